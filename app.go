@@ -2,9 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 
 	"rename/internal/domain"
 	"rename/internal/repository"
@@ -17,16 +22,20 @@ import (
 // App struct - Presentation layer (thin adapter)
 // Following DIP (Dependency Inversion Principle) - depends on abstractions (use cases)
 type App struct {
-	ctx                    context.Context
-	renameUseCase          *usecase.RenameUseCase
-	historyUseCase         *usecase.HistoryUseCase
-	currentFiles           []*domain.File
-	currentStrategy        domain.RenameStrategy
-	currentPattern         string
-	currentReplacement     string
-	currentIsRegex         bool
-	currentCaseInsensitive bool
-	initialFiles           []string // Files passed on startup via command-line
+	ctx             context.Context
+	renameUseCase   *usecase.RenameUseCase
+	historyUseCase  *usecase.HistoryUseCase
+	journalUseCase  *usecase.JournalUseCase
+	folderUseCase   *usecase.FolderUseCase
+	currentFiles    []*domain.File
+	currentStrategy domain.RenameStrategy
+	currentEntry    *domain.HistoryEntry  // Pattern info saved to history on success (nil if not applicable)
+	initialFiles    []string              // Files passed on startup via command-line
+	normalizeMatch  bool                  // Patterns are matched in NFC (see SetNormalizedMatching)
+	blocking        []usecase.PreviewItem // Files of the last preview whose status prevents executing
+	mu              sync.Mutex            // Serializes calls using the selection and settings (Wails runs bound methods concurrently)
+	cancelMu        sync.Mutex            // Guards cancelRename (not mu, which ExecuteRename holds)
+	cancelRename    context.CancelFunc    // Stops the running ExecuteRename (nil when idle)
 }
 
 // NewApp creates a new App application struct with dependency injection
@@ -35,33 +44,59 @@ func NewApp() *App {
 	fileSystem := service.NewFileSystemService()
 
 	// Get config path
-	homeDir, _ := os.UserHomeDir()
-	configPath := filepath.Join(homeDir, ".config", "rename", "config.json")
+	configPath := filepath.Join(configDir(), "config.json")
 
 	historyRepo := repository.NewJSONHistoryRepository(configPath)
+	journalRepo := repository.NewJSONJournalRepository(journalPath())
 
 	// Initialize use cases
 	renameUseCase := usecase.NewRenameUseCase(fileSystem)
 	historyUseCase := usecase.NewHistoryUseCase(historyRepo)
+	journalUseCase := usecase.NewJournalUseCase(journalRepo, fileSystem)
+	folderUseCase := usecase.NewFolderUseCase(fileSystem)
+	renameUseCase.SetMetadataProvider(service.NewMetadataService())
+	renameUseCase.SetContentHasher(service.NewHashService())
+	renameUseCase.SetTrash(service.NewTrashService())
 
 	return &App{
 		renameUseCase:  renameUseCase,
 		historyUseCase: historyUseCase,
+		journalUseCase: journalUseCase,
+		folderUseCase:  folderUseCase,
 		currentFiles:   make([]*domain.File, 0),
 	}
 }
 
+// configDir returns the directory holding history and the undo journal
+func configDir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".config", "rename")
+}
+
+// journalPath returns the undo journal path (next to config.json)
+func journalPath() string {
+	return filepath.Join(configDir(), "journal.json")
+}
+
 // startup is called when the app starts
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
 
+	// Conflicts under the prompt policy are asked via a native dialog
+	a.renameUseCase.SetConflictPrompt(a.promptConflict)
+
+	// Reading EXIF or hashing a large folder reports progress to the frontend
+	a.renameUseCase.SetProgressHandler(func(done, total int) {
+		runtime.EventsEmit(a.ctx, "preview:progress", PreviewProgress{Done: done, Total: total})
+	})
+	a.renameUseCase.SetRenameProgressHandler(func(done, total int, path string) {
+		runtime.EventsEmit(a.ctx, "rename:progress", RenameProgress{Done: done, Total: total, Path: path})
+	})
+
 	// If initial files were provided via command-line, load them into currentFiles
 	// Frontend will retrieve them via GetInitialFiles() after mounting
 	if len(a.initialFiles) > 0 {
-		a.currentFiles = make([]*domain.File, len(a.initialFiles))
-		for i, path := range a.initialFiles {
-			a.currentFiles[i] = domain.NewFile(path)
-		}
+		a.currentFiles = a.folderUseCase.NewFiles(a.initialFiles)
 	}
 }
 
@@ -75,97 +110,381 @@ func (a *App) SelectFiles() ([]string, error) {
 	}
 
 	// Convert to File entities
-	a.currentFiles = make([]*domain.File, len(files))
+	selected := make([]*domain.File, len(files))
 	for i, path := range files {
-		a.currentFiles[i] = domain.NewFile(path)
+		selected[i] = domain.NewFile(path)
 	}
+	a.setCurrentFiles(selected)
 
 	return files, nil
 }
 
+// setCurrentFiles replaces the current files
+// Cached stats are dropped since the files may have changed since they were last previewed
+func (a *App) setCurrentFiles(files []*domain.File) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.currentFiles = files
+	a.blocking = nil
+	a.renameUseCase.ClearStatCache()
+}
+
+// SelectFolder opens folder selection dialog and returns the chosen folder ("" if cancelled)
+// The frontend then calls LoadFolder with the filter options
+func (a *App) SelectFolder() (string, error) {
+	return runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "フォルダを選択",
+	})
+}
+
+// FolderLoad represents the files found by LoadFolder for frontend
+type FolderLoad struct {
+	Paths   []string `json:"paths"`
+	Skipped []string `json:"skipped,omitempty"` // Subfolders that could not be read, with the reason
+}
+
+// LoadFolder walks root with the given filters and makes the found files the current files
+func (a *App) LoadFolder(root string, options domain.FolderOptions) (*FolderLoad, error) {
+	files, skipped, err := a.folderUseCase.Collect(root, options)
+	if err != nil {
+		return nil, err
+	}
+
+	a.setCurrentFiles(files)
+	load := &FolderLoad{Paths: make([]string, len(files))}
+	for i, file := range files {
+		load.Paths[i] = file.OriginalPath()
+	}
+	for _, err := range skipped {
+		load.Skipped = append(load.Skipped, err.Error())
+	}
+
+	return load, nil
+}
+
 // FilePreview represents a file preview for frontend
 type FilePreview struct {
-	OriginalPath string `json:"originalPath"`
-	OriginalName string `json:"originalName"`
-	NewName      string `json:"newName"`
-	HasChanged   bool   `json:"hasChanged"`
+	OriginalPath   string `json:"originalPath"`
+	OriginalName   string `json:"originalName"`
+	NewName        string `json:"newName"`
+	HasChanged     bool   `json:"hasChanged"`
+	IsDir          bool   `json:"isDir"`
+	ResolvedName   string `json:"resolvedName"`   // Final name after conflict resolution
+	Conflict       bool   `json:"conflict"`       // Target name already exists
+	ConflictAction string `json:"conflictAction"` // How the conflict will be handled
+	// Metadata fields used by a template rule (e.g. "exif.date"), shown next to the original name
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Duplicate contents: the earlier file this one duplicates, or the later files duplicating this one
+	DuplicateOf     string   `json:"duplicateOf,omitempty"`
+	Duplicates      []string `json:"duplicates,omitempty"`
+	DuplicateAction string   `json:"duplicateAction,omitempty"` // skip, trash or shared (keep only flags)
+	// Why the resolved name is invalid on the target platforms (see SetTargetPlatforms)
+	Issues []domain.NameIssue `json:"issues,omitempty"`
+	// Original paths of all selected files mapping to the same new name (resolved by the conflict policy)
+	Collisions []string `json:"collisions,omitempty"`
+	// will-rename, unchanged, collides-with-existing, collides-within-batch, invalid-name,
+	// source-missing or permission-denied; the last three block ExecuteRename
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"` // Explanation of the status
 }
 
 // GeneratePreview generates rename preview
 func (a *App) GeneratePreview(pattern, replacement string, isRegex, caseInsensitive bool) ([]FilePreview, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	if len(a.currentFiles) == 0 {
 		return []FilePreview{}, nil
 	}
 
+	// Create strategy
+	strategy, err := domain.NewPatternStrategy(pattern, replacement, isRegex, caseInsensitive, a.normalizeMatch)
+	if err != nil {
+		return nil, err
+	}
+
 	// Save current pattern info for later use
-	a.currentPattern = pattern
-	a.currentReplacement = replacement
-	a.currentIsRegex = isRegex
-	a.currentCaseInsensitive = caseInsensitive
+	a.currentEntry = &domain.HistoryEntry{
+		Pattern:         pattern,
+		Replacement:     replacement,
+		IsRegex:         isRegex,
+		CaseInsensitive: caseInsensitive,
+		Normalize:       a.normalizeMatch,
+	}
 
-	// Create strategy
-	var strategy domain.RenameStrategy
-	var err error
+	return a.preview(strategy), nil
+}
+
+// SetNormalizedMatching toggles Unicode-insensitive matching for GeneratePreview and GenerateNumberedPreview
+// When enabled, the pattern matches names regardless of NFC/NFD (e.g. Japanese names from Finder)
+func (a *App) SetNormalizedMatching(enabled bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.normalizeMatch = enabled
+}
+
+// GenerateNumberedPreview generates rename preview with sequence numbers
+// The pattern (if any) is applied first, then the number is inserted
+func (a *App) GenerateNumberedPreview(pattern, replacement string, isRegex, caseInsensitive bool, numbering domain.NumberingOptions) ([]FilePreview, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.currentFiles) == 0 {
+		return []FilePreview{}, nil
+	}
 
-	if isRegex {
-		strategy, err = domain.NewRegexMatchStrategy(pattern, replacement)
+	var base domain.RenameStrategy
+	if pattern != "" {
+		var err error
+		base, err = domain.NewPatternStrategy(pattern, replacement, isRegex, caseInsensitive, a.normalizeMatch)
 		if err != nil {
 			return nil, err
 		}
-	} else {
-		strategy = domain.NewExactMatchStrategy(pattern, replacement)
 	}
 
-	// Apply case-insensitive if needed
-	if caseInsensitive {
-		strategy = domain.NewCaseInsensitiveStrategy(strategy)
+	strategy, err := domain.NewNumberingStrategy(base, numbering)
+	if err != nil {
+		return nil, err
+	}
+
+	// Numbering is not part of the saved history entries
+	a.currentEntry = nil
+
+	return a.preview(strategy), nil
+}
+
+// PreviewProgress is sent as the "preview:progress" event while file contents are read
+type PreviewProgress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+// RenameProgress is sent as the "rename:progress" event after each file of ExecuteRename
+type RenameProgress struct {
+	Done  int    `json:"done"`
+	Total int    `json:"total"`
+	Path  string `json:"path"` // Original path of the file just processed
+}
+
+// TemplateValidation is the result of ValidateTemplate
+type TemplateValidation struct {
+	Valid   bool   `json:"valid"`
+	Column  int    `json:"column"` // 1-based column of a syntax error (0 when the pattern is invalid)
+	Message string `json:"message"`
+}
+
+// ValidateTemplate checks a template rule while the user types, reporting where the error is
+// pattern is the optional regular expression providing {1}, {2}... groups
+func (a *App) ValidateTemplate(template, pattern string, caseInsensitive bool) TemplateValidation {
+	_, err := domain.RuleConfig{
+		Type:            domain.RuleTemplate,
+		Template:        template,
+		Pattern:         pattern,
+		CaseInsensitive: caseInsensitive,
+	}.Build()
+	if err == nil {
+		return TemplateValidation{Valid: true}
+	}
+
+	var templateErr *domain.TemplateError
+	if errors.As(err, &templateErr) {
+		return TemplateValidation{Column: templateErr.Column, Message: templateErr.Message}
+	}
+	return TemplateValidation{Message: err.Error()}
+}
+
+// GeneratePipelinePreview generates rename preview for an ordered list of rules
+// Disabled rules are skipped; the whole pipeline is saved to history on success
+func (a *App) GeneratePipelinePreview(rules []domain.RuleConfig) ([]FilePreview, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.currentFiles) == 0 {
+		return []FilePreview{}, nil
 	}
 
+	strategy, err := domain.NewPipelineFromConfig(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	a.currentEntry = &domain.HistoryEntry{
+		Rules: rules,
+	}
+
+	return a.preview(strategy), nil
+}
+
+// SortFiles reorders the selected files (this is also the numbering order)
+// order is one of selection, name, natural, mtime, size
+func (a *App) SortFiles(order string, descending bool) ([]string, error) {
+	fileOrder, err := domain.ParseFileOrder(order)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := a.renameUseCase.SortFiles(a.currentFiles, fileOrder, descending); err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, len(a.currentFiles))
+	for i, file := range a.currentFiles {
+		paths[i] = file.OriginalPath()
+	}
+	return paths, nil
+}
+
+// preview stores strategy as current and converts the use case preview for the frontend
+func (a *App) preview(strategy domain.RenameStrategy) []FilePreview {
 	a.currentStrategy = strategy
 
 	// Generate preview
-	files := a.renameUseCase.GeneratePreview(a.currentFiles, strategy)
+	items := a.renameUseCase.Preview(a.currentFiles, strategy)
+	a.blocking = usecase.BlockingItems(items)
 
 	// Convert to preview
-	previews := make([]FilePreview, len(files))
-	for i, file := range files {
+	previews := make([]FilePreview, len(items))
+	for i, item := range items {
 		previews[i] = FilePreview{
-			OriginalPath: file.OriginalPath(),
-			OriginalName: file.OriginalName(),
-			NewName:      file.NewName(),
-			HasChanged:   file.HasChanged(),
+			OriginalPath:    item.File.OriginalPath(),
+			OriginalName:    item.File.OriginalName(),
+			NewName:         item.File.NewName(),
+			HasChanged:      item.File.HasChanged(),
+			IsDir:           item.File.IsDir(),
+			ResolvedName:    item.ResolvedName,
+			Conflict:        item.Conflict,
+			ConflictAction:  string(item.Action),
+			Metadata:        item.Metadata.Strings(),
+			DuplicateOf:     item.DuplicateOf,
+			Duplicates:      item.Duplicates,
+			DuplicateAction: string(item.DuplicateAction),
+			Issues:          item.Issues,
+			Collisions:      item.Collisions,
+			Status:          string(item.Status),
+			Detail:          item.Detail,
 		}
 	}
 
-	return previews, nil
+	return previews
+}
+
+// SetConflictOptions sets how existing target names are handled
+func (a *App) SetConflictOptions(options domain.ConflictOptions) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.renameUseCase.SetConflictOptions(options)
+}
+
+// SetDuplicateOptions sets how files with identical contents are flagged and handled
+func (a *App) SetDuplicateOptions(options domain.DuplicateOptions) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.renameUseCase.SetDuplicateOptions(options)
+}
+
+// SetTransactional makes ExecuteRename all or nothing: a failed rename reverts the renames already done
+func (a *App) SetTransactional(enabled bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.renameUseCase.SetTransactional(enabled)
+}
+
+// SetTargetPlatforms sets the platforms ("macos", "windows", "linux", "fat") new names are checked against
+func (a *App) SetTargetPlatforms(platforms []domain.Platform) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.renameUseCase.SetTargetPlatforms(platforms)
+}
+
+// promptConflict asks the user how to handle an existing target name
+func (a *App) promptConflict(file *domain.File, existingPath string) domain.ConflictPolicy {
+	const (
+		skipButton      = "スキップ"
+		overwriteButton = "上書き"
+		suffixButton    = "番号を付ける"
+	)
+
+	selection, err := runtime.MessageDialog(a.ctx, runtime.MessageDialogOptions{
+		Type:          runtime.QuestionDialog,
+		Title:         "ファイル名の衝突",
+		Message:       fmt.Sprintf("%s は既に存在します。%s をどうしますか？", filepath.Base(existingPath), file.OriginalName()),
+		Buttons:       []string{skipButton, overwriteButton, suffixButton},
+		DefaultButton: skipButton,
+		CancelButton:  skipButton,
+	})
+	if err != nil {
+		return domain.ConflictSkip
+	}
+
+	switch selection {
+	case overwriteButton:
+		return domain.ConflictOverwrite
+	case suffixButton:
+		return domain.ConflictSuffix
+	}
+	return domain.ConflictSkip
 }
 
 // ExecuteRename executes the rename operation
 func (a *App) ExecuteRename() (usecase.RenameResult, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	if a.currentStrategy == nil {
 		return usecase.RenameResult{}, nil
 	}
+	// The frontend disables the button, but a stale preview must not slip through either
+	if len(a.blocking) > 0 {
+		return usecase.RenameResult{}, fmt.Errorf("%d file(s) cannot be renamed: %s", len(a.blocking), a.blocking[0].Detail)
+	}
+	// Files deleted since the preview are caught before anything is renamed
+	if missing := a.renameUseCase.MissingSources(a.currentFiles); len(missing) > 0 {
+		return usecase.RenameResult{}, fmt.Errorf("%d file(s) no longer exist: %s", len(missing), missing[0].OriginalPath())
+	}
 
-	result := a.renameUseCase.Execute(a.currentFiles)
+	// CancelRename does not take mu, so it can stop the batch between files
+	parent := a.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithCancel(parent)
+	a.cancelMu.Lock()
+	a.cancelRename = cancel
+	a.cancelMu.Unlock()
+	defer func() {
+		a.cancelMu.Lock()
+		a.cancelRename = nil
+		a.cancelMu.Unlock()
+		cancel()
+	}()
+
+	result := a.renameUseCase.Execute(ctx, a.currentFiles)
 
 	// Update currentFiles with new paths after rename
 	if len(result.NewFilePaths) > 0 {
-		a.currentFiles = make([]*domain.File, len(result.NewFilePaths))
 		for i, path := range result.NewFilePaths {
-			a.currentFiles[i] = domain.NewFile(path)
+			a.currentFiles[i] = movedFile(a.currentFiles[i], path)
 		}
 	}
+	// Trashed duplicates are no longer part of the selection
+	if len(result.TrashedPaths) > 0 {
+		trashed := make(map[string]bool, len(result.TrashedPaths))
+		for _, path := range result.TrashedPaths {
+			trashed[path] = true
+		}
+		a.currentFiles = slices.DeleteFunc(a.currentFiles, func(file *domain.File) bool {
+			return trashed[file.OriginalPath()]
+		})
+	}
+
+	// Record performed renames so they can be undone (log error but don't fail the operation)
+	if err := a.journalUseCase.Record(result.Operations); err != nil {
+		log.Printf("Warning: Failed to save undo journal: %v", err)
+	}
 
 	// If successful, add to history
-	if result.SuccessCount > 0 {
-		entry := domain.HistoryEntry{
-			Pattern:         a.currentPattern,
-			Replacement:     a.currentReplacement,
-			IsRegex:         a.currentIsRegex,
-			CaseInsensitive: a.currentCaseInsensitive,
-		}
+	if result.SuccessCount > 0 && a.currentEntry != nil {
 		// Save to history (log error but don't fail the operation)
-		if err := a.historyUseCase.AddEntry(entry); err != nil {
+		if err := a.historyUseCase.AddEntry(*a.currentEntry); err != nil {
 			log.Printf("Warning: Failed to save history: %v", err)
 		}
 	}
@@ -173,6 +492,48 @@ func (a *App) ExecuteRename() (usecase.RenameResult, error) {
 	return result, nil
 }
 
+// UndoLastRename reverts the last count executed rename batches
+func (a *App) UndoLastRename(count int) (usecase.UndoResult, error) {
+	if count < 1 {
+		count = 1
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	result, err := a.journalUseCase.Undo(count)
+	if err != nil {
+		return result, err
+	}
+
+	// Point current files back to their restored paths (including files inside restored folders)
+	for _, op := range result.Restored {
+		for i, file := range a.currentFiles {
+			path := file.OriginalPath()
+			if path == op.NewPath {
+				a.currentFiles[i] = movedFile(file, op.OldPath)
+			} else if strings.HasPrefix(path, op.NewPath+string(filepath.Separator)) {
+				a.currentFiles[i] = movedFile(file, op.OldPath+path[len(op.NewPath):])
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// movedFile returns a fresh entity for file at its new path, keeping whether it is a directory
+func movedFile(file *domain.File, path string) *domain.File {
+	if file.IsDir() {
+		return domain.NewDirectory(path)
+	}
+	return domain.NewFile(path)
+}
+
+// GetRenameJournal returns the executed rename batches that can be undone
+func (a *App) GetRenameJournal() ([]domain.RenameBatch, error) {
+	return a.journalUseCase.GetBatches()
+}
+
 // GetHistory returns rename history
 func (a *App) GetHistory() ([]domain.HistoryEntry, error) {
 	return a.historyUseCase.GetHistory()
@@ -183,6 +544,16 @@ func (a *App) AddToHistory(entry domain.HistoryEntry) error {
 	return a.historyUseCase.AddEntry(entry)
 }
 
+// CancelRename stops the running ExecuteRename after the file in progress
+// ExecuteRename then returns what was done, with Cancelled set (nothing happens when idle)
+func (a *App) CancelRename() {
+	a.cancelMu.Lock()
+	defer a.cancelMu.Unlock()
+	if a.cancelRename != nil {
+		a.cancelRename()
+	}
+}
+
 // SetInitialFiles sets files passed via command-line on startup
 func (a *App) SetInitialFiles(files []string) {
 	a.initialFiles = files
@@ -196,10 +567,7 @@ func (a *App) GetInitialFiles() []string {
 // LoadFilesFromSecondInstance loads files when a second instance is launched
 func (a *App) LoadFilesFromSecondInstance(files []string) {
 	// Convert to File entities
-	a.currentFiles = make([]*domain.File, len(files))
-	for i, path := range files {
-		a.currentFiles[i] = domain.NewFile(path)
-	}
+	a.setCurrentFiles(a.folderUseCase.NewFiles(files))
 
 	// Show the existing window
 	runtime.WindowShow(a.ctx)
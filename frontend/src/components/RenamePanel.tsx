@@ -1,7 +1,7 @@
 'use client';
 
 import { useState, useEffect, useRef, useCallback } from 'react';
-import { SelectFiles, GeneratePreview, ExecuteRename, GetHistory, GetInitialFiles } from '../../wailsjs/go/main/App';
+import { SelectFiles, GeneratePreview, ExecuteRename, GetHistory, GetInitialFiles, SetConflictOptions } from '../../wailsjs/go/main/App';
 import { EventsOn } from '../../wailsjs/runtime/runtime';
 import { main, domain } from '../../wailsjs/go/models';
 
@@ -13,12 +13,38 @@ type HistoryEntry = domain.HistoryEntry;
 const PREVIEW_DEBOUNCE_MS = 300;
 const MAX_HISTORY_DISPLAY = 10;
 
+// How an existing file with the new name is handled (see domain.ConflictPolicy)
+const CONFLICT_POLICIES = [
+  { value: 'suffix', label: '番号を付ける' },
+  { value: 'skip', label: 'スキップ' },
+  { value: 'overwrite', label: '上書き' },
+  { value: 'fail', label: '一括中止' },
+  { value: 'prompt', label: '実行時に確認' },
+];
+
+// Statuses that prevent ExecuteRename (see FilePreview.status)
+const BLOCKING_STATUSES = ['invalid-name', 'source-missing', 'permission-denied'];
+
+const isBlocking = (preview: FilePreview) => BLOCKING_STATUSES.includes(preview.status);
+
+// Row background for each preview status
+const rowClassName = (preview: FilePreview) => {
+  if (isBlocking(preview)) {
+    return 'bg-destructive/10';
+  }
+  if (preview.status === 'collides-with-existing' || preview.status === 'collides-within-batch') {
+    return 'bg-accent/15';
+  }
+  return preview.hasChanged ? 'bg-accent/5' : '';
+};
+
 export default function RenamePanel() {
   const [selectedFiles, setSelectedFiles] = useState<string[]>([]);
   const [pattern, setPattern] = useState('');
   const [replacement, setReplacement] = useState('');
   const [isRegex, setIsRegex] = useState(false);
   const [caseInsensitive, setCaseInsensitive] = useState(false);
+  const [conflictPolicy, setConflictPolicy] = useState('suffix');
   const [previews, setPreviews] = useState<FilePreview[]>([]);
   const [history, setHistory] = useState<HistoryEntry[]>([]);
   const [loading, setLoading] = useState(false);
@@ -55,7 +81,7 @@ export default function RenamePanel() {
     if (selectedFiles.length > 0) {
       generatePreviewDebounced();
     }
-  }, [pattern, replacement, isRegex, caseInsensitive, selectedFiles]);
+  }, [pattern, replacement, isRegex, caseInsensitive, conflictPolicy, selectedFiles]);
 
   const loadHistory = async () => {
     try {
@@ -89,10 +115,15 @@ export default function RenamePanel() {
         }
 
         try {
+          // The policy is kept by the backend, so ExecuteRename writes the names previewed here
+          await SetConflictOptions(domain.ConflictOptions.createFrom({ policy: conflictPolicy, suffixTemplate: '{n}', suffixStart: 1 }));
           const result = await GeneratePreview(pattern, replacement, isRegex, caseInsensitive);
           setPreviews(result || []);
           const changedCount = result?.filter(p => p.hasChanged).length || 0;
-          if (changedCount > 0) {
+          const blockingCount = result?.filter(isBlocking).length || 0;
+          if (blockingCount > 0) {
+            setMessage(`${blockingCount}個のファイルはリネームできません`);
+          } else if (changedCount > 0) {
             setMessage(`${changedCount}個のファイルが変更されます`);
           }
         } catch (err: any) {
@@ -101,7 +132,7 @@ export default function RenamePanel() {
         }
       }, PREVIEW_DEBOUNCE_MS);
     };
-  })(), [selectedFiles, pattern, replacement, isRegex, caseInsensitive]);
+  })(), [selectedFiles, pattern, replacement, isRegex, caseInsensitive, conflictPolicy]);
 
   const handleExecuteRename = async () => {
     if (previews.length === 0) {
@@ -115,6 +146,12 @@ export default function RenamePanel() {
       return;
     }
 
+    const blockingCount = previews.filter(isBlocking).length;
+    if (blockingCount > 0) {
+      setMessage(`${blockingCount}個のファイルはリネームできません`);
+      return;
+    }
+
     setLoading(true);
     try {
       const result = await ExecuteRename();
@@ -259,10 +296,32 @@ export default function RenamePanel() {
               </label>
             </div>
 
+            {/* Conflict Policy */}
+            <div>
+              <label className="block text-sm font-medium mb-2 text-foreground">
+                同じ名前のファイルがある場合
+              </label>
+              <select
+                value={conflictPolicy}
+                onChange={(e) => {
+                  // The old preview no longer shows the names that would be written
+                  setPreviews([]);
+                  setConflictPolicy(e.target.value);
+                }}
+                className="w-full px-3 py-2 border rounded bg-background text-foreground focus:outline-none focus:ring-2 focus:ring-accent"
+              >
+                {CONFLICT_POLICIES.map((policy) => (
+                  <option key={policy.value} value={policy.value}>
+                    {policy.label}
+                  </option>
+                ))}
+              </select>
+            </div>
+
             {/* Execute Button */}
             <button
               onClick={handleExecuteRename}
-              disabled={loading || previews.length === 0 || previews.filter(p => p.hasChanged).length === 0}
+              disabled={loading || previews.length === 0 || previews.filter(p => p.hasChanged).length === 0 || previews.some(isBlocking)}
               className="w-full px-4 py-3 bg-destructive text-accent-foreground rounded hover:bg-destructive/90 transition disabled:opacity-50 disabled:cursor-not-allowed font-medium"
             >
               リネーム実行
@@ -298,18 +357,29 @@ export default function RenamePanel() {
                   previews.map((preview, index) => (
                     <tr
                       key={index}
-                      className={`border-t ${
-                        preview.hasChanged ? 'bg-accent/5' : ''
-                      }`}
+                      className={`border-t ${rowClassName(preview)}`}
+                      title={preview.detail}
                     >
                       <td className="px-4 py-2 text-sm text-foreground">
                         {preview.originalName}
                       </td>
                       <td className="px-4 py-2 text-sm text-foreground font-medium">
-                        {preview.newName}
+                        {preview.resolvedName || preview.newName}
+                        {preview.resolvedName && preview.resolvedName !== preview.newName && (
+                          <div className="text-xs font-normal text-muted-foreground">
+                            {preview.newName}（{CONFLICT_POLICIES.find(p => p.value === preview.conflictAction)?.label ?? preview.conflictAction}）
+                          </div>
+                        )}
+                        {preview.detail && (
+                          <div className={`text-xs font-normal ${isBlocking(preview) ? 'text-destructive' : 'text-muted-foreground'}`}>
+                            {preview.detail}
+                          </div>
+                        )}
                       </td>
                       <td className="px-4 py-2 text-sm text-center">
-                        {preview.hasChanged ? (
+                        {isBlocking(preview) ? (
+                          <span className="text-destructive">✕</span>
+                        ) : preview.hasChanged ? (
                           <span className="text-success">✓</span>
                         ) : (
                           <span className="text-muted-foreground">-</span>
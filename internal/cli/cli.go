@@ -0,0 +1,604 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+
+	"rename/internal/domain"
+	"rename/internal/usecase"
+)
+
+// Exit codes returned by Run
+const (
+	ExitOK      = 0 // All renames succeeded (or nothing to do)
+	ExitFailure = 1 // At least one rename failed
+	ExitUsage   = 2 // Invalid arguments or pattern
+)
+
+// CLI is the headless presentation layer (thin adapter like App)
+// Following DIP - depends on the same use cases as the GUI
+type CLI struct {
+	renameUseCase  *usecase.RenameUseCase
+	journalUseCase *usecase.JournalUseCase
+	folderUseCase  *usecase.FolderUseCase
+	stdout         io.Writer
+	stderr         io.Writer
+}
+
+// NewCLI creates a new CLI with dependency injection
+func NewCLI(renameUseCase *usecase.RenameUseCase, journalUseCase *usecase.JournalUseCase, folderUseCase *usecase.FolderUseCase, stdout, stderr io.Writer) *CLI {
+	return &CLI{
+		renameUseCase:  renameUseCase,
+		journalUseCase: journalUseCase,
+		folderUseCase:  folderUseCase,
+		stdout:         stdout,
+		stderr:         stderr,
+	}
+}
+
+// IsCommand reports whether the command-line arguments start with a CLI subcommand
+// Anything else is treated as a list of files for the GUI
+func IsCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	switch args[0] {
+	case "apply", "undo":
+		return true
+	}
+	return false
+}
+
+// Run executes the subcommand in args and returns the process exit code
+func (c *CLI) Run(args []string) int {
+	if !IsCommand(args) {
+		fmt.Fprintln(c.stderr, "usage: rename apply --pattern X --replace Y [--regex] [--ignore-case] [--normalize-match] [--scope SCOPE] [--template TEMPLATE] [--normalize FORM] [--convert LIST] [--transliterate] [--sanitize] [--platforms LIST] [--hash ALGORITHM] [--duplicates ACTION] [--case STYLE] [--on-conflict POLICY] [--transactional] [--number POSITION] [--rules FILE] [--sort ORDER] [--dir DIR [--max-depth N] [--include GLOB] [--exclude GLOB] [--hidden] [--gitignore] [--targets KIND]] [--dry-run] [--json] files...")
+		fmt.Fprintln(c.stderr, "       rename undo [-n N] [--json]")
+		return ExitUsage
+	}
+
+	switch args[0] {
+	case "apply":
+		return c.runApply(args[1:])
+	case "undo":
+		return c.runUndo(args[1:])
+	}
+	return ExitUsage
+}
+
+// stringList is a flag that can be given multiple times
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// previewItem is the JSON representation of a single file preview
+type previewItem struct {
+	OriginalPath   string             `json:"originalPath"`
+	NewPath        string             `json:"newPath"`
+	HasChanged     bool               `json:"hasChanged"`
+	IsDir          bool               `json:"isDir,omitempty"`
+	Conflict       bool               `json:"conflict"`
+	ConflictAction string             `json:"conflictAction,omitempty"`
+	Metadata       map[string]string  `json:"metadata,omitempty"`
+	DuplicateOf    string             `json:"duplicateOf,omitempty"`
+	Duplicates     []string           `json:"duplicates,omitempty"`
+	Issues         []domain.NameIssue `json:"issues,omitempty"`
+	Collisions     []string           `json:"collisions,omitempty"`
+	Status         domain.FileStatus  `json:"status"`
+	Detail         string             `json:"detail,omitempty"`
+}
+
+// applyOutput is the JSON document printed by apply --json
+type applyOutput struct {
+	Preview []previewItem `json:"preview"`
+	Blocked bool          `json:"blocked,omitempty"` // Some files have error statuses, so nothing was renamed
+	Result  *resultOutput `json:"result,omitempty"`
+}
+
+// resultOutput is the JSON representation of usecase.RenameResult
+type resultOutput struct {
+	SuccessCount int      `json:"successCount"`
+	FailureCount int      `json:"failureCount"`
+	SkippedCount int      `json:"skippedCount"`
+	TrashedCount int      `json:"trashedCount"`
+	Aborted      bool     `json:"aborted"`
+	Cancelled    bool     `json:"cancelled,omitempty"`
+	Errors       []string `json:"errors"`
+	NewFilePaths []string `json:"newFilePaths"`
+	// Set with --transactional when a failure reverted the batch
+	RolledBack      bool     `json:"rolledBack,omitempty"`
+	RolledBackCount int      `json:"rolledBackCount,omitempty"`
+	RollbackErrors  []string `json:"rollbackErrors,omitempty"`
+}
+
+// printTemplateCaret points at the column of a template syntax error
+func printTemplateCaret(w io.Writer, template string, err error) {
+	var templateErr *domain.TemplateError
+	if !errors.As(err, &templateErr) {
+		return
+	}
+	fmt.Fprintf(w, "  %s\n  %s^\n", template, strings.Repeat(" ", templateErr.Column-1))
+}
+
+// runApply handles: rename apply --pattern X --replace Y [--regex] [--ignore-case] [--on-conflict POLICY] files...
+func (c *CLI) runApply(args []string) int {
+	flags := flag.NewFlagSet("apply", flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	pattern := flags.String("pattern", "", "search pattern (required unless case, numbering or rules)")
+	replacement := flags.String("replace", "", "replacement string")
+	isRegex := flags.Bool("regex", false, "treat pattern as a regular expression")
+	caseInsensitive := flags.Bool("ignore-case", false, "match case-insensitively")
+	normalizeMatch := flags.Bool("normalize-match", false, "match --pattern regardless of Unicode composition (NFC/NFD)")
+	scope := flags.String("scope", string(domain.ScopeFull), "part matched by --pattern: stem, extension or full")
+	conflictPolicy := flags.String("on-conflict", string(domain.ConflictSuffix), "existing target handling: suffix, skip, fail or overwrite")
+	suffixTemplate := flags.String("suffix-template", "{n}", "counter format for the suffix policy, e.g. \" ({n})\" or \"_{n:03}\"")
+	suffixStart := flags.Int("suffix-start", 1, "first counter value for the suffix policy")
+	template := flags.String("template", "", "build names from fields, e.g. \"{exif.date:2006-01-02_150405}_{exif.model}\" or \"{parent|slug}_{index:03}\" (--pattern then provides {1}, {2}... groups)")
+	normalizeForm := flags.String("normalize", "", "convert names to a Unicode normalization form: nfc, nfd, nfkc or nfkd")
+	normalizeScope := flags.String("normalize-scope", string(domain.ScopeFull), "part converted by --normalize: stem, extension or full")
+	conversions := flags.String("convert", "", "comma-separated character conversions: halfwidth, fullwidth, kana-fullwidth, hiragana, katakana, kanji-numbers")
+	convertScope := flags.String("convert-scope", string(domain.ScopeStem), "part converted by --convert: stem, extension or full")
+	sanitize := flags.Bool("sanitize", false, "fix names that are invalid on the --platforms (reserved characters, device names, length)")
+	sanitizeReplacement := flags.String("sanitize-replacement", domain.DefaultFallback, "replacement for invalid characters with --sanitize")
+	platforms := flags.String("platforms", string(domain.PlatformFor(runtime.GOOS)), "platforms new names must be valid on: macos, windows, linux, fat or all")
+	transliterate := flags.Bool("transliterate", false, "convert names to ASCII: kana to romaji, Cyrillic and Greek to Latin, accents removed")
+	fallback := flags.String("transliterate-fallback", domain.DefaultFallback, "replacement for characters without ASCII equivalent (e.g. kanji); empty drops them")
+	hashAlgorithm := flags.String("hash", "", "name files by content digest: sha256, sha1, md5 or xxh64")
+	hashLength := flags.Int("hash-length", 0, "hex digits of the digest to keep (0 = all)")
+	hashKeepStem := flags.Bool("hash-keep-stem", false, "keep the original name before the digest")
+	hashSeparator := flags.String("hash-separator", "_", "separator between name and digest (with --hash-keep-stem)")
+	transactional := flags.Bool("transactional", false, "all or nothing: if a rename fails, revert the renames already done")
+	duplicateAction := flags.String("duplicates", "", "handle files with identical contents: keep (only report), skip, trash or shared")
+	duplicateHash := flags.String("duplicates-hash", string(domain.HashSHA256), "digest used to compare contents with --duplicates")
+	caseStyle := flags.String("case", "", "convert case: lower, upper, title, camel, pascal, snake or kebab")
+	caseScope := flags.String("case-scope", string(domain.ScopeStem), "part converted by --case: stem, extension or full")
+	numberPosition := flags.String("number", "", "add sequence numbers: prefix, suffix or placeholder ({n} in the name)")
+	numberStart := flags.Int("number-start", 1, "first sequence number")
+	numberStep := flags.Int("number-step", 1, "sequence number increment")
+	numberPadding := flags.Int("number-padding", 0, "minimum digits of sequence numbers (zero padded)")
+	numberSeparator := flags.String("number-separator", "", "separator between number and name")
+	rulesPath := flags.String("rules", "", "JSON file with a pipeline of rules (replaces --pattern, --template, --normalize, --convert, --transliterate, --hash, --case, --number and --sanitize)")
+	sortOrder := flags.String("sort", "", "file order: selection, name, natural, mtime or size")
+	reverse := flags.Bool("reverse", false, "reverse the file order")
+	dryRun := flags.Bool("dry-run", false, "print the preview without renaming")
+	jsonOutput := flags.Bool("json", false, "print results as JSON")
+	var dirs, include, exclude stringList
+	flags.Var(&dirs, "dir", "add the files of a folder (repeatable)")
+	maxDepth := flags.Int("max-depth", 0, "folder depth for --dir (0 = unlimited, 1 = no subfolders)")
+	flags.Var(&include, "include", "only add folder files matching this glob (repeatable)")
+	flags.Var(&exclude, "exclude", "skip folder files and subfolders matching this glob (repeatable)")
+	filterRegex := flags.Bool("filter-regex", false, "treat --include and --exclude as regular expressions")
+	hidden := flags.Bool("hidden", false, "include hidden files and folders")
+	gitignore := flags.Bool("gitignore", false, "skip paths ignored by .gitignore files")
+	targets := flags.String("targets", string(domain.TargetFiles), "what --dir adds: files, directories or both")
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		return ExitUsage
+	}
+
+	ruleFlags := *pattern != "" || *template != "" || *normalizeForm != "" || *conversions != "" || *transliterate || *sanitize || *hashAlgorithm != "" || *caseStyle != "" || *numberPosition != ""
+	if !ruleFlags && *rulesPath == "" {
+		fmt.Fprintln(c.stderr, "Error: --pattern is required")
+		return ExitUsage
+	}
+	if *rulesPath != "" && ruleFlags {
+		fmt.Fprintln(c.stderr, "Error: --rules cannot be combined with --pattern, --template, --normalize, --convert, --transliterate, --hash, --case, --number or --sanitize")
+		return ExitUsage
+	}
+	if flags.NArg() == 0 && len(dirs) == 0 {
+		fmt.Fprintln(c.stderr, "Error: no files given")
+		return ExitUsage
+	}
+
+	targetPlatforms, err := domain.ParsePlatforms(*platforms)
+	if err != nil {
+		fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return ExitUsage
+	}
+
+	var strategy domain.RenameStrategy
+	if *rulesPath != "" {
+		pipeline, err := loadPipeline(*rulesPath)
+		if err != nil {
+			fmt.Fprintf(c.stderr, "Error: %v\n", err)
+			return ExitUsage
+		}
+		strategy = pipeline
+	} else {
+		// Flags form a fixed pipeline: normalize → convert → template or replace → transliterate → hash → case → numbering → sanitize
+		rules := make([]domain.RuleConfig, 0, 8)
+		if *normalizeForm != "" {
+			rules = append(rules, domain.RuleConfig{
+				Type:    domain.RuleNormalize,
+				Enabled: true,
+				Form:    domain.NormalizationForm(*normalizeForm),
+				Scope:   domain.Scope(*normalizeScope),
+			})
+		}
+		if *conversions != "" {
+			rule := domain.RuleConfig{
+				Type:    domain.RuleConvert,
+				Enabled: true,
+				Scope:   domain.Scope(*convertScope),
+			}
+			for _, conversion := range strings.Split(*conversions, ",") {
+				rule.Conversions = append(rule.Conversions, domain.Conversion(strings.TrimSpace(conversion)))
+			}
+			rules = append(rules, rule)
+		}
+		if *template != "" {
+			// The pattern is matched by the template instead of being replaced
+			rules = append(rules, domain.RuleConfig{
+				Type:            domain.RuleTemplate,
+				Enabled:         true,
+				Template:        *template,
+				Pattern:         *pattern,
+				CaseInsensitive: *caseInsensitive,
+			})
+		} else if *pattern != "" {
+			rules = append(rules, domain.RuleConfig{
+				Type:            domain.RuleReplace,
+				Enabled:         true,
+				Pattern:         *pattern,
+				Replacement:     *replacement,
+				IsRegex:         *isRegex,
+				CaseInsensitive: *caseInsensitive,
+				Normalize:       *normalizeMatch,
+				Scope:           domain.Scope(*scope),
+			})
+		}
+		if *transliterate {
+			rules = append(rules, domain.RuleConfig{
+				Type:     domain.RuleTransliterate,
+				Enabled:  true,
+				Fallback: fallback,
+			})
+		}
+		if *hashAlgorithm != "" {
+			rules = append(rules, domain.RuleConfig{
+				Type:    domain.RuleHash,
+				Enabled: true,
+				Hash: &domain.HashOptions{
+					Algorithm: domain.HashAlgorithm(*hashAlgorithm),
+					Length:    *hashLength,
+					KeepStem:  *hashKeepStem,
+					Separator: *hashSeparator,
+				},
+			})
+		}
+		if *caseStyle != "" {
+			rules = append(rules, domain.RuleConfig{
+				Type:      domain.RuleCase,
+				Enabled:   true,
+				CaseStyle: domain.CaseStyle(*caseStyle),
+				Scope:     domain.Scope(*caseScope),
+			})
+		}
+		if *numberPosition != "" {
+			rules = append(rules, domain.RuleConfig{
+				Type:    domain.RuleNumbering,
+				Enabled: true,
+				Numbering: &domain.NumberingOptions{
+					Start:     *numberStart,
+					Step:      *numberStep,
+					Padding:   *numberPadding,
+					Position:  domain.NumberPosition(*numberPosition),
+					Separator: *numberSeparator,
+				},
+			})
+		}
+		if *sanitize {
+			rules = append(rules, domain.RuleConfig{
+				Type:      domain.RuleSanitize,
+				Enabled:   true,
+				Platforms: targetPlatforms,
+				Fallback:  sanitizeReplacement,
+			})
+		}
+
+		pipelineRules := make([]domain.PipelineRule, len(rules))
+		for i, rule := range rules {
+			built, err := rule.Build()
+			if err != nil {
+				fmt.Fprintf(c.stderr, "Error: invalid %s: %v\n", rule.Type, err)
+				if rule.Type == domain.RuleTemplate {
+					printTemplateCaret(c.stderr, *template, err)
+				}
+				return ExitUsage
+			}
+			pipelineRules[i] = domain.PipelineRule{Strategy: built, Enabled: true}
+		}
+		strategy = domain.NewPipelineStrategy(pipelineRules)
+	}
+
+	order, err := domain.ParseFileOrder(*sortOrder)
+	if err != nil {
+		fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return ExitUsage
+	}
+
+	// Prompting needs the GUI
+	options := domain.ConflictOptions{
+		Policy:         domain.ConflictPolicy(*conflictPolicy),
+		SuffixTemplate: *suffixTemplate,
+		SuffixStart:    *suffixStart,
+	}
+	if options.Policy == domain.ConflictPrompt {
+		fmt.Fprintln(c.stderr, "Error: the prompt policy is only available in the GUI")
+		return ExitUsage
+	}
+	c.renameUseCase.SetTransactional(*transactional)
+	if err := c.renameUseCase.SetTargetPlatforms(targetPlatforms); err != nil {
+		fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return ExitUsage
+	}
+	if err := c.renameUseCase.SetConflictOptions(options); err != nil {
+		fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return ExitUsage
+	}
+
+	if *duplicateAction != "" {
+		duplicates := domain.DuplicateOptions{
+			Detect:    true,
+			Algorithm: domain.HashAlgorithm(*duplicateHash),
+			Action:    domain.DuplicateAction(*duplicateAction),
+		}
+		if err := c.renameUseCase.SetDuplicateOptions(duplicates); err != nil {
+			fmt.Fprintf(c.stderr, "Error: %v\n", err)
+			return ExitUsage
+		}
+	}
+
+	// Directories given as arguments are renamed themselves
+	files := c.folderUseCase.NewFiles(flags.Args())
+
+	folderOptions := domain.FolderOptions{
+		MaxDepth:         *maxDepth,
+		Include:          include,
+		Exclude:          exclude,
+		Regex:            *filterRegex,
+		IncludeHidden:    *hidden,
+		RespectGitignore: *gitignore,
+		Targets:          domain.TargetKind(*targets),
+	}
+	if err := folderOptions.Validate(); err != nil {
+		fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return ExitUsage
+	}
+	if _, err := domain.NewFileFilter(include, exclude, *filterRegex); err != nil {
+		fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return ExitUsage
+	}
+	for _, dir := range dirs {
+		found, skipped, err := c.folderUseCase.Collect(dir, folderOptions)
+		if err != nil {
+			fmt.Fprintf(c.stderr, "Error: %v\n", err)
+			return ExitFailure
+		}
+		for _, err := range skipped {
+			fmt.Fprintf(c.stderr, "Warning: Skipped folder: %v\n", err)
+		}
+		files = append(files, found...)
+	}
+
+	if err := c.renameUseCase.SortFiles(files, order, *reverse); err != nil {
+		fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return ExitFailure
+	}
+
+	items := c.renameUseCase.Preview(files, strategy)
+
+	output := applyOutput{
+		Preview: make([]previewItem, len(items)),
+	}
+	for i, item := range items {
+		output.Preview[i] = previewItem{
+			OriginalPath:   item.File.OriginalPath(),
+			NewPath:        filepath.Join(item.File.Directory(), item.ResolvedName),
+			HasChanged:     item.File.HasChanged(),
+			IsDir:          item.File.IsDir(),
+			Conflict:       item.Conflict,
+			ConflictAction: string(item.Action),
+			Metadata:       item.Metadata.Strings(),
+			DuplicateOf:    item.DuplicateOf,
+			Duplicates:     item.Duplicates,
+			Issues:         item.Issues,
+			Collisions:     item.Collisions,
+			Status:         item.Status,
+			Detail:         item.Detail,
+		}
+	}
+
+	if !*jsonOutput {
+		c.printPreview(items)
+	}
+
+	exitCode := ExitOK
+	// Errors found by the preview block the batch (a dry run reports them through the exit code)
+	if blocking := usecase.BlockingItems(items); len(blocking) > 0 {
+		output.Blocked = true
+		exitCode = ExitFailure
+		fmt.Fprintf(c.stderr, "Error: %d file(s) cannot be renamed, nothing was changed:\n", len(blocking))
+		for _, item := range blocking {
+			fmt.Fprintf(c.stderr, "  %s: %s\n", item.File.OriginalPath(), item.Detail)
+		}
+	} else if !*dryRun {
+		// Ctrl+C stops between files and still prints what was done
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		result := c.renameUseCase.Execute(ctx, files)
+		stop()
+		output.Result = &resultOutput{
+			SuccessCount:    result.SuccessCount,
+			FailureCount:    result.FailureCount,
+			SkippedCount:    result.SkippedCount,
+			TrashedCount:    result.TrashedCount,
+			Aborted:         result.Aborted,
+			Cancelled:       result.Cancelled,
+			Errors:          result.Errors,
+			NewFilePaths:    result.NewFilePaths,
+			RolledBack:      result.RolledBack,
+			RolledBackCount: result.RolledBackCount,
+			RollbackErrors:  result.RollbackErrors,
+		}
+		if result.FailureCount > 0 || result.Cancelled {
+			exitCode = ExitFailure
+		}
+
+		if err := c.journalUseCase.Record(result.Operations); err != nil {
+			fmt.Fprintf(c.stderr, "Warning: Failed to save undo journal: %v\n", err)
+		}
+
+		if !*jsonOutput {
+			c.printResult(result)
+		}
+	}
+
+	if *jsonOutput {
+		encoder := json.NewEncoder(c.stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(output); err != nil {
+			fmt.Fprintf(c.stderr, "Error: %v\n", err)
+			return ExitFailure
+		}
+	}
+
+	return exitCode
+}
+
+// loadPipeline reads a JSON array of rule definitions (same format as the history entries)
+func loadPipeline(path string) (*domain.PipelineStrategy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []domain.RuleConfig
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("invalid rules file %s: %v", path, err)
+	}
+
+	return domain.NewPipelineFromConfig(rules)
+}
+
+// printPreview prints the preview as an aligned table
+func (c *CLI) printPreview(items []usecase.PreviewItem) {
+	w := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ORIGINAL\t\tNEW\tNOTE")
+	for _, item := range items {
+		arrow := "->"
+		switch {
+		case item.Status.IsError():
+			arrow = "!!"
+		case !item.File.HasChanged():
+			arrow = "=="
+		}
+		note := item.Detail
+		if item.DuplicateOf != "" && !item.Status.IsError() {
+			note = fmt.Sprintf("duplicate of %s (%s)", filepath.Base(item.DuplicateOf), item.DuplicateAction)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", item.File.OriginalName(), arrow, item.ResolvedName, note)
+	}
+	w.Flush()
+}
+
+// printResult prints the summary of an executed rename
+func (c *CLI) printResult(result usecase.RenameResult) {
+	fmt.Fprintf(c.stdout, "Renamed: %d, Failed: %d, Skipped: %d", result.SuccessCount, result.FailureCount, result.SkippedCount)
+	if result.TrashedCount > 0 {
+		fmt.Fprintf(c.stdout, ", Trashed: %d", result.TrashedCount)
+	}
+	if result.RolledBack {
+		fmt.Fprintf(c.stdout, ", Rolled back: %d", result.RolledBackCount)
+	}
+	fmt.Fprintln(c.stdout)
+	if result.Aborted {
+		fmt.Fprintln(c.stderr, "Aborted: nothing was renamed")
+	}
+	if result.Cancelled {
+		fmt.Fprintln(c.stderr, "Cancelled: the remaining files were not renamed")
+	}
+	for _, msg := range result.Errors {
+		fmt.Fprintf(c.stderr, "Error: %s\n", msg)
+	}
+	if result.RolledBack && len(result.RollbackErrors) == 0 {
+		fmt.Fprintln(c.stderr, "Rolled back: every file has its original name")
+	}
+	for _, msg := range result.RollbackErrors {
+		fmt.Fprintf(c.stderr, "Rollback error: %s\n", msg)
+	}
+}
+
+// undoOutput is the JSON document printed by undo --json
+type undoOutput struct {
+	RestoredCount int                      `json:"restoredCount"`
+	FailureCount  int                      `json:"failureCount"`
+	Errors        []string                 `json:"errors"`
+	Restored      []domain.RenameOperation `json:"restored"`
+}
+
+// runUndo handles: rename undo [-n N] [--json]
+func (c *CLI) runUndo(args []string) int {
+	flags := flag.NewFlagSet("undo", flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	count := flags.Int("n", 1, "number of batches to undo")
+	jsonOutput := flags.Bool("json", false, "print results as JSON")
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		return ExitUsage
+	}
+	if *count < 1 || flags.NArg() > 0 {
+		fmt.Fprintln(c.stderr, "Error: -n must be a positive number and no files may be given")
+		return ExitUsage
+	}
+
+	result, err := c.journalUseCase.Undo(*count)
+	if err != nil {
+		fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return ExitFailure
+	}
+
+	if *jsonOutput {
+		encoder := json.NewEncoder(c.stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(undoOutput(result)); err != nil {
+			fmt.Fprintf(c.stderr, "Error: %v\n", err)
+			return ExitFailure
+		}
+	} else {
+		for _, op := range result.Restored {
+			fmt.Fprintf(c.stdout, "%s -> %s\n", op.NewPath, op.OldPath)
+		}
+		fmt.Fprintf(c.stdout, "Restored: %d, Failed: %d\n", result.RestoredCount, result.FailureCount)
+		for _, msg := range result.Errors {
+			fmt.Fprintf(c.stderr, "Error: %s\n", msg)
+		}
+	}
+
+	if result.FailureCount > 0 {
+		return ExitFailure
+	}
+	return ExitOK
+}
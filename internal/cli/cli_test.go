@@ -0,0 +1,536 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"rename/internal/domain"
+	"rename/internal/repository"
+	"rename/internal/service"
+	"rename/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCLI(t *testing.T) (*CLI, *bytes.Buffer, *bytes.Buffer) {
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	fileSystem := service.NewFileSystemService()
+	renameUseCase := usecase.NewRenameUseCase(fileSystem)
+	renameUseCase.SetMetadataProvider(service.NewMetadataService())
+	renameUseCase.SetContentHasher(service.NewHashService())
+	renameUseCase.SetTrash(service.NewTrashService())
+	journalRepo := repository.NewJSONJournalRepository(filepath.Join(t.TempDir(), "journal.json"))
+	journalUseCase := usecase.NewJournalUseCase(journalRepo, fileSystem)
+	folderUseCase := usecase.NewFolderUseCase(fileSystem)
+	return NewCLI(renameUseCase, journalUseCase, folderUseCase, stdout, stderr), stdout, stderr
+}
+
+func createFiles(t *testing.T, dir string, names ...string) []string {
+	paths := make([]string, len(names))
+	for i, name := range names {
+		paths[i] = filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(paths[i], []byte(name), 0644))
+	}
+	return paths
+}
+
+func TestIsCommand(t *testing.T) {
+	assert.True(t, IsCommand([]string{"apply", "--pattern", "a"}))
+	assert.True(t, IsCommand([]string{"undo"}))
+	assert.False(t, IsCommand([]string{"/path/to/file.txt"}))
+	assert.False(t, IsCommand([]string{}))
+}
+
+func TestCLI_Apply(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := createFiles(t, tmpDir, "test1.txt", "other.txt")
+
+	cli, stdout, _ := newTestCLI(t)
+	code := cli.Run(append([]string{"apply", "--pattern", "test", "--replace", "renamed"}, paths...))
+
+	assert.Equal(t, ExitOK, code)
+	assert.FileExists(t, filepath.Join(tmpDir, "renamed1.txt"))
+	assert.FileExists(t, filepath.Join(tmpDir, "other.txt"))
+	assert.Contains(t, stdout.String(), "test1.txt")
+	assert.Contains(t, stdout.String(), "renamed1.txt")
+	assert.Contains(t, stdout.String(), "Renamed: 1, Failed: 0, Skipped: 0")
+}
+
+func TestCLI_Apply_DryRunJSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := createFiles(t, tmpDir, "IMG_001.jpg")
+
+	cli, stdout, _ := newTestCLI(t)
+	code := cli.Run(append([]string{"apply", "--pattern", `img_(\d+)`, "--replace", "photo-$1", "--regex", "--ignore-case", "--dry-run", "--json"}, paths...))
+
+	assert.Equal(t, ExitOK, code)
+	// Dry run must not touch the file system
+	assert.FileExists(t, paths[0])
+
+	var output applyOutput
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+	assert.Equal(t, 1, len(output.Preview))
+	assert.Equal(t, filepath.Join(tmpDir, "photo-001.jpg"), output.Preview[0].NewPath)
+	assert.True(t, output.Preview[0].HasChanged)
+	assert.Nil(t, output.Result)
+}
+
+func TestCLI_Apply_FailureExitCode(t *testing.T) {
+	tmpDir := t.TempDir()
+	missing := filepath.Join(tmpDir, "missing.txt")
+
+	cli, _, stderr := newTestCLI(t)
+	code := cli.Run([]string{"apply", "--pattern", "missing", "--replace", "found", missing})
+
+	assert.Equal(t, ExitFailure, code)
+	assert.Contains(t, stderr.String(), "missing.txt")
+}
+
+func TestCLI_Apply_BlockedByStatus(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := createFiles(t, tmpDir, "10-30.txt", "memo.txt", "todo.txt")
+
+	cli, stdout, stderr := newTestCLI(t)
+	code := cli.Run(append([]string{"apply", "--pattern", `^(\d+)-|memo`, "--replace", "${1}:", "--regex", "--platforms", "windows", "--json"}, paths...))
+
+	// 10:30.txt is invalid on Windows, so nothing is renamed
+	assert.Equal(t, ExitFailure, code)
+	for _, path := range paths {
+		assert.FileExists(t, path)
+	}
+	assert.Contains(t, stderr.String(), "invalid on windows")
+
+	var output applyOutput
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+	assert.True(t, output.Blocked)
+	assert.Nil(t, output.Result)
+	assert.Equal(t, domain.StatusInvalidName, output.Preview[0].Status)
+	assert.Equal(t, domain.StatusInvalidName, output.Preview[1].Status)
+	assert.Equal(t, domain.StatusUnchanged, output.Preview[2].Status)
+}
+
+func TestCLI_Apply_BatchCollision(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   string
+		code     int
+		expected []string
+	}{
+		{"suffix", "suffix", ExitOK, []string{"a.txt", "a (2).txt", "b.txt"}},
+		{"skip", "skip", ExitOK, []string{"a.txt", "y_a.txt", "b.txt"}},
+		{"fail", "fail", ExitFailure, []string{"x_a.txt", "y_a.txt", "z_b.txt"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			paths := createFiles(t, tmpDir, "x_a.txt", "y_a.txt", "z_b.txt")
+
+			cli, stdout, _ := newTestCLI(t)
+			args := []string{"apply", "--pattern", `^\w_`, "--replace", "", "--regex", "--on-conflict", tt.policy, "--suffix-template", " ({n})", "--suffix-start", "2"}
+			code := cli.Run(append(args, paths...))
+
+			assert.Equal(t, tt.code, code)
+			assert.Contains(t, stdout.String(), "same new name as y_a.txt ("+tt.policy+")")
+			for _, name := range tt.expected {
+				assert.FileExists(t, filepath.Join(tmpDir, name))
+			}
+		})
+	}
+}
+
+func TestCLI_Apply_Transactional(t *testing.T) {
+	tmpDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "sub"), 0755))
+	paths := createFiles(t, tmpDir, "b.txt", "sub.txt", "sub/keep.txt")
+
+	// sub.txt cannot replace the folder sub, so b is renamed back to b.txt
+	cli, stdout, stderr := newTestCLI(t)
+	code := cli.Run([]string{"apply", "--pattern", `\.txt$`, "--replace", "", "--regex", "--on-conflict", "overwrite", "--transactional", "--json", paths[0], paths[1]})
+
+	assert.Equal(t, ExitFailure, code)
+	assert.FileExists(t, paths[0])
+	assert.FileExists(t, paths[1])
+	assert.NoFileExists(t, filepath.Join(tmpDir, "b"))
+	assert.Empty(t, stderr.String())
+
+	var output applyOutput
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+	assert.True(t, output.Result.RolledBack)
+	assert.Equal(t, 1, output.Result.RolledBackCount)
+	assert.Equal(t, 0, output.Result.SuccessCount)
+	assert.Equal(t, 1, output.Result.FailureCount)
+	assert.Equal(t, []string{paths[0], paths[1]}, output.Result.NewFilePaths)
+}
+
+func TestCLI_Apply_UsageErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"missing pattern", []string{"apply", "file.txt"}},
+		{"missing files", []string{"apply", "--pattern", "a"}},
+		{"invalid regex", []string{"apply", "--pattern", "[invalid(", "--regex", "file.txt"}},
+		{"unknown flag", []string{"apply", "--unknown", "file.txt"}},
+		{"unknown conflict policy", []string{"apply", "--pattern", "a", "--on-conflict", "merge", "file.txt"}},
+		{"unknown number position", []string{"apply", "--number", "middle", "file.txt"}},
+		{"unknown sort order", []string{"apply", "--pattern", "a", "--sort", "random", "file.txt"}},
+		{"unknown scope", []string{"apply", "--pattern", "a", "--scope", "middle", "file.txt"}},
+		{"unknown case style", []string{"apply", "--case", "sarcastic", "file.txt"}},
+		{"unknown case scope", []string{"apply", "--case", "lower", "--case-scope", "middle", "file.txt"}},
+		{"unknown targets", []string{"apply", "--pattern", "a", "--dir", ".", "--targets", "links"}},
+		{"invalid include glob", []string{"apply", "--pattern", "a", "--dir", ".", "--include", "[abc"}},
+		{"rules with pattern", []string{"apply", "--rules", "rules.json", "--pattern", "a", "file.txt"}},
+		{"missing rules file", []string{"apply", "--rules", "/nonexistent/rules.json", "file.txt"}},
+		{"prompt policy", []string{"apply", "--pattern", "a", "--on-conflict", "prompt", "file.txt"}},
+		{"invalid suffix template", []string{"apply", "--pattern", "a", "--suffix-template", "copy", "file.txt"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli, _, _ := newTestCLI(t)
+			assert.Equal(t, ExitUsage, cli.Run(tt.args))
+		})
+	}
+}
+
+func TestCLI_Apply_ConflictPolicy(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := createFiles(t, tmpDir, "a.txt", "b.txt")
+
+	cli, stdout, stderr := newTestCLI(t)
+	code := cli.Run([]string{"apply", "--pattern", "a", "--replace", "b", "--on-conflict", "fail", paths[0]})
+
+	assert.Equal(t, ExitFailure, code)
+	assert.FileExists(t, paths[0])
+	assert.Contains(t, stderr.String(), "Aborted")
+
+	stdout.Reset()
+	code = cli.Run([]string{"apply", "--pattern", "a", "--replace", "b", "--on-conflict", "suffix", "--suffix-template", " ({n})", "--suffix-start", "2", paths[0]})
+
+	assert.Equal(t, ExitOK, code)
+	assert.FileExists(t, filepath.Join(tmpDir, "b (2).txt"))
+	assert.Contains(t, stdout.String(), "exists (suffix)")
+}
+
+func TestCLI_Apply_LinkedTarget(t *testing.T) {
+	tests := []struct {
+		name string
+		link func(oldname, newname string) error
+	}{
+		{"symbolic link", os.Symlink},
+		{"hard link", os.Link},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			paths := createFiles(t, tmpDir, "a.txt")
+			target := filepath.Join(tmpDir, "b.txt")
+			assert.NoError(t, tt.link(paths[0], target))
+
+			cli, stdout, _ := newTestCLI(t)
+			code := cli.Run([]string{"apply", "--pattern", "a", "--replace", "b", "--on-conflict", "skip", "--json", paths[0]})
+
+			// b.txt is another entry pointing at the same data, so it is a conflict like any existing file
+			assert.Equal(t, ExitOK, code)
+			var output applyOutput
+			assert.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+			assert.True(t, output.Preview[0].Conflict)
+			assert.Equal(t, "skip", output.Preview[0].ConflictAction)
+			assert.Equal(t, 1, output.Result.SkippedCount)
+
+			entries, err := os.ReadDir(tmpDir)
+			assert.NoError(t, err)
+			names := make([]string, len(entries))
+			for i, entry := range entries {
+				names[i] = entry.Name()
+			}
+			assert.Equal(t, []string{"a.txt", "b.txt"}, names)
+			info, err := os.Lstat(target)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.name == "symbolic link", info.Mode()&os.ModeSymlink != 0)
+		})
+	}
+}
+
+func TestCLI_Apply_Numbering(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := createFiles(t, tmpDir, "DSC10.jpg", "DSC9.jpg")
+
+	cli, _, _ := newTestCLI(t)
+	code := cli.Run(append([]string{"apply",
+		"--pattern", `^DSC\d+`, "--replace", "IMG_{n}", "--regex",
+		"--number", "placeholder", "--number-padding", "4", "--sort", "natural"}, paths...))
+
+	assert.Equal(t, ExitOK, code)
+	// Natural order puts DSC9 before DSC10
+	content, err := os.ReadFile(filepath.Join(tmpDir, "IMG_0001.jpg"))
+	assert.NoError(t, err)
+	assert.Equal(t, "DSC9.jpg", string(content))
+	content, err = os.ReadFile(filepath.Join(tmpDir, "IMG_0002.jpg"))
+	assert.NoError(t, err)
+	assert.Equal(t, "DSC10.jpg", string(content))
+}
+
+func TestCLI_Apply_Case(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := createFiles(t, tmpDir, "My Vacation Photo.JPG")
+
+	cli, _, _ := newTestCLI(t)
+	code := cli.Run(append([]string{"apply", "--case", "kebab", "--case-scope", "full"}, paths...))
+
+	assert.Equal(t, ExitOK, code)
+	assert.FileExists(t, filepath.Join(tmpDir, "my-vacation-photo.jpg"))
+}
+
+func TestCLI_Apply_Template(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := createFiles(t, tmpDir, "IMG_0001.jpg")
+
+	cli, _, stderr := newTestCLI(t)
+
+	// A photo without EXIF keeps its name
+	code := cli.Run(append([]string{"apply", "--template", "{exif.date}_{exif.model}"}, paths...))
+	assert.Equal(t, ExitOK, code)
+	assert.FileExists(t, paths[0])
+
+	code = cli.Run(append([]string{"apply", "--template", "{name}_edited", "--case", "upper"}, paths...))
+	assert.Equal(t, ExitOK, code)
+	assert.FileExists(t, filepath.Join(tmpDir, "IMG_0001_EDITED.jpg"))
+
+	code = cli.Run(append([]string{"apply", "--template", "{exif.unknown"}, paths...))
+	assert.Equal(t, ExitUsage, code)
+	assert.Contains(t, stderr.String(), "invalid template")
+}
+
+func TestCLI_Apply_TemplateCaptures(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := createFiles(t, tmpDir, "2024-05-01 Beach Day.jpg", "notes.txt")
+
+	cli, _, stderr := newTestCLI(t)
+	code := cli.Run(append([]string{"apply", "--template", "{2|slug}_{1}", "--pattern", `^(\S+) (.+)$`}, paths...))
+
+	assert.Equal(t, ExitOK, code)
+	assert.FileExists(t, filepath.Join(tmpDir, "beach-day_2024-05-01.jpg"))
+	assert.FileExists(t, filepath.Join(tmpDir, "notes.txt"))
+
+	code = cli.Run(append([]string{"apply", "--template", "{stem|shout}"}, paths[1]))
+	assert.Equal(t, ExitUsage, code)
+	assert.Contains(t, stderr.String(), "column 7: unknown filter \"shout\"\n  {stem|shout}\n        ^\n")
+}
+
+func TestCLI_Apply_Hash(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "photo.JPG")
+	assert.NoError(t, os.WriteFile(path, []byte("abc"), 0644))
+
+	cli, _, _ := newTestCLI(t)
+	code := cli.Run([]string{"apply", "--hash", "sha256", "--hash-length", "12", "--hash-keep-stem", "--case", "lower", "--case-scope", "extension", path})
+
+	assert.Equal(t, ExitOK, code)
+	assert.FileExists(t, filepath.Join(tmpDir, "photo_ba7816bf8f01.jpg"))
+
+	code = cli.Run([]string{"apply", "--hash", "crc32", filepath.Join(tmpDir, "photo_ba7816bf8f01.jpg")})
+	assert.Equal(t, ExitUsage, code)
+}
+
+func TestCLI_Apply_Duplicates(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := make([]string, 0)
+	for _, name := range []string{"a.jpg", "b.jpg", "c.jpg"} {
+		paths = append(paths, filepath.Join(tmpDir, name))
+		assert.NoError(t, os.WriteFile(paths[len(paths)-1], []byte("same"), 0644))
+	}
+
+	cli, stdout, _ := newTestCLI(t)
+	code := cli.Run(append([]string{"apply", "--template", "trip", "--duplicates", "shared", "--duplicates-hash", "xxh64"}, paths...))
+
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout.String(), "duplicate of a.jpg (shared)")
+	assert.FileExists(t, filepath.Join(tmpDir, "trip.jpg"))
+	assert.FileExists(t, filepath.Join(tmpDir, "trip1.jpg"))
+	assert.FileExists(t, filepath.Join(tmpDir, "trip2.jpg"))
+
+	code = cli.Run([]string{"apply", "--template", "x", "--duplicates", "delete", filepath.Join(tmpDir, "trip.jpg")})
+	assert.Equal(t, ExitUsage, code)
+}
+
+func TestCLI_Apply_Normalize(t *testing.T) {
+	tmpDir := t.TempDir()
+	// Names as stored by macOS (NFD)
+	paths := createFiles(t, tmpDir, "\u30ab\u3099イト\u3099.pdf", "\u30cf\u309aン.txt")
+
+	cli, _, _ := newTestCLI(t)
+	code := cli.Run(append([]string{"apply", "--pattern", "ガイド", "--replace", "guide", "--normalize-match"}, paths...))
+	assert.Equal(t, ExitOK, code)
+	assert.FileExists(t, filepath.Join(tmpDir, "guide.pdf"))
+	assert.FileExists(t, paths[1])
+
+	code = cli.Run([]string{"apply", "--normalize", "nfc", paths[1]})
+	assert.Equal(t, ExitOK, code)
+	assert.FileExists(t, filepath.Join(tmpDir, "\u30d1ン.txt"))
+	assert.NoFileExists(t, paths[1])
+
+	code = cli.Run([]string{"apply", "--normalize", "nfx", paths[1]})
+	assert.Equal(t, ExitUsage, code)
+}
+
+func TestCLI_Apply_Convert(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := createFiles(t, tmpDir, "ｶﾀﾛｸﾞ　第十二版.pdf")
+
+	cli, _, _ := newTestCLI(t)
+	code := cli.Run(append([]string{"apply", "--convert", "kana-fullwidth, halfwidth,kanji-numbers"}, paths...))
+
+	assert.Equal(t, ExitOK, code)
+	assert.FileExists(t, filepath.Join(tmpDir, "カタログ 第12版.pdf"))
+
+	code = cli.Run([]string{"apply", "--convert", "romaji", filepath.Join(tmpDir, "カタログ 第12版.pdf")})
+	assert.Equal(t, ExitUsage, code)
+}
+
+func TestCLI_Apply_Transliterate(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := createFiles(t, tmpDir, "東京タワー.JPG", "Crème brûlée.txt")
+
+	cli, _, _ := newTestCLI(t)
+	code := cli.Run(append([]string{"apply", "--transliterate", "--transliterate-fallback", "tokyo_", "--case", "lower"}, paths...))
+
+	assert.Equal(t, ExitOK, code)
+	assert.FileExists(t, filepath.Join(tmpDir, "tokyo_tawaa.JPG"))
+	assert.FileExists(t, filepath.Join(tmpDir, "creme brulee.txt"))
+
+	code = cli.Run([]string{"apply", "--transliterate", "--transliterate-fallback", "/", paths[0]})
+	assert.Equal(t, ExitUsage, code)
+}
+
+func TestCLI_Apply_Sanitize(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := createFiles(t, tmpDir, "10-30 memo.txt")
+
+	cli, stdout, _ := newTestCLI(t)
+	code := cli.Run(append([]string{"apply", "--pattern", "-", "--replace", ":", "--platforms", "windows", "--dry-run"}, paths...))
+	// Invalid names block the batch
+	assert.Equal(t, ExitFailure, code)
+	assert.Contains(t, stdout.String(), `invalid on windows: contains reserved characters ':'`)
+
+	code = cli.Run(append([]string{"apply", "--pattern", "-", "--replace", ":", "--platforms", "all", "--sanitize"}, paths...))
+	assert.Equal(t, ExitOK, code)
+	assert.FileExists(t, filepath.Join(tmpDir, "10_30 memo.txt"))
+
+	code = cli.Run([]string{"apply", "--sanitize", "--platforms", "amiga", paths[0]})
+	assert.Equal(t, ExitUsage, code)
+}
+
+func TestCLI_Apply_Scope(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := createFiles(t, tmpDir, "jpg_export.jpg", "logs.tar.gz")
+
+	cli, _, _ := newTestCLI(t)
+	code := cli.Run(append([]string{"apply", "--pattern", `^(\w+)$`, "--replace", "${1}_old", "--regex", "--scope", "stem"}, paths...))
+
+	assert.Equal(t, ExitOK, code)
+	assert.FileExists(t, filepath.Join(tmpDir, "jpg_export_old.jpg"))
+	assert.FileExists(t, filepath.Join(tmpDir, "logs_old.tar.gz"))
+}
+
+func TestCLI_Apply_Dir(t *testing.T) {
+	tmpDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "sub", "deeper"), 0755))
+	createFiles(t, tmpDir, "IMG_1.jpg", "IMG_2.txt", ".IMG_3.jpg", "sub/IMG_4.jpg", "sub/deeper/IMG_5.jpg")
+
+	cli, _, _ := newTestCLI(t)
+	code := cli.Run([]string{"apply", "--pattern", "IMG_", "--replace", "photo_",
+		"--dir", tmpDir, "--max-depth", "2", "--include", "*.jpg"})
+
+	assert.Equal(t, ExitOK, code)
+	assert.FileExists(t, filepath.Join(tmpDir, "photo_1.jpg"))
+	assert.FileExists(t, filepath.Join(tmpDir, "sub", "photo_4.jpg"))
+	// Not matched by --include, hidden or deeper than --max-depth
+	assert.FileExists(t, filepath.Join(tmpDir, "IMG_2.txt"))
+	assert.FileExists(t, filepath.Join(tmpDir, ".IMG_3.jpg"))
+	assert.FileExists(t, filepath.Join(tmpDir, "sub", "deeper", "IMG_5.jpg"))
+}
+
+func TestCLI_Apply_Directories(t *testing.T) {
+	tmpDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "My Album", "Day One"), 0755))
+	createFiles(t, tmpDir, "My Album/Day One/IMG 1.jpg")
+
+	cli, _, _ := newTestCLI(t)
+	code := cli.Run([]string{"apply", "--case", "kebab", "--dir", tmpDir, "--targets", "both"})
+
+	assert.Equal(t, ExitOK, code)
+	assert.FileExists(t, filepath.Join(tmpDir, "my-album", "day-one", "img-1.jpg"))
+
+	// Undo restores the whole tree
+	assert.Equal(t, ExitOK, cli.Run([]string{"undo"}))
+	assert.FileExists(t, filepath.Join(tmpDir, "My Album", "Day One", "IMG 1.jpg"))
+}
+
+func TestCLI_Apply_Rules(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := createFiles(t, tmpDir, "DSC_beach  day.jpg")
+	rulesPath := filepath.Join(t.TempDir(), "rules.json")
+	rules := `[
+		{"type": "replace", "enabled": true, "pattern": "DSC_"},
+		{"type": "replace", "enabled": true, "pattern": "\\s+", "replacement": "_", "isRegex": true},
+		{"type": "replace", "enabled": false, "pattern": "beach", "replacement": "sea"},
+		{"type": "numbering", "enabled": true, "numbering": {"start": 1, "step": 1, "padding": 2, "position": "prefix", "separator": "-"}}
+	]`
+	assert.NoError(t, os.WriteFile(rulesPath, []byte(rules), 0644))
+
+	cli, _, _ := newTestCLI(t)
+	code := cli.Run(append([]string{"apply", "--rules", rulesPath}, paths...))
+
+	assert.Equal(t, ExitOK, code)
+	assert.FileExists(t, filepath.Join(tmpDir, "01-beach_day.jpg"))
+}
+
+func TestCLI_Undo(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := createFiles(t, tmpDir, "a.txt", "b.txt")
+
+	cli, stdout, _ := newTestCLI(t)
+	code := cli.Run(append([]string{"apply", "--pattern", ".txt", "--replace", ".md"}, paths...))
+	assert.Equal(t, ExitOK, code)
+	assert.FileExists(t, filepath.Join(tmpDir, "a.md"))
+
+	stdout.Reset()
+	code = cli.Run([]string{"undo"})
+
+	assert.Equal(t, ExitOK, code)
+	assert.FileExists(t, paths[0])
+	assert.FileExists(t, paths[1])
+	assert.NoFileExists(t, filepath.Join(tmpDir, "a.md"))
+	assert.Contains(t, stdout.String(), "Restored: 2, Failed: 0")
+
+	// Nothing left to undo
+	stdout.Reset()
+	code = cli.Run([]string{"undo"})
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout.String(), "Restored: 0, Failed: 0")
+}
+
+func TestCLI_Undo_ModifiedFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	paths := createFiles(t, tmpDir, "a.txt")
+
+	cli, _, stderr := newTestCLI(t)
+	assert.Equal(t, ExitOK, cli.Run([]string{"apply", "--pattern", "a", "--replace", "b", paths[0]}))
+
+	// Modify the renamed file so undo must refuse to touch it
+	renamed := filepath.Join(tmpDir, "b.txt")
+	assert.NoError(t, os.WriteFile(renamed, []byte("changed content"), 0644))
+
+	code := cli.Run([]string{"undo", "-n", "1"})
+
+	assert.Equal(t, ExitFailure, code)
+	assert.FileExists(t, renamed)
+	assert.Contains(t, stderr.String(), "modified")
+}
@@ -0,0 +1,50 @@
+// Package digest computes content digests of files for hash based naming
+package digest
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// Supported algorithm names
+const (
+	SHA256 = "sha256"
+	SHA1   = "sha1"
+	MD5    = "md5"
+	XXH64  = "xxh64"
+)
+
+// New returns a hash for the algorithm name
+func New(algorithm string) (hash.Hash, error) {
+	switch algorithm {
+	case SHA256:
+		return sha256.New(), nil
+	case SHA1:
+		return sha1.New(), nil
+	case MD5:
+		return md5.New(), nil
+	case XXH64:
+		return NewXXH64(), nil
+	}
+	return nil, fmt.Errorf("unknown hash algorithm: %q", algorithm)
+}
+
+// bufferSize is the read size used for large files
+const bufferSize = 1 << 20
+
+// Hex reads r to the end and returns its lowercase hex digest
+func Hex(r io.Reader, algorithm string) (string, error) {
+	h, err := New(algorithm)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.CopyBuffer(h, r, make([]byte, bufferSize)); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
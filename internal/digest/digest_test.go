@@ -0,0 +1,73 @@
+package digest
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXXH64(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected uint64
+	}{
+		{"", 0xef46db3751d8e999},
+		{"a", 0xd24ec4f1a98c6e5b},
+		{"abc", 0x44bc2cf5ad770999},
+		{"Nobody inspects the spammish repetition", 0xfbcea83c8a378bf1},
+		{"The quick brown fox jumps over the lazy dog", 0x0b242d361fda71bc},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			h := NewXXH64()
+			h.Write([]byte(tt.input))
+			assert.Equal(t, tt.expected, h.Sum64())
+		})
+	}
+}
+
+func TestXXH64_Streaming(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789abcdefghijklmnopqrstuvwxyz"), 100)
+	whole := NewXXH64()
+	whole.Write(data)
+
+	// Writes of any size give the same digest
+	for _, chunk := range []int{1, 3, 7, 31, 32, 33, 100} {
+		h := NewXXH64()
+		for rest := data; len(rest) > 0; {
+			n := min(chunk, len(rest))
+			h.Write(rest[:n])
+			rest = rest[n:]
+		}
+		assert.Equal(t, whole.Sum64(), h.Sum64(), "chunk %d", chunk)
+	}
+
+	whole.Reset()
+	assert.Equal(t, uint64(0xef46db3751d8e999), whole.Sum64())
+}
+
+func TestHex(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		expected  string
+	}{
+		{SHA256, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{SHA1, "a9993e364706816aba3e25717850c26c9cd0d89d"},
+		{MD5, "900150983cd24fb0d6963f7d28e17f72"},
+		{XXH64, "44bc2cf5ad770999"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			digest, err := Hex(strings.NewReader("abc"), tt.algorithm)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, digest)
+		})
+	}
+
+	_, err := Hex(strings.NewReader("abc"), "crc32")
+	assert.Error(t, err)
+}
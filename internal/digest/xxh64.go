@@ -0,0 +1,132 @@
+package digest
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+// XXH64 primes (variables so the seed setup can wrap around like the reference implementation)
+var (
+	prime1 uint64 = 11400714785074694791
+	prime2 uint64 = 14029467366897019727
+	prime3 uint64 = 1609587929392839161
+	prime4 uint64 = 9650029242287828579
+	prime5 uint64 = 2870177450012600261
+)
+
+// xxh64 is a streaming XXH64 digest with seed 0 (a fast non-cryptographic hash)
+type xxh64 struct {
+	v1, v2, v3, v4 uint64
+	total          uint64
+	buf            [32]byte
+	n              int // Bytes buffered in buf
+}
+
+// NewXXH64 returns a new XXH64 hash (seed 0)
+func NewXXH64() hash.Hash64 {
+	d := &xxh64{}
+	d.Reset()
+	return d
+}
+
+// Reset resets the digest to its initial state
+func (d *xxh64) Reset() {
+	d.v1 = prime1 + prime2
+	d.v2 = prime2
+	d.v3 = 0
+	d.v4 = -prime1
+	d.total = 0
+	d.n = 0
+}
+
+// Size returns the digest size in bytes
+func (d *xxh64) Size() int { return 8 }
+
+// BlockSize returns the stripe size
+func (d *xxh64) BlockSize() int { return 32 }
+
+// Write adds data to the digest
+func (d *xxh64) Write(data []byte) (int, error) {
+	written := len(data)
+	d.total += uint64(written)
+
+	if d.n+len(data) < 32 {
+		d.n += copy(d.buf[d.n:], data)
+		return written, nil
+	}
+
+	if d.n > 0 {
+		copied := copy(d.buf[d.n:], data)
+		d.stripe(d.buf[:])
+		data = data[copied:]
+		d.n = 0
+	}
+	for ; len(data) >= 32; data = data[32:] {
+		d.stripe(data)
+	}
+	d.n = copy(d.buf[:], data)
+
+	return written, nil
+}
+
+// stripe processes 32 bytes
+func (d *xxh64) stripe(data []byte) {
+	d.v1 = round(d.v1, binary.LittleEndian.Uint64(data[0:]))
+	d.v2 = round(d.v2, binary.LittleEndian.Uint64(data[8:]))
+	d.v3 = round(d.v3, binary.LittleEndian.Uint64(data[16:]))
+	d.v4 = round(d.v4, binary.LittleEndian.Uint64(data[24:]))
+}
+
+// Sum64 returns the digest without changing the state
+func (d *xxh64) Sum64() uint64 {
+	var h uint64
+	if d.total >= 32 {
+		h = bits.RotateLeft64(d.v1, 1) + bits.RotateLeft64(d.v2, 7) + bits.RotateLeft64(d.v3, 12) + bits.RotateLeft64(d.v4, 18)
+		h = mergeRound(h, d.v1)
+		h = mergeRound(h, d.v2)
+		h = mergeRound(h, d.v3)
+		h = mergeRound(h, d.v4)
+	} else {
+		h = prime5
+	}
+	h += d.total
+
+	data := d.buf[:d.n]
+	for ; len(data) >= 8; data = data[8:] {
+		h ^= round(0, binary.LittleEndian.Uint64(data))
+		h = bits.RotateLeft64(h, 27)*prime1 + prime4
+	}
+	if len(data) >= 4 {
+		h ^= uint64(binary.LittleEndian.Uint32(data)) * prime1
+		h = bits.RotateLeft64(h, 23)*prime2 + prime3
+		data = data[4:]
+	}
+	for _, b := range data {
+		h ^= uint64(b) * prime5
+		h = bits.RotateLeft64(h, 11) * prime1
+	}
+
+	h ^= h >> 33
+	h *= prime2
+	h ^= h >> 29
+	h *= prime3
+	h ^= h >> 32
+	return h
+}
+
+// Sum appends the big-endian digest to b
+func (d *xxh64) Sum(b []byte) []byte {
+	return binary.BigEndian.AppendUint64(b, d.Sum64())
+}
+
+func round(acc, input uint64) uint64 {
+	acc += input * prime2
+	acc = bits.RotateLeft64(acc, 31)
+	return acc * prime1
+}
+
+func mergeRound(acc, value uint64) uint64 {
+	acc ^= round(0, value)
+	return acc*prime1 + prime4
+}
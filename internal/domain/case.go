@@ -0,0 +1,214 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// CaseStyle identifies a case conversion
+type CaseStyle string
+
+const (
+	CaseLower  CaseStyle = "lower"  // my photo 2024
+	CaseUpper  CaseStyle = "upper"  // MY PHOTO 2024
+	CaseTitle  CaseStyle = "title"  // My Photo 2024 (separators kept)
+	CaseCamel  CaseStyle = "camel"  // myPhoto2024
+	CasePascal CaseStyle = "pascal" // MyPhoto2024
+	CaseSnake  CaseStyle = "snake"  // my_photo_2024
+	CaseKebab  CaseStyle = "kebab"  // my-photo-2024
+)
+
+// CaseStrategy converts the case of a filename part
+type CaseStrategy struct {
+	style CaseStyle
+	scope Scope
+}
+
+// NewCaseStrategy creates a case conversion strategy
+// With ScopeFull, stem and extension are converted separately so the dot is kept
+func NewCaseStrategy(style CaseStyle, scope Scope) (*CaseStrategy, error) {
+	switch style {
+	case CaseLower, CaseUpper, CaseTitle, CaseCamel, CasePascal, CaseSnake, CaseKebab:
+	default:
+		return nil, fmt.Errorf("unknown case style: %q", style)
+	}
+	switch scope {
+	case ScopeStem, ScopeExtension, ScopeFull:
+	default:
+		return nil, fmt.Errorf("unknown scope: %q", scope)
+	}
+
+	return &CaseStrategy{
+		style: style,
+		scope: scope,
+	}, nil
+}
+
+// Apply converts the selected parts of filename
+func (s *CaseStrategy) Apply(filename string) string {
+	return s.ApplyContext(filename, RenameContext{})
+}
+
+// ApplyContext converts the selected parts of filename (directory names have no extension)
+func (s *CaseStrategy) ApplyContext(filename string, ctx RenameContext) string {
+	stem, ext := ctx.SplitName(filename)
+
+	if s.scope != ScopeExtension {
+		// Keep the leading dot of hidden files
+		dots := stem[:len(stem)-len(strings.TrimLeft(stem, "."))]
+		stem = dots + ConvertCase(strings.TrimPrefix(stem, dots), s.style)
+	}
+	if s.scope != ScopeStem && ext != "" {
+		ext = "." + ConvertCase(strings.TrimPrefix(ext, "."), s.style)
+	}
+	return stem + ext
+}
+
+// ConvertCase converts text to style
+func ConvertCase(text string, style CaseStyle) string {
+	switch style {
+	case CaseLower:
+		return strings.ToLower(text)
+	case CaseUpper:
+		return strings.ToUpper(text)
+	case CaseTitle:
+		return titleCase(text)
+	}
+
+	words := SplitWords(text)
+	for i, word := range words {
+		switch {
+		case style == CaseCamel && i == 0, style == CaseSnake, style == CaseKebab:
+			words[i] = strings.ToLower(word)
+		default:
+			words[i] = capitalize(word)
+		}
+	}
+
+	switch style {
+	case CaseSnake:
+		return strings.Join(words, "_")
+	case CaseKebab:
+		return strings.Join(words, "-")
+	}
+	return strings.Join(words, "")
+}
+
+// charClass groups runes for word boundary detection
+type charClass int
+
+const (
+	classSeparator charClass = iota
+	classLower
+	classUpper
+	classDigit
+	classCJK    // Han, Hiragana, Katakana (no case, no spaces between words)
+	classLetter // Letters without case (other scripts)
+)
+
+func classify(r rune) charClass {
+	switch {
+	case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) || r == 'ー' || r == '々':
+		return classCJK
+	case unicode.IsUpper(r):
+		return classUpper
+	case unicode.IsLower(r):
+		return classLower
+	case unicode.IsDigit(r):
+		return classDigit
+	case unicode.IsLetter(r) || unicode.Is(unicode.Mn, r):
+		return classLetter
+	}
+	return classSeparator
+}
+
+// SplitWords splits text into words
+// Boundaries are separators (space, _, -, . and other punctuation), lower→upper changes
+// (fileName), acronym ends (HTTPServer → HTTP Server), letter↔digit changes (img2024)
+// and script changes between CJK and other text (写真IMG → 写真 IMG)
+func SplitWords(text string) []string {
+	runes := []rune(text)
+	words := make([]string, 0)
+	start := -1
+
+	for i, r := range runes {
+		class := classify(r)
+		if class == classSeparator {
+			if start >= 0 {
+				words = append(words, string(runes[start:i]))
+				start = -1
+			}
+			continue
+		}
+
+		if start >= 0 && isBoundary(runes, i) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(runes[start:]))
+	}
+
+	return words
+}
+
+// isBoundary reports whether a new word starts at runes[i] (runes[i-1] is not a separator)
+func isBoundary(runes []rune, i int) bool {
+	prev, curr := classify(runes[i-1]), classify(runes[i])
+
+	switch {
+	case prev == classLower && curr == classUpper:
+		return true
+	case prev == classUpper && curr == classUpper:
+		// Last capital of an acronym followed by lowercase starts a new word
+		return i+1 < len(runes) && classify(runes[i+1]) == classLower
+	case (prev == classDigit) != (curr == classDigit):
+		return true
+	case (prev == classCJK) != (curr == classCJK):
+		return true
+	}
+	return false
+}
+
+// titleCase capitalizes every word while keeping the original separators
+func titleCase(text string) string {
+	runes := []rune(text)
+	var builder strings.Builder
+	start := 0
+
+	flush := func(end int) {
+		if start < end {
+			builder.WriteString(capitalize(string(runes[start:end])))
+		}
+	}
+
+	for i, r := range runes {
+		if classify(r) == classSeparator {
+			flush(i)
+			builder.WriteRune(r)
+			start = i + 1
+			continue
+		}
+		if i > start && isBoundary(runes, i) {
+			flush(i)
+			start = i
+		}
+	}
+	flush(len(runes))
+
+	return builder.String()
+}
+
+// capitalize upper-cases the first rune and lower-cases the rest
+func capitalize(word string) string {
+	runes := []rune(strings.ToLower(word))
+	if len(runes) > 0 {
+		runes[0] = unicode.ToUpper(runes[0])
+	}
+	return string(runes)
+}
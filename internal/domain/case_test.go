@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"my photo_final-v2", []string{"my", "photo", "final", "v", "2"}},
+		{"camelCaseName", []string{"camel", "Case", "Name"}},
+		{"HTTPServerLog", []string{"HTTP", "Server", "Log"}},
+		{"IMG2024summer", []string{"IMG", "2024", "summer"}},
+		{"写真IMG_0001", []string{"写真", "IMG", "0001"}},
+		{"東京タワーの夜景 2024", []string{"東京タワーの夜景", "2024"}},
+		{"  --  ", []string{}},
+		{"Привет мир", []string{"Привет", "мир"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SplitWords(tt.input))
+		})
+	}
+}
+
+func TestConvertCase(t *testing.T) {
+	input := "my photoFile 2024"
+
+	tests := []struct {
+		style    CaseStyle
+		expected string
+	}{
+		{CaseLower, "my photofile 2024"},
+		{CaseUpper, "MY PHOTOFILE 2024"},
+		{CaseTitle, "My PhotoFile 2024"},
+		{CaseCamel, "myPhotoFile2024"},
+		{CasePascal, "MyPhotoFile2024"},
+		{CaseSnake, "my_photo_file_2024"},
+		{CaseKebab, "my-photo-file-2024"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.style), func(t *testing.T) {
+			assert.Equal(t, tt.expected, ConvertCase(input, tt.style))
+		})
+	}
+}
+
+func TestConvertCase_CJK(t *testing.T) {
+	assert.Equal(t, "写真_img_0001", ConvertCase("写真IMG 0001", CaseSnake))
+	assert.Equal(t, "写真Img0001", ConvertCase("写真 IMG 0001", CaseCamel))
+	assert.Equal(t, "旅行 Day 1", ConvertCase("旅行 day 1", CaseTitle))
+}
+
+func TestCaseStrategy_Scope(t *testing.T) {
+	tests := []struct {
+		name     string
+		style    CaseStyle
+		scope    Scope
+		input    string
+		expected string
+	}{
+		{"stem only", CaseSnake, ScopeStem, "My Photo.JPG", "my_photo.JPG"},
+		{"extension only", CaseLower, ScopeExtension, "My Photo.JPG", "My Photo.jpg"},
+		{"both parts", CaseKebab, ScopeFull, "My Photo.JPG", "my-photo.jpg"},
+		{"no extension", CaseUpper, ScopeExtension, "Makefile", "Makefile"},
+		{"dotfile", CaseUpper, ScopeStem, ".bashrc", ".BASHRC"},
+		{"dotfile keeps dot", CasePascal, ScopeStem, ".my_config.json", ".MyConfig.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := NewCaseStrategy(tt.style, tt.scope)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, strategy.Apply(tt.input))
+		})
+	}
+}
+
+func TestNewCaseStrategy_Invalid(t *testing.T) {
+	_, err := NewCaseStrategy("sponge", ScopeStem)
+	assert.Error(t, err)
+
+	_, err = NewCaseStrategy(CaseLower, "middle")
+	assert.Error(t, err)
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// ConflictPolicy decides what happens when the target name already exists
+type ConflictPolicy string
+
+const (
+	ConflictSuffix    ConflictPolicy = "suffix"    // Add a numeric suffix (default)
+	ConflictSkip      ConflictPolicy = "skip"      // Leave the file as is
+	ConflictFail      ConflictPolicy = "fail"      // Abort the whole batch before renaming anything
+	ConflictOverwrite ConflictPolicy = "overwrite" // Replace the existing file
+	ConflictPrompt    ConflictPolicy = "prompt"    // Ask the user for each conflict
+)
+
+// ConflictOptions configures conflict handling (as sent by the frontend)
+type ConflictOptions struct {
+	Policy         ConflictPolicy `json:"policy"`
+	SuffixTemplate string         `json:"suffixTemplate"`
+	SuffixStart    int            `json:"suffixStart"`
+}
+
+// DefaultConflictOptions returns the original behavior: base+1, base+2, ... without separator
+func DefaultConflictOptions() ConflictOptions {
+	return ConflictOptions{
+		Policy:         ConflictSuffix,
+		SuffixTemplate: "{n}",
+		SuffixStart:    1,
+	}
+}
+
+// Validate checks the policy name and suffix template
+func (o ConflictOptions) Validate() error {
+	switch o.Policy {
+	case ConflictSuffix, ConflictSkip, ConflictFail, ConflictOverwrite, ConflictPrompt:
+	default:
+		return fmt.Errorf("unknown conflict policy: %q", o.Policy)
+	}
+
+	_, err := NewSuffixTemplate(o.SuffixTemplate, o.SuffixStart)
+	return err
+}
+
+// suffixPlaceholder matches {n} or {n:03} (zero padded to 3 digits)
+var suffixPlaceholder = regexp.MustCompile(`\{n(?::(0\d+))?\}`)
+
+// SuffixTemplate formats the counter added to a conflicting name
+// e.g. "{n}" → photo1.jpg, " ({n})" → photo (2).jpg, "_{n:03}" → photo_002.jpg
+type SuffixTemplate struct {
+	prefix string
+	suffix string
+	width  int
+	start  int
+}
+
+// NewSuffixTemplate parses a template containing exactly one {n} placeholder
+func NewSuffixTemplate(template string, start int) (*SuffixTemplate, error) {
+	matches := suffixPlaceholder.FindAllStringSubmatchIndex(template, -1)
+	if len(matches) != 1 {
+		return nil, fmt.Errorf("suffix template must contain exactly one {n}: %q", template)
+	}
+	if start < 0 {
+		return nil, fmt.Errorf("suffix start must not be negative: %d", start)
+	}
+
+	match := matches[0]
+	width := 0
+	if match[2] >= 0 {
+		width, _ = strconv.Atoi(template[match[2]:match[3]])
+	}
+
+	return &SuffixTemplate{
+		prefix: template[:match[0]],
+		suffix: template[match[1]:],
+		width:  width,
+		start:  start,
+	}, nil
+}
+
+// Start returns the first counter value to try
+func (t *SuffixTemplate) Start() int {
+	return t.start
+}
+
+// Apply inserts the formatted counter between the stem and the extension of name
+func (t *SuffixTemplate) Apply(name string, n int) string {
+	base, ext := SplitName(name)
+	return base + t.format(n) + ext
+}
+
+// ApplyToDirectory appends the formatted counter to a directory name (which has no extension)
+func (t *SuffixTemplate) ApplyToDirectory(name string, n int) string {
+	return name + t.format(n)
+}
+
+func (t *SuffixTemplate) format(n int) string {
+	return t.prefix + fmt.Sprintf("%0*d", t.width, n) + t.suffix
+}
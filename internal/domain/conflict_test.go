@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuffixTemplate_Apply(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		input    string
+		n        int
+		expected string
+	}{
+		{"default", "{n}", "photo.jpg", 1, "photo1.jpg"},
+		{"parenthesized", " ({n})", "photo.jpg", 2, "photo (2).jpg"},
+		{"zero padded", "_{n:03}", "photo.jpg", 2, "photo_002.jpg"},
+		{"no extension", "-{n}", "Makefile", 3, "Makefile-3"},
+		{"multi-part extension", " ({n})", "logs.tar.gz", 2, "logs (2).tar.gz"},
+		{"dotfile", "_{n}", ".bashrc", 1, ".bashrc_1"},
+		{"wider than padding", "_{n:02}", "a.txt", 123, "a_123.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := NewSuffixTemplate(tt.template, 1)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, template.Apply(tt.input, tt.n))
+		})
+	}
+}
+
+func TestSuffixTemplate_ApplyToDirectory(t *testing.T) {
+	template, err := NewSuffixTemplate(" ({n})", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "release.v2 (3)", template.ApplyToDirectory("release.v2", 3))
+}
+
+func TestNewSuffixTemplate_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		start    int
+	}{
+		{"missing placeholder", "_copy", 1},
+		{"two placeholders", "{n}_{n}", 1},
+		{"negative start", "{n}", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSuffixTemplate(tt.template, tt.start)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestConflictOptions_Validate(t *testing.T) {
+	assert.NoError(t, DefaultConflictOptions().Validate())
+	assert.NoError(t, ConflictOptions{Policy: ConflictSkip, SuffixTemplate: " ({n})", SuffixStart: 2}.Validate())
+	assert.Error(t, ConflictOptions{Policy: "rename-everything", SuffixTemplate: "{n}"}.Validate())
+	assert.Error(t, ConflictOptions{Policy: ConflictSuffix, SuffixTemplate: "copy"}.Validate())
+}
@@ -0,0 +1,32 @@
+package domain
+
+// RenameContext carries per-file information for strategies that need more than the name
+type RenameContext struct {
+	Index    int      // Position of the file in the batch (0-based)
+	Path     string   // Original path of the file ("" when unknown)
+	IsDir    bool     // The target is a directory (its name has no extension)
+	Metadata Metadata // Metadata of the file (only loaded for strategies that need it)
+}
+
+// SplitName splits name into stem and extension like the package-level SplitName,
+// except that directory names are all stem and have no extension
+func (c RenameContext) SplitName(name string) (stem, ext string) {
+	if c.IsDir {
+		return name, ""
+	}
+	return SplitName(name)
+}
+
+// ContextStrategy is implemented by strategies that depend on the file's position in the batch
+type ContextStrategy interface {
+	RenameStrategy
+	ApplyContext(filename string, ctx RenameContext) string
+}
+
+// ApplyStrategy applies strategy, passing ctx when the strategy supports it
+func ApplyStrategy(strategy RenameStrategy, filename string, ctx RenameContext) string {
+	if contextual, ok := strategy.(ContextStrategy); ok {
+		return contextual.ApplyContext(filename, ctx)
+	}
+	return strategy.Apply(filename)
+}
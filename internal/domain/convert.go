@@ -0,0 +1,314 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Conversion identifies a character conversion for Japanese names
+type Conversion string
+
+const (
+	ConvertHalfWidth     Conversion = "halfwidth"      // Full-width ASCII to half-width: ＡＢＣ１２３ → ABC123
+	ConvertFullWidth     Conversion = "fullwidth"      // Half-width ASCII to full-width: ABC123 → ＡＢＣ１２３
+	ConvertKanaFullWidth Conversion = "kana-fullwidth" // Half-width katakana to full-width: ｶﾞｲﾄﾞ → ガイド
+	ConvertHiragana      Conversion = "hiragana"       // Katakana to hiragana: カタカナ → かたかな
+	ConvertKatakana      Conversion = "katakana"       // Hiragana to katakana: ひらがな → ヒラガナ
+	ConvertKanjiNumbers  Conversion = "kanji-numbers"  // Kanji numerals to digits: 第十二回 → 第12回, 二〇二四 → 2024
+)
+
+// Validate checks the conversion name
+func (c Conversion) Validate() error {
+	switch c {
+	case ConvertHalfWidth, ConvertFullWidth, ConvertKanaFullWidth, ConvertHiragana, ConvertKatakana, ConvertKanjiNumbers:
+		return nil
+	}
+	return fmt.Errorf("unknown conversion: %q", c)
+}
+
+// Convert applies the conversion to text
+func (c Conversion) Convert(text string) string {
+	switch c {
+	case ConvertHalfWidth:
+		return strings.Map(toHalfWidth, text)
+	case ConvertFullWidth:
+		return strings.Map(toFullWidth, text)
+	case ConvertKanaFullWidth:
+		return kanaToFullWidth(text)
+	case ConvertHiragana:
+		return strings.Map(toHiragana, text)
+	case ConvertKatakana:
+		return strings.Map(toKatakana, text)
+	case ConvertKanjiNumbers:
+		return kanjiNumbersToDigits(text)
+	}
+	return text
+}
+
+// ConvertStrategy applies character conversions in order
+// e.g. [kana-fullwidth, halfwidth] turns "ｶﾀﾛｸﾞ　２０２４" into "カタログ 2024"
+type ConvertStrategy struct {
+	conversions []Conversion
+}
+
+// NewConvertStrategy creates a strategy applying conversions in order
+func NewConvertStrategy(conversions ...Conversion) (*ConvertStrategy, error) {
+	if len(conversions) == 0 {
+		return nil, fmt.Errorf("no conversion given")
+	}
+	for _, conversion := range conversions {
+		if err := conversion.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return &ConvertStrategy{conversions: conversions}, nil
+}
+
+// Apply converts filename
+func (s *ConvertStrategy) Apply(filename string) string {
+	for _, conversion := range s.conversions {
+		filename = conversion.Convert(filename)
+	}
+	return filename
+}
+
+// reservedFullWidth are full-width forms of characters that are not allowed in file names
+// (path separators and characters reserved by Windows); they are often used on purpose and are kept
+const reservedFullWidth = "／＼：＊？＂＜＞｜"
+
+// toHalfWidth maps full-width ASCII (U+FF01-U+FF5E) and the ideographic space to half-width
+func toHalfWidth(r rune) rune {
+	switch {
+	case r == '　':
+		return ' '
+	case r >= '！' && r <= '～' && !strings.ContainsRune(reservedFullWidth, r):
+		return r - '！' + '!'
+	}
+	return r
+}
+
+// toFullWidth maps printable ASCII to full-width
+// Characters not allowed in file names are kept, so the result has the same validity
+func toFullWidth(r rune) rune {
+	switch {
+	case r == ' ':
+		return '　'
+	case r >= '!' && r <= '~':
+		full := r - '!' + '！'
+		if !strings.ContainsRune(reservedFullWidth, full) {
+			return full
+		}
+	}
+	return r
+}
+
+// halfWidthKana maps half-width katakana and punctuation (U+FF61-U+FF9D) to full-width
+var halfWidthKana = []rune("。「」、・ヲァィゥェォャュョッーアイウエオカキクケコサシスセソタチツテトナニヌネノハヒフヘホマミムメモヤユヨラリルレロワン")
+
+// kanaToFullWidth converts half-width katakana to full-width, joining voiced sound marks
+// with the preceding kana (ｶﾞ → ガ, ﾊﾟ → パ, ｳﾞ → ヴ)
+func kanaToFullWidth(text string) string {
+	var builder strings.Builder
+	var previous rune // Last kana converted from half-width (0 if none)
+
+	for _, r := range text {
+		switch {
+		case r >= '｡' && r <= 'ﾝ':
+			if previous != 0 {
+				builder.WriteRune(previous)
+			}
+			previous = halfWidthKana[r-'｡']
+			continue
+		case r == 'ﾞ' || r == 'ﾟ':
+			mark, spacing := '゙', '゛'
+			if r == 'ﾟ' {
+				mark, spacing = '゚', '゜'
+			}
+			if previous != 0 {
+				if voiced := norm.NFC.String(string([]rune{previous, mark})); utf8.RuneCountInString(voiced) == 1 {
+					builder.WriteString(voiced)
+					previous = 0
+					continue
+				}
+				builder.WriteRune(previous)
+				previous = 0
+			}
+			builder.WriteRune(spacing)
+			continue
+		}
+
+		if previous != 0 {
+			builder.WriteRune(previous)
+			previous = 0
+		}
+		builder.WriteRune(r)
+	}
+	if previous != 0 {
+		builder.WriteRune(previous)
+	}
+
+	return builder.String()
+}
+
+// toHiragana maps katakana (ァ-ヶ, ヽ, ヾ) to hiragana; ー and katakana without hiragana (ヷ) are kept
+func toHiragana(r rune) rune {
+	if (r >= 'ァ' && r <= 'ヶ') || r == 'ヽ' || r == 'ヾ' {
+		return r - 0x60
+	}
+	return r
+}
+
+// toKatakana maps hiragana (ぁ-ゖ, ゝ, ゞ) to katakana
+func toKatakana(r rune) rune {
+	if (r >= 'ぁ' && r <= 'ゖ') || r == 'ゝ' || r == 'ゞ' {
+		return r + 0x60
+	}
+	return r
+}
+
+// kanjiDigits are the values of kanji digits
+var kanjiDigits = map[rune]int64{
+	'〇': 0, '零': 0, '一': 1, '二': 2, '三': 3, '四': 4,
+	'五': 5, '六': 6, '七': 7, '八': 8, '九': 9,
+}
+
+// kanjiUnits are the values of kanji units below 万
+var kanjiUnits = map[rune]int64{'十': 10, '百': 100, '千': 1000}
+
+// kanjiBigUnits are the values of kanji units that group four digits
+var kanjiBigUnits = map[rune]int64{'万': 10000, '億': 100000000}
+
+// kanjiCounters are counters that make the numerals before them a number (一巻, 十日, 三百円)
+// Counters that also form ordinary words with a numeral (一部, 十分, 一番) are left out
+var kanjiCounters = map[rune]bool{
+	'回': true, '年': true, '月': true, '日': true, '週': true, '巻': true, '話': true, '章': true,
+	'号': true, '版': true, '期': true, '歳': true, '枚': true, '冊': true, '曲': true, '位': true,
+	'円': true,
+}
+
+// isKanjiNumeral reports whether r is a kanji digit or unit
+func isKanjiNumeral(r rune) bool {
+	_, digit := kanjiDigits[r]
+	return digit || kanjiUnits[r] > 0 || kanjiBigUnits[r] > 0
+}
+
+// isKanjiUnit reports whether r is a kanji unit (十, 百, 万, ...)
+func isKanjiUnit(r rune) bool {
+	return kanjiUnits[r] > 0 || kanjiBigUnits[r] > 0
+}
+
+// kanjiNumbersToDigits replaces runs of kanji numerals with Arabic digits
+// Runs with units are read as numbers (二千二十四 → 2024), runs of digits only digit by digit (二〇二四 → 2024)
+// Runs outside a number context are words and are kept (一般, 三月兎, 八百屋, 万一, see numeralRunIsNumber)
+func kanjiNumbersToDigits(text string) string {
+	runes := []rune(text)
+	var builder strings.Builder
+
+	for i := 0; i < len(runes); {
+		if !isKanjiNumeral(runes[i]) {
+			builder.WriteRune(runes[i])
+			i++
+			continue
+		}
+
+		end := i
+		for end < len(runes) && isKanjiNumeral(runes[end]) {
+			end++
+		}
+		if numeralRunIsNumber(runes, i, end) {
+			builder.WriteString(kanjiNumber(runes[i:end]))
+		} else {
+			builder.WriteString(string(runes[i:end]))
+		}
+		i = end
+	}
+
+	return builder.String()
+}
+
+// numeralRunIsNumber reports whether the numerals runes[start:end] count something
+// Two or more digits without units always do (二〇二四); a run starting with 万 or 億 never does (万年筆, 万一)
+// Other runs need a number context: they follow 第 or a digit, precede a digit, stand apart from
+// other letters, or precede a counter that ends the word (一巻と, 十日, 三百円; 年 and 月 may be
+// followed by more numerals as in 三月十日, but not 三月兎 or 五月雨)
+func numeralRunIsNumber(runes []rune, start, end int) bool {
+	run := runes[start:end]
+	if kanjiBigUnits[run[0]] > 0 {
+		return false
+	}
+	if len(run) > 1 && !slices.ContainsFunc(run, isKanjiUnit) {
+		return true
+	}
+
+	if start > 0 && (runes[start-1] == '第' || unicode.IsDigit(runes[start-1])) {
+		return true
+	}
+	separatedBefore := start == 0 || !unicode.IsLetter(runes[start-1])
+	if end >= len(runes) {
+		return separatedBefore
+	}
+	next := runes[end]
+	if unicode.IsDigit(next) {
+		return true
+	}
+	if !unicode.IsLetter(next) {
+		return separatedBefore
+	}
+	if !kanjiCounters[next] {
+		return false
+	}
+	if end+1 >= len(runes) {
+		return true
+	}
+	after := runes[end+1]
+	if isKanjiNumeral(after) {
+		return next == '年' || next == '月'
+	}
+	return !unicode.Is(unicode.Han, after)
+}
+
+// kanjiNumber converts a run of kanji numerals
+func kanjiNumber(run []rune) string {
+	hasUnit, hasSmall := false, false
+	for _, r := range run {
+		if kanjiUnits[r] > 0 {
+			hasUnit, hasSmall = true, true
+		} else if kanjiBigUnits[r] > 0 {
+			hasUnit = true
+		} else {
+			hasSmall = true
+		}
+	}
+	if !hasSmall {
+		return string(run)
+	}
+
+	if !hasUnit {
+		var digits strings.Builder
+		for _, r := range run {
+			digits.WriteByte(byte('0' + kanjiDigits[r]))
+		}
+		return digits.String()
+	}
+
+	// total collects groups of 万/億, section the part below 万, current the pending digits
+	var total, section, current int64
+	for _, r := range run {
+		if digit, ok := kanjiDigits[r]; ok {
+			current = current*10 + digit
+		} else if unit := kanjiUnits[r]; unit > 0 {
+			section += max(current, 1) * unit
+			current = 0
+		} else {
+			total += max(section+current, 1) * kanjiBigUnits[r]
+			section, current = 0, 0
+		}
+	}
+	return strconv.FormatInt(total+section+current, 10)
+}
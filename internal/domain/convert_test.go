@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConversion_Convert(t *testing.T) {
+	tests := []struct {
+		name       string
+		conversion Conversion
+		input      string
+		expected   string
+	}{
+		{"halfwidth ascii", ConvertHalfWidth, "ＡＢＣ－１２３（ｖ２）", "ABC-123(v2)"},
+		{"halfwidth space", ConvertHalfWidth, "会議　資料", "会議 資料"},
+		{"halfwidth keeps reserved", ConvertHalfWidth, "Ａ／Ｂ：Ｃ？", "A／B：C？"},
+		{"halfwidth keeps kana", ConvertHalfWidth, "カタカナｶﾀｶﾅ", "カタカナｶﾀｶﾅ"},
+		{"fullwidth ascii", ConvertFullWidth, "ABC 123", "ＡＢＣ　１２３"},
+		{"fullwidth keeps reserved", ConvertFullWidth, "a.b", "ａ．ｂ"},
+		{"kana fullwidth", ConvertKanaFullWidth, "ｶﾀﾛｸﾞ", "カタログ"},
+		{"kana fullwidth handakuten", ConvertKanaFullWidth, "ﾊﾟﾝﾌﾚｯﾄ", "パンフレット"},
+		{"kana fullwidth vu", ConvertKanaFullWidth, "ｳﾞｨｰﾅｽ", "ヴィーナス"},
+		{"kana fullwidth punctuation", ConvertKanaFullWidth, "｢ﾒﾓ｣･ｰ", "「メモ」・ー"},
+		{"kana fullwidth lone mark", ConvertKanaFullWidth, "ｱﾞﾟ", "ア゛゜"},
+		{"kana fullwidth mark at start", ConvertKanaFullWidth, "ﾞｱ", "゛ア"},
+		{"kana fullwidth keeps ascii", ConvertKanaFullWidth, "IMG_ｶﾞ01.jpg", "IMG_ガ01.jpg"},
+		{"hiragana", ConvertHiragana, "カタカナ・ヴァイオリン", "かたかな・ゔぁいおりん"},
+		{"hiragana keeps long vowel", ConvertHiragana, "コーヒー", "こーひー"},
+		{"katakana", ConvertKatakana, "ひらがなゝ", "ヒラガナヽ"},
+		{"kanji numbers positional", ConvertKanjiNumbers, "二〇二四年", "2024年"},
+		{"kanji numbers units", ConvertKanjiNumbers, "第十二回", "第12回"},
+		{"kanji numbers ten", ConvertKanjiNumbers, "十日", "10日"},
+		{"kanji numbers large", ConvertKanjiNumbers, "二千二十四年", "2024年"},
+		{"kanji numbers man", ConvertKanjiNumbers, "三万五千円", "35000円"},
+		{"kanji numbers oku", ConvertKanjiNumbers, "一億二千万", "120000000"},
+		{"kanji numbers several", ConvertKanjiNumbers, "一巻と二十三巻", "1巻と23巻"},
+		{"kanji numbers keeps word", ConvertKanjiNumbers, "万年筆", "万年筆"},
+		{"kanji numbers date", ConvertKanjiNumbers, "三月十日_会議", "3月10日_会議"},
+		{"kanji numbers ordinal", ConvertKanjiNumbers, "第一話", "第1話"},
+		{"kanji numbers counter at end", ConvertKanjiNumbers, "写真 一枚", "写真 1枚"},
+		{"kanji numbers units before counter", ConvertKanjiNumbers, "八百円", "800円"},
+		{"kanji numbers units standing apart", ConvertKanjiNumbers, "予算_三百_案", "予算_300_案"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.conversion.Convert(tt.input))
+		})
+	}
+}
+
+func TestConvertStrategy_Apply(t *testing.T) {
+	strategy, err := NewConvertStrategy(ConvertKanaFullWidth, ConvertHalfWidth)
+	assert.NoError(t, err)
+	assert.Equal(t, "カタログ 2024", strategy.Apply("ｶﾀﾛｸﾞ　２０２４"))
+
+	_, err = NewConvertStrategy()
+	assert.Error(t, err)
+	_, err = NewConvertStrategy(ConvertHiragana, "romaji")
+	assert.Error(t, err)
+}
+
+func TestConvertKanjiNumbers_KeepsWords(t *testing.T) {
+	words := []string{
+		"一般", "一覧", "一緒", "統一", "唯一", "一部の資料", "十分な", "一番好き", "千葉",
+		"三月兎", "五月雨", "三日月", "一期一会", "一時保存", "一人旅", "七夕",
+		"万一の場合", "万一", "八百屋", "百万遍", "十二支", "四十七士",
+	}
+
+	for _, word := range words {
+		assert.Equal(t, word, ConvertKanjiNumbers.Convert(word), word)
+	}
+}
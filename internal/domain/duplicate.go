@@ -0,0 +1,39 @@
+package domain
+
+import "fmt"
+
+// DuplicateAction decides what happens to files whose contents duplicate an earlier file in the batch
+// The first file of each group is always renamed normally
+type DuplicateAction string
+
+const (
+	DuplicateKeep   DuplicateAction = "keep"   // Only flag duplicates in the preview (default)
+	DuplicateSkip   DuplicateAction = "skip"   // Leave duplicates unchanged
+	DuplicateTrash  DuplicateAction = "trash"  // Move duplicates to the trash
+	DuplicateShared DuplicateAction = "shared" // Give duplicates the first file's name plus a counter
+)
+
+// DuplicateOptions configures duplicate detection (as sent by the frontend)
+type DuplicateOptions struct {
+	Detect    bool            `json:"detect"`
+	Algorithm HashAlgorithm   `json:"algorithm"` // Digest used to compare contents (files of equal size only)
+	Action    DuplicateAction `json:"action"`
+}
+
+// DefaultDuplicateOptions returns detection turned off
+func DefaultDuplicateOptions() DuplicateOptions {
+	return DuplicateOptions{
+		Algorithm: HashSHA256,
+		Action:    DuplicateKeep,
+	}
+}
+
+// Validate checks the algorithm and action
+func (o DuplicateOptions) Validate() error {
+	switch o.Action {
+	case DuplicateKeep, DuplicateSkip, DuplicateTrash, DuplicateShared:
+	default:
+		return fmt.Errorf("unknown duplicate action: %q", o.Action)
+	}
+	return o.Algorithm.Validate()
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDuplicateOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options DuplicateOptions
+		wantErr bool
+	}{
+		{"default", DefaultDuplicateOptions(), false},
+		{"trash with xxh64", DuplicateOptions{Detect: true, Algorithm: HashXXH64, Action: DuplicateTrash}, false},
+		{"shared", DuplicateOptions{Detect: true, Algorithm: HashSHA256, Action: DuplicateShared}, false},
+		{"unknown action", DuplicateOptions{Detect: true, Algorithm: HashSHA256, Action: "delete"}, true},
+		{"empty action", DuplicateOptions{Algorithm: HashSHA256}, true},
+		{"unknown algorithm", DuplicateOptions{Algorithm: "crc32", Action: DuplicateSkip}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -11,6 +11,8 @@ type File struct {
 	originalName string
 	directory    string
 	newName      string
+	isDir        bool
+	duplicateOf  string // Path of the earlier file with the same contents ("" if unique)
 }
 
 // NewFile creates a new File entity
@@ -26,6 +28,28 @@ func NewFile(path string) *File {
 	}
 }
 
+// NewDirectory creates a File entity for a directory
+func NewDirectory(path string) *File {
+	file := NewFile(path)
+	file.isDir = true
+	return file
+}
+
+// IsDir returns true if the entity is a directory
+func (f *File) IsDir() bool {
+	return f.isDir
+}
+
+// DuplicateOf returns the path of an earlier file in the batch with the same contents ("" if none)
+func (f *File) DuplicateOf() string {
+	return f.duplicateOf
+}
+
+// SetDuplicateOf marks the file as a duplicate of the file at path ("" clears the mark)
+func (f *File) SetDuplicateOf(path string) {
+	f.duplicateOf = path
+}
+
 // OriginalPath returns the original file path
 func (f *File) OriginalPath() string {
 	return f.originalPath
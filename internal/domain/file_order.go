@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// FileOrder decides the order files are numbered and renamed in
+type FileOrder string
+
+const (
+	OrderSelection FileOrder = "selection" // As selected (default)
+	OrderName      FileOrder = "name"      // Byte-wise by name
+	OrderNatural   FileOrder = "natural"   // By name, digit runs compared as numbers (img2 < img10)
+	OrderModTime   FileOrder = "mtime"     // By modification time
+	OrderSize      FileOrder = "size"      // By file size
+)
+
+// ParseFileOrder validates an order name; empty means selection order
+func ParseFileOrder(order string) (FileOrder, error) {
+	switch FileOrder(order) {
+	case "":
+		return OrderSelection, nil
+	case OrderSelection, OrderName, OrderNatural, OrderModTime, OrderSize:
+		return FileOrder(order), nil
+	}
+	return "", fmt.Errorf("unknown file order: %q", order)
+}
+
+// NeedsStat reports whether the order compares file attributes
+func (o FileOrder) NeedsStat() bool {
+	return o == OrderModTime || o == OrderSize
+}
+
+// NaturalLess compares names treating runs of digits as numbers
+// Letters are compared case-insensitively first so "b" sorts after "A"
+// Full-width digits count by value, so "９" sorts before "10"
+func NaturalLess(a, b string) bool {
+	ra, rb := []rune(a), []rune(b)
+	i, j := 0, 0
+
+	for i < len(ra) && j < len(rb) {
+		if isNaturalDigit(ra[i]) && isNaturalDigit(rb[j]) {
+			// Compare digit runs by numeric value (ignoring leading zeros)
+			var na, nb string
+			na, i = digitRun(ra, i)
+			nb, j = digitRun(rb, j)
+			if len(na) != len(nb) {
+				return len(na) < len(nb)
+			}
+			if na != nb {
+				return na < nb
+			}
+			continue
+		}
+
+		ca, cb := unicode.ToLower(ra[i]), unicode.ToLower(rb[j])
+		if ca != cb {
+			return ca < cb
+		}
+		i++
+		j++
+	}
+
+	if len(ra)-i != len(rb)-j {
+		return len(ra)-i < len(rb)-j
+	}
+	// Fall back to byte order for a deterministic result (e.g. "a" vs "A", "01" vs "1")
+	return a < b
+}
+
+// isNaturalDigit reports whether r is an ASCII or full-width digit
+func isNaturalDigit(r rune) bool {
+	return ('0' <= r && r <= '9') || ('０' <= r && r <= '９')
+}
+
+// digitRun reads the digits starting at runes[start] as ASCII without leading zeros
+// and returns them with the index after the run
+func digitRun(runes []rune, start int) (string, int) {
+	var digits strings.Builder
+	end := start
+	for ; end < len(runes) && isNaturalDigit(runes[end]); end++ {
+		r := runes[end]
+		if r >= '０' {
+			r = r - '０' + '0'
+		}
+		if digits.Len() > 0 || r != '0' {
+			digits.WriteRune(r)
+		}
+	}
+	return digits.String(), end
+}
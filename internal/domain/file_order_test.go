@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNaturalLess(t *testing.T) {
+	names := []string{"img10.jpg", "img2.jpg", "IMG1.jpg", "img02.jpg", "a.txt", "img.jpg", "b1", "b"}
+	sort.SliceStable(names, func(i, j int) bool {
+		return NaturalLess(names[i], names[j])
+	})
+
+	assert.Equal(t, []string{"a.txt", "b", "b1", "img.jpg", "IMG1.jpg", "img02.jpg", "img2.jpg", "img10.jpg"}, names)
+}
+
+func TestNaturalLess_FullWidthDigits(t *testing.T) {
+	names := []string{"写真10", "写真９", "写真１１", "写真2"}
+	sort.SliceStable(names, func(i, j int) bool {
+		return NaturalLess(names[i], names[j])
+	})
+
+	assert.Equal(t, []string{"写真2", "写真９", "写真10", "写真１１"}, names)
+	assert.True(t, NaturalLess("９", "10"))
+	assert.False(t, NaturalLess("10", "９"))
+}
+
+func TestParseFileOrder(t *testing.T) {
+	order, err := ParseFileOrder("")
+	assert.NoError(t, err)
+	assert.Equal(t, OrderSelection, order)
+
+	order, err = ParseFileOrder("natural")
+	assert.NoError(t, err)
+	assert.Equal(t, OrderNatural, order)
+	assert.False(t, order.NeedsStat())
+	assert.True(t, OrderModTime.NeedsStat())
+
+	_, err = ParseFileOrder("random")
+	assert.Error(t, err)
+}
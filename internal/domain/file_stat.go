@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// FileStat holds the file attributes used by the use cases
+// Keeps the domain independent of os.FileInfo
+type FileStat struct {
+	Size       int64
+	ModTime    time.Time
+	ChangeTime time.Time // Last metadata change (zero where the platform has none, e.g. Windows)
+	BirthTime  time.Time // Creation time (zero where the platform or file system does not record it)
+	IsDir      bool
+}
+
+// DirEntry is a single entry of a directory listing
+type DirEntry struct {
+	Name  string
+	IsDir bool
+}
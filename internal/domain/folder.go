@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// TargetKind selects which entries of a folder become rename targets
+type TargetKind string
+
+const (
+	TargetFiles       TargetKind = "files"       // Files only (default)
+	TargetDirectories TargetKind = "directories" // Directories only
+	TargetBoth        TargetKind = "both"        // Files and directories
+)
+
+// IncludesFiles reports whether files are targets (empty means files)
+func (k TargetKind) IncludesFiles() bool {
+	return k != TargetDirectories
+}
+
+// IncludesDirectories reports whether directories are targets
+func (k TargetKind) IncludesDirectories() bool {
+	return k == TargetDirectories || k == TargetBoth
+}
+
+// FolderOptions configures how a folder is expanded into files
+type FolderOptions struct {
+	MaxDepth         int        `json:"maxDepth"`         // 0 = unlimited, 1 = the folder itself only
+	Include          []string   `json:"include"`          // Targets must match one of these (all when empty)
+	Exclude          []string   `json:"exclude"`          // Matching files and folders are skipped
+	Regex            bool       `json:"regex"`            // Include/exclude are regular expressions instead of globs
+	IncludeHidden    bool       `json:"includeHidden"`    // Include names starting with "."
+	RespectGitignore bool       `json:"respectGitignore"` // Skip paths ignored by .gitignore files
+	Targets          TargetKind `json:"targets"`          // Files, directories or both (empty means files)
+}
+
+// Validate checks the depth and target kind
+func (o FolderOptions) Validate() error {
+	if o.MaxDepth < 0 {
+		return fmt.Errorf("max depth must not be negative: %d", o.MaxDepth)
+	}
+	switch o.Targets {
+	case "", TargetFiles, TargetDirectories, TargetBoth:
+	default:
+		return fmt.Errorf("unknown targets: %q", o.Targets)
+	}
+	return nil
+}
+
+// pathMatcher matches a relative, slash-separated path
+type pathMatcher struct {
+	re       *regexp.Regexp
+	basename bool // Glob without "/" matches the name at any depth
+}
+
+func (m pathMatcher) match(relPath string) bool {
+	if m.basename {
+		return m.re.MatchString(path.Base(relPath))
+	}
+	return m.re.MatchString(relPath)
+}
+
+// FileFilter decides which files of a folder are selected
+// Globs are case-insensitive and match the name, or the relative path when they contain "/"
+// Regular expressions are searched in the relative path
+type FileFilter struct {
+	include []pathMatcher
+	exclude []pathMatcher
+}
+
+// NewFileFilter compiles include and exclude patterns
+func NewFileFilter(include, exclude []string, isRegex bool) (*FileFilter, error) {
+	includeMatchers, err := compileMatchers(include, isRegex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid include pattern: %w", err)
+	}
+	excludeMatchers, err := compileMatchers(exclude, isRegex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exclude pattern: %w", err)
+	}
+
+	return &FileFilter{
+		include: includeMatchers,
+		exclude: excludeMatchers,
+	}, nil
+}
+
+func compileMatchers(patterns []string, isRegex bool) ([]pathMatcher, error) {
+	matchers := make([]pathMatcher, 0, len(patterns))
+	for _, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+
+		if isRegex {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, err
+			}
+			matchers = append(matchers, pathMatcher{re: re})
+			continue
+		}
+
+		re, err := compileGlob(strings.TrimPrefix(pattern, "/"), true)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, pathMatcher{re: re, basename: !strings.Contains(pattern, "/")})
+	}
+	return matchers, nil
+}
+
+// Included reports whether a file passes the include patterns
+func (f *FileFilter) Included(relPath string) bool {
+	if len(f.include) == 0 {
+		return true
+	}
+	return matchAny(f.include, relPath)
+}
+
+// Excluded reports whether a file or folder matches an exclude pattern
+func (f *FileFilter) Excluded(relPath string) bool {
+	return matchAny(f.exclude, relPath)
+}
+
+func matchAny(matchers []pathMatcher, relPath string) bool {
+	for _, matcher := range matchers {
+		if matcher.match(relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsHiddenName reports whether name is hidden by Unix convention
+func IsHiddenName(name string) bool {
+	return strings.HasPrefix(name, ".")
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompileGlob(t *testing.T) {
+	tests := []struct {
+		glob     string
+		path     string
+		expected bool
+	}{
+		{"*.jpg", "photo.jpg", true},
+		{"*.jpg", "dir/photo.jpg", false},
+		{"IMG_????.jpg", "IMG_0001.jpg", true},
+		{"IMG_????.jpg", "IMG_001.jpg", false},
+		{"**/*.jpg", "photo.jpg", true},
+		{"**/*.jpg", "a/b/photo.jpg", true},
+		{"raw/**", "raw/a/b.cr2", true},
+		{"raw/**", "raws/a.cr2", false},
+		{"[a-c]*.txt", "b.txt", true},
+		{"[!a-c]*.txt", "b.txt", false},
+		{`\*.txt`, "*.txt", true},
+		{`\*.txt`, "a.txt", false},
+		{"写真*.jpg", "写真_01.jpg", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.glob+" "+tt.path, func(t *testing.T) {
+			re, err := compileGlob(tt.glob, false)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, re.MatchString(tt.path))
+		})
+	}
+}
+
+func TestFileFilter(t *testing.T) {
+	filter, err := NewFileFilter([]string{"*.jpg", "raw/*.cr2"}, []string{"thumbs", "*_tmp.*"}, false)
+	assert.NoError(t, err)
+
+	assert.True(t, filter.Included("a/b/photo.JPG"))
+	assert.True(t, filter.Included("raw/img.cr2"))
+	assert.False(t, filter.Included("other/img.cr2"))
+	assert.False(t, filter.Included("notes.txt"))
+	assert.True(t, filter.Excluded("a/thumbs"))
+	assert.True(t, filter.Excluded("a/photo_tmp.jpg"))
+	assert.False(t, filter.Excluded("a/photo.jpg"))
+}
+
+func TestFileFilter_Regex(t *testing.T) {
+	filter, err := NewFileFilter([]string{`^2024/.*\.jpg$`}, []string{`(^|/)cache/`}, true)
+	assert.NoError(t, err)
+
+	assert.True(t, filter.Included("2024/trip/a.jpg"))
+	assert.False(t, filter.Included("2023/a.jpg"))
+	assert.True(t, filter.Excluded("2024/cache/a.jpg"))
+}
+
+func TestFileFilter_NoIncludeMatchesAll(t *testing.T) {
+	filter, err := NewFileFilter(nil, nil, false)
+	assert.NoError(t, err)
+
+	assert.True(t, filter.Included("anything.bin"))
+	assert.False(t, filter.Excluded("anything.bin"))
+}
+
+func TestNewFileFilter_Invalid(t *testing.T) {
+	_, err := NewFileFilter([]string{"[abc"}, nil, false)
+	assert.Error(t, err)
+	_, err = NewFileFilter(nil, []string{"(unclosed"}, true)
+	assert.Error(t, err)
+}
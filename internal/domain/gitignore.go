@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// GitignoreFileName is the name of the ignore files read while walking a folder
+const GitignoreFileName = ".gitignore"
+
+// ignoreRule is a single pattern line of a .gitignore file
+type ignoreRule struct {
+	re       *regexp.Regexp
+	negate   bool // "!pattern" re-includes a path
+	dirOnly  bool // "pattern/" only matches directories
+	basename bool // Pattern without "/" matches the name at any depth
+}
+
+// Gitignore holds the rules of one .gitignore file
+// Patterns are relative to the folder containing the file (base)
+type Gitignore struct {
+	base  string
+	rules []ignoreRule
+}
+
+// ParseGitignore parses the content of a .gitignore located in base (slash-separated, relative to the walked root)
+// Invalid patterns are ignored like git does
+func ParseGitignore(base, content string) *Gitignore {
+	gitignore := &Gitignore{base: base}
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if !strings.HasSuffix(line, `\ `) {
+			line = strings.TrimRight(line, " ")
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var rule ignoreRule
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+
+		// A slash at the beginning or in the middle anchors the pattern to base
+		rule.basename = !strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+
+		re, err := compileGlob(line, false)
+		if err != nil {
+			continue
+		}
+		rule.re = re
+		gitignore.rules = append(gitignore.rules, rule)
+	}
+
+	return gitignore
+}
+
+// Match reports whether a rule matches relPath and whether the path is ignored
+// The last matching rule wins
+func (g *Gitignore) Match(relPath string, isDir bool) (matched, ignored bool) {
+	if g.base != "" {
+		if !strings.HasPrefix(relPath, g.base+"/") {
+			return false, false
+		}
+		relPath = strings.TrimPrefix(relPath, g.base+"/")
+	}
+
+	for _, rule := range g.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+
+		subject := relPath
+		if rule.basename {
+			subject = path.Base(relPath)
+		}
+		if rule.re.MatchString(subject) {
+			matched = true
+			ignored = !rule.negate
+		}
+	}
+	return matched, ignored
+}
+
+// IsIgnored checks relPath against nested .gitignore files ordered from the root down
+// Deeper files take precedence over their parents
+func IsIgnored(gitignores []*Gitignore, relPath string, isDir bool) bool {
+	ignored := false
+	for _, gitignore := range gitignores {
+		if matched, result := gitignore.Match(relPath, isDir); matched {
+			ignored = result
+		}
+	}
+	return ignored
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitignore_Match(t *testing.T) {
+	gitignore := ParseGitignore("", `
+# build output
+*.log
+!keep.log
+build/
+/root-only.txt
+docs/*.tmp
+**/cache
+\#not-a-comment
+`)
+
+	tests := []struct {
+		path     string
+		isDir    bool
+		expected bool
+	}{
+		{"debug.log", false, true},
+		{"sub/debug.log", false, true},
+		{"sub/keep.log", false, false},
+		{"build", true, true},
+		{"build", false, false},
+		{"src/build", true, true},
+		{"root-only.txt", false, true},
+		{"sub/root-only.txt", false, false},
+		{"docs/a.tmp", false, true},
+		{"docs/sub/a.tmp", false, false},
+		{"a/b/cache", true, true},
+		{"#not-a-comment", false, true},
+		{"main.go", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsIgnored([]*Gitignore{gitignore}, tt.path, tt.isDir))
+		})
+	}
+}
+
+func TestIsIgnored_Nested(t *testing.T) {
+	root := ParseGitignore("", "*.tmp\n")
+	nested := ParseGitignore("project", "!important.tmp\n/local.txt\n")
+
+	assert.True(t, IsIgnored([]*Gitignore{root, nested}, "project/scratch.tmp", false))
+	assert.False(t, IsIgnored([]*Gitignore{root, nested}, "project/important.tmp", false))
+	assert.True(t, IsIgnored([]*Gitignore{root, nested}, "important.tmp", false))
+	assert.True(t, IsIgnored([]*Gitignore{root, nested}, "project/local.txt", false))
+	assert.False(t, IsIgnored([]*Gitignore{root, nested}, "local.txt", false))
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// compileGlob converts a slash-separated glob into an anchored regular expression
+// "*" and "?" do not cross "/", "**/" matches any number of directories and "**" matches anything
+// Character classes ([a-z], [!0-9]) and backslash escapes are supported
+func compileGlob(glob string, caseInsensitive bool) (*regexp.Regexp, error) {
+	var builder strings.Builder
+	if caseInsensitive {
+		builder.WriteString("(?i)")
+	}
+	builder.WriteString("^")
+
+	runes := []rune(glob)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch r {
+		case '*':
+			if i+1 < len(runes) && runes[i+1] == '*' {
+				if i+2 < len(runes) && runes[i+2] == '/' {
+					builder.WriteString("(?:.*/)?")
+					i += 2
+				} else {
+					builder.WriteString(".*")
+					i++
+				}
+				continue
+			}
+			builder.WriteString("[^/]*")
+		case '?':
+			builder.WriteString("[^/]")
+		case '[':
+			end := strings.IndexRune(string(runes[i+1:]), ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed character class in %q", glob)
+			}
+			class := []rune(string(runes[i+1:])[:end])
+			if len(class) > 0 && class[0] == '!' {
+				class[0] = '^'
+			}
+			builder.WriteString("[" + strings.ReplaceAll(string(class), `\`, `\\`) + "]")
+			i += len(class) + 1
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+				builder.WriteString(regexp.QuoteMeta(string(runes[i])))
+			}
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+
+	builder.WriteString("$")
+	return regexp.Compile(builder.String())
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HashAlgorithm identifies a content digest
+type HashAlgorithm string
+
+const (
+	HashSHA256 HashAlgorithm = "sha256"
+	HashSHA1   HashAlgorithm = "sha1"
+	HashMD5    HashAlgorithm = "md5"
+	HashXXH64  HashAlgorithm = "xxh64" // Fast non-cryptographic hash
+)
+
+// hashFieldPrefix namespaces digest fields in metadata, e.g. "hash.sha256"
+const hashFieldPrefix = "hash."
+
+// Validate checks that the algorithm is supported
+func (a HashAlgorithm) Validate() error {
+	switch a {
+	case HashSHA256, HashSHA1, HashMD5, HashXXH64:
+		return nil
+	}
+	return fmt.Errorf("unknown hash algorithm: %q", a)
+}
+
+// HashField returns the metadata field holding the hex digest of algorithm
+func HashField(algorithm HashAlgorithm) string {
+	return hashFieldPrefix + string(algorithm)
+}
+
+// ParseHashField returns the algorithm of a digest field like "hash.sha256"
+func ParseHashField(key string) (HashAlgorithm, bool) {
+	name, ok := strings.CutPrefix(key, hashFieldPrefix)
+	if !ok || HashAlgorithm(name).Validate() != nil {
+		return "", false
+	}
+	return HashAlgorithm(name), true
+}
+
+// HashOptions configures hash based naming (as sent by the frontend)
+type HashOptions struct {
+	Algorithm HashAlgorithm `json:"algorithm"`
+	Length    int           `json:"length"`    // Hex digits kept (0 = full digest)
+	KeepStem  bool          `json:"keepStem"`  // Keep the original name before the digest
+	Separator string        `json:"separator"` // Between stem and digest
+}
+
+// HashStrategy names files by their content digest, e.g. photo.jpg → 3a7bd3e2.jpg
+// or photo_3a7bd3e2.jpg with KeepStem; the extension is kept
+// The digest is computed by the use case and passed as metadata; directories are not renamed
+type HashStrategy struct {
+	options HashOptions
+}
+
+// NewHashStrategy creates a hash naming strategy
+func NewHashStrategy(options HashOptions) (*HashStrategy, error) {
+	if err := options.Algorithm.Validate(); err != nil {
+		return nil, err
+	}
+	if options.Length < 0 {
+		return nil, fmt.Errorf("hash length must not be negative: %d", options.Length)
+	}
+	return &HashStrategy{options: options}, nil
+}
+
+// MetadataFields returns the digest field of the configured algorithm
+func (s *HashStrategy) MetadataFields() []string {
+	return []string{HashField(s.options.Algorithm)}
+}
+
+// Apply leaves filename unchanged (the digest needs the file contents)
+func (s *HashStrategy) Apply(filename string) string {
+	return s.ApplyContext(filename, RenameContext{})
+}
+
+// ApplyContext replaces the stem with the digest from ctx (unchanged when there is none)
+func (s *HashStrategy) ApplyContext(filename string, ctx RenameContext) string {
+	digest, ok := ctx.Metadata[HashField(s.options.Algorithm)].(string)
+	if !ok || digest == "" || ctx.IsDir {
+		return filename
+	}
+	digest = truncateDigest(digest, s.options.Length)
+
+	stem, ext := ctx.SplitName(filename)
+	if s.options.KeepStem {
+		return stem + s.options.Separator + digest + ext
+	}
+	return digest + ext
+}
+
+// truncateDigest keeps the first length hex digits (all when length is 0 or too large)
+func truncateDigest(digest string, length int) string {
+	if length > 0 && length < len(digest) {
+		return digest[:length]
+	}
+	return digest
+}
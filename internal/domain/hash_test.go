@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashStrategy_ApplyContext(t *testing.T) {
+	const digest = "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+	metadata := Metadata{HashField(HashSHA256): digest}
+
+	tests := []struct {
+		name     string
+		options  HashOptions
+		filename string
+		ctx      RenameContext
+		expected string
+	}{
+		{"full digest", HashOptions{Algorithm: HashSHA256}, "photo.jpg", RenameContext{Metadata: metadata}, digest + ".jpg"},
+		{"truncated", HashOptions{Algorithm: HashSHA256, Length: 8}, "photo.JPG", RenameContext{Metadata: metadata}, "ba7816bf.JPG"},
+		{"keep stem", HashOptions{Algorithm: HashSHA256, Length: 8, KeepStem: true, Separator: "_"}, "logs.tar.gz", RenameContext{Metadata: metadata}, "logs_ba7816bf.tar.gz"},
+		{"length over digest", HashOptions{Algorithm: HashSHA256, Length: 100}, "a", RenameContext{Metadata: metadata}, digest},
+		{"no digest", HashOptions{Algorithm: HashSHA256}, "photo.jpg", RenameContext{}, "photo.jpg"},
+		{"other algorithm", HashOptions{Algorithm: HashMD5}, "photo.jpg", RenameContext{Metadata: metadata}, "photo.jpg"},
+		{"directory", HashOptions{Algorithm: HashSHA256}, "photos", RenameContext{IsDir: true, Metadata: metadata}, "photos"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := NewHashStrategy(tt.options)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, strategy.ApplyContext(tt.filename, tt.ctx))
+		})
+	}
+}
+
+func TestNewHashStrategy_Errors(t *testing.T) {
+	_, err := NewHashStrategy(HashOptions{Algorithm: "crc32"})
+	assert.Error(t, err)
+
+	_, err = NewHashStrategy(HashOptions{Algorithm: HashXXH64, Length: -1})
+	assert.Error(t, err)
+
+	_, err = RuleConfig{Type: RuleHash, Enabled: true}.Build()
+	assert.Error(t, err)
+}
+
+func TestHashFields(t *testing.T) {
+	strategy, err := NewHashStrategy(HashOptions{Algorithm: HashXXH64})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"hash.xxh64"}, MetadataFields(strategy))
+
+	algorithm, ok := ParseHashField("hash.md5")
+	assert.True(t, ok)
+	assert.Equal(t, HashMD5, algorithm)
+	_, ok = ParseHashField("hash.crc32")
+	assert.False(t, ok)
+
+	// Digests are also available in templates
+	template, err := NewTemplateStrategy("{stem}-{hash.sha1:7}")
+	assert.NoError(t, err)
+	assert.Equal(t, "a-a9993e3.txt", template.ApplyContext("a.txt", RenameContext{
+		Metadata: Metadata{HashField(HashSHA1): "a9993e364706816aba3e25717850c26c9cd0d89d"},
+	}))
+
+	_, err = NewTemplateStrategy("{hash.sha1:short}")
+	assert.Error(t, err)
+	_, err = NewTemplateStrategy("{hash.crc32}")
+	assert.Error(t, err)
+}
@@ -1,13 +1,35 @@
 package domain
 
+import "reflect"
+
 const MaxHistorySize = 100
 
 // HistoryEntry represents a single history entry
+// Rules is set when a whole pipeline was used instead of a single pattern
 type HistoryEntry struct {
-	Pattern       string `json:"pattern"`
-	Replacement   string `json:"replacement"`
-	IsRegex       bool   `json:"isRegex"`
-	CaseInsensitive bool `json:"caseInsensitive"`
+	Pattern         string       `json:"pattern"`
+	Replacement     string       `json:"replacement"`
+	IsRegex         bool         `json:"isRegex"`
+	CaseInsensitive bool         `json:"caseInsensitive"`
+	Normalize       bool         `json:"normalize,omitempty"` // Pattern matched in NFC
+	Rules           []RuleConfig `json:"rules,omitempty"`
+}
+
+// Equal reports whether two entries describe the same rename
+func (e HistoryEntry) Equal(other HistoryEntry) bool {
+	if e.Pattern != other.Pattern ||
+		e.Replacement != other.Replacement ||
+		e.IsRegex != other.IsRegex ||
+		e.CaseInsensitive != other.CaseInsensitive ||
+		e.Normalize != other.Normalize {
+		return false
+	}
+
+	// nil and empty rules are the same (omitted in JSON)
+	if len(e.Rules) == 0 || len(other.Rules) == 0 {
+		return len(e.Rules) == len(other.Rules)
+	}
+	return reflect.DeepEqual(e.Rules, other.Rules)
 }
 
 // History manages rename history
@@ -28,10 +50,7 @@ func NewHistory() *History {
 func (h *History) Add(entry HistoryEntry) {
 	// Check for duplicate
 	for i, existing := range h.entries {
-		if existing.Pattern == entry.Pattern &&
-			existing.Replacement == entry.Replacement &&
-			existing.IsRegex == entry.IsRegex &&
-			existing.CaseInsensitive == entry.CaseInsensitive {
+		if existing.Equal(entry) {
 			// Move to front
 			h.entries = append([]HistoryEntry{entry}, append(h.entries[:i], h.entries[i+1:]...)...)
 			return
@@ -83,3 +83,37 @@ func TestHistory_DuplicateHandling(t *testing.T) {
 	// Duplicate should be moved to front, not added twice
 	assert.Equal(t, 1, history.Count())
 }
+
+func TestHistory_PipelineEntries(t *testing.T) {
+	history := NewHistory()
+
+	pipeline := HistoryEntry{
+		Rules: []RuleConfig{
+			{Type: RuleReplace, Enabled: true, Pattern: "DSC_"},
+			{Type: RuleNumbering, Enabled: true, Numbering: &NumberingOptions{Start: 1, Step: 1, Position: NumberPrefix}},
+		},
+	}
+	samePipeline := HistoryEntry{
+		Rules: []RuleConfig{
+			{Type: RuleReplace, Enabled: true, Pattern: "DSC_"},
+			{Type: RuleNumbering, Enabled: true, Numbering: &NumberingOptions{Start: 1, Step: 1, Position: NumberPrefix}},
+		},
+	}
+	otherPipeline := HistoryEntry{
+		Rules: []RuleConfig{
+			{Type: RuleReplace, Enabled: false, Pattern: "DSC_"},
+		},
+	}
+
+	history.Add(pipeline)
+	history.Add(otherPipeline)
+	history.Add(samePipeline)
+
+	// Identical pipelines are treated as duplicates
+	assert.Equal(t, 2, history.Count())
+	assert.Equal(t, samePipeline, history.GetAll()[0])
+
+	// A plain pattern entry differs from a pipeline even with an empty pattern
+	assert.False(t, HistoryEntry{}.Equal(pipeline))
+	assert.True(t, HistoryEntry{Rules: []RuleConfig{}}.Equal(HistoryEntry{}))
+}
@@ -0,0 +1,80 @@
+package domain
+
+import "time"
+
+const MaxJournalSize = 50
+
+// RenameOperation represents a single rename that was actually performed
+// Size and ModTime describe the renamed file so later changes can be detected
+type RenameOperation struct {
+	OldPath string    `json:"oldPath"`
+	NewPath string    `json:"newPath"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+}
+
+// RenameBatch groups the operations of one executed rename
+type RenameBatch struct {
+	ExecutedAt time.Time         `json:"executedAt"`
+	Operations []RenameOperation `json:"operations"`
+}
+
+// Journal keeps executed rename batches so they can be undone
+// Following SRP (Single Responsibility Principle) - only manages batches
+type Journal struct {
+	batches []RenameBatch
+}
+
+// NewJournal creates a new empty Journal
+func NewJournal() *Journal {
+	return &Journal{
+		batches: make([]RenameBatch, 0, MaxJournalSize),
+	}
+}
+
+// Add records a new batch as the most recent one
+func (j *Journal) Add(batch RenameBatch) {
+	j.batches = append([]RenameBatch{batch}, j.batches...)
+
+	// Keep only MaxJournalSize batches
+	if len(j.batches) > MaxJournalSize {
+		j.batches = j.batches[:MaxJournalSize]
+	}
+}
+
+// TakeRecent removes and returns up to n most recent batches (most recent first)
+func (j *Journal) TakeRecent(n int) []RenameBatch {
+	if n > len(j.batches) {
+		n = len(j.batches)
+	}
+	if n <= 0 {
+		return []RenameBatch{}
+	}
+
+	taken := j.batches[:n]
+	j.batches = append([]RenameBatch{}, j.batches[n:]...)
+	return taken
+}
+
+// PutBack returns batches taken by TakeRecent (most recent first) to the front of the journal
+func (j *Journal) PutBack(batches []RenameBatch) {
+	j.batches = append(append([]RenameBatch{}, batches...), j.batches...)
+	if len(j.batches) > MaxJournalSize {
+		j.batches = j.batches[:MaxJournalSize]
+	}
+}
+
+// GetAll returns all batches (most recent first)
+func (j *Journal) GetAll() []RenameBatch {
+	return j.batches
+}
+
+// Count returns the number of batches
+func (j *Journal) Count() int {
+	return len(j.batches)
+}
+
+// SetBatches sets the batches directly (for repository loading)
+func (j *Journal) SetBatches(batches []RenameBatch) {
+	j.batches = batches
+}
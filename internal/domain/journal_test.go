@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestBatch(oldPath, newPath string) RenameBatch {
+	return RenameBatch{
+		Operations: []RenameOperation{{OldPath: oldPath, NewPath: newPath}},
+	}
+}
+
+func TestNewJournal(t *testing.T) {
+	journal := NewJournal()
+	assert.NotNil(t, journal)
+	assert.Equal(t, 0, journal.Count())
+}
+
+func TestJournal_Add(t *testing.T) {
+	journal := NewJournal()
+
+	journal.Add(newTestBatch("/a.txt", "/b.txt"))
+	journal.Add(newTestBatch("/c.txt", "/d.txt"))
+
+	batches := journal.GetAll()
+	assert.Equal(t, 2, len(batches))
+	// Most recent first
+	assert.Equal(t, "/c.txt", batches[0].Operations[0].OldPath)
+	assert.Equal(t, "/a.txt", batches[1].Operations[0].OldPath)
+}
+
+func TestJournal_MaxLimit(t *testing.T) {
+	journal := NewJournal()
+
+	for i := 0; i < MaxJournalSize+10; i++ {
+		journal.Add(newTestBatch("/old", "/new"))
+	}
+
+	assert.Equal(t, MaxJournalSize, journal.Count())
+}
+
+func TestJournal_TakeRecent(t *testing.T) {
+	journal := NewJournal()
+	journal.Add(newTestBatch("/1", "/1new"))
+	journal.Add(newTestBatch("/2", "/2new"))
+	journal.Add(newTestBatch("/3", "/3new"))
+
+	taken := journal.TakeRecent(2)
+	assert.Equal(t, 2, len(taken))
+	assert.Equal(t, "/3", taken[0].Operations[0].OldPath)
+	assert.Equal(t, "/2", taken[1].Operations[0].OldPath)
+	assert.Equal(t, 1, journal.Count())
+	assert.Equal(t, "/1", journal.GetAll()[0].Operations[0].OldPath)
+
+	// Taking more than available returns what is left
+	taken = journal.TakeRecent(5)
+	assert.Equal(t, 1, len(taken))
+	assert.Equal(t, 0, journal.Count())
+
+	assert.Equal(t, 0, len(journal.TakeRecent(1)))
+}
+
+func TestJournal_PutBack(t *testing.T) {
+	journal := NewJournal()
+	journal.Add(newTestBatch("/1", "/1new"))
+	journal.Add(newTestBatch("/2", "/2new"))
+	journal.Add(newTestBatch("/3", "/3new"))
+
+	taken := journal.TakeRecent(2)
+	journal.PutBack(taken)
+
+	batches := journal.GetAll()
+	assert.Equal(t, 3, len(batches))
+	assert.Equal(t, "/3", batches[0].Operations[0].OldPath)
+	assert.Equal(t, "/2", batches[1].Operations[0].OldPath)
+	assert.Equal(t, "/1", batches[2].Operations[0].OldPath)
+}
@@ -0,0 +1,181 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Metadata holds metadata fields of a file keyed like "exif.date", "exif.model" or "artist"
+// Values are string, int, float64 or time.Time
+type Metadata map[string]any
+
+// Metadata fields read from EXIF
+const (
+	FieldEXIFDate      = "exif.date"
+	FieldEXIFMake      = "exif.make"
+	FieldEXIFModel     = "exif.model"
+	FieldEXIFLens      = "exif.lens"
+	FieldEXIFISO       = "exif.iso"
+	FieldEXIFFNumber   = "exif.fnumber"
+	FieldEXIFFocal     = "exif.focal"
+	FieldEXIFLatitude  = "exif.gps.lat"
+	FieldEXIFLongitude = "exif.gps.lon"
+	FieldEXIFAltitude  = "exif.gps.alt"
+)
+
+// Metadata fields read from audio tags (ID3, Vorbis comments, MP4 atoms)
+const (
+	FieldTitle  = "title"
+	FieldArtist = "artist"
+	FieldAlbum  = "album"
+	FieldTrack  = "track"
+	FieldDisc   = "disc"
+	FieldYear   = "year"
+)
+
+// Metadata fields read from the file system
+const (
+	FieldModTime    = "mtime" // Modification time
+	FieldChangeTime = "ctime" // Last metadata change (not available on Windows)
+	FieldBirthTime  = "btime" // Creation time (where the platform and file system record it)
+	FieldSize       = "size"  // Size in bytes ({size:human} → 1.5 MB)
+)
+
+// DefaultDateLayout formats dates without an explicit layout (safe for file names)
+const DefaultDateLayout = "2006-01-02_150405"
+
+// metadataFields are the metadata fields usable in templates
+var metadataFields = map[string]bool{
+	FieldEXIFDate: true, FieldEXIFMake: true, FieldEXIFModel: true, FieldEXIFLens: true, FieldEXIFISO: true,
+	FieldEXIFFNumber: true, FieldEXIFFocal: true, FieldEXIFLatitude: true, FieldEXIFLongitude: true, FieldEXIFAltitude: true,
+	FieldTitle: true, FieldArtist: true, FieldAlbum: true, FieldTrack: true, FieldDisc: true, FieldYear: true,
+	FieldModTime: true, FieldChangeTime: true, FieldBirthTime: true, FieldSize: true,
+}
+
+// IsFileStatField reports whether key is read from the file system rather than the file contents
+func IsFileStatField(key string) bool {
+	switch key {
+	case FieldModTime, FieldChangeTime, FieldBirthTime, FieldSize:
+		return true
+	}
+	return false
+}
+
+// StatMetadata returns the file system fields of stat (timestamps the platform lacks are omitted)
+func StatMetadata(stat FileStat) Metadata {
+	fields := Metadata{
+		FieldModTime: stat.ModTime,
+		FieldSize:    stat.Size,
+	}
+	if !stat.ChangeTime.IsZero() {
+		fields[FieldChangeTime] = stat.ChangeTime
+	}
+	if !stat.BirthTime.IsZero() {
+		fields[FieldBirthTime] = stat.BirthTime
+	}
+	return fields
+}
+
+// IsMetadataField reports whether key names a metadata field (as opposed to a file name field)
+func IsMetadataField(key string) bool {
+	if metadataFields[key] {
+		return true
+	}
+	_, ok := ParseHashField(key)
+	return ok
+}
+
+// Format returns the field as text ("" when missing)
+// arg is a Go time layout for dates, the minimum digits (zero padded) or "human" (as a byte size)
+// for integers, the number of decimals for other numbers and the digits kept for digests
+func (m Metadata) Format(key, arg string) string {
+	switch value := m[key].(type) {
+	case string:
+		if _, ok := ParseHashField(key); ok {
+			length, _ := strconv.Atoi(arg)
+			return truncateDigest(value, length)
+		}
+		return value
+	case int:
+		return formatCount(int64(value), arg)
+	case int64:
+		return formatCount(value, arg)
+	case float64:
+		precision := -1
+		if digits, err := strconv.Atoi(arg); err == nil && digits >= 0 {
+			precision = digits
+		}
+		return strconv.FormatFloat(value, 'f', precision, 64)
+	case time.Time:
+		if arg == "" {
+			arg = DefaultDateLayout
+		}
+		return value.Format(arg)
+	}
+	return ""
+}
+
+// formatCount formats an integer field, as a byte count when arg is "human"
+func formatCount(value int64, arg string) string {
+	if arg == "human" {
+		return humanSize(value)
+	}
+	return formatInt(value, arg)
+}
+
+// formatInt formats value zero padded to the number of digits in arg (if any)
+func formatInt(value int64, arg string) string {
+	if digits, err := strconv.Atoi(arg); err == nil && digits > 0 {
+		return fmt.Sprintf("%0*d", digits, value)
+	}
+	return strconv.FormatInt(value, 10)
+}
+
+// humanSize formats a byte count with binary units, e.g. 1536 → "1.5 KB"
+func humanSize(size int64) string {
+	const units = "KMGTPE"
+	if size < 1024 {
+		return fmt.Sprintf("%d B", size)
+	}
+	value := float64(size)
+	unit := -1
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	text := strconv.FormatFloat(value, 'f', 1, 64)
+	return strings.TrimSuffix(text, ".0") + " " + string(units[unit]) + "B"
+}
+
+// Strings returns all fields formatted with their defaults (nil when there are none)
+func (m Metadata) Strings() map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+	fields := make(map[string]string, len(m))
+	for key := range m {
+		fields[key] = m.Format(key, "")
+	}
+	return fields
+}
+
+// MetadataConsumer is implemented by strategies that read metadata from the context
+// Metadata is only loaded when the strategy needs it
+type MetadataConsumer interface {
+	MetadataFields() []string
+}
+
+// MetadataFields returns the metadata fields strategy reads (nil when none)
+func MetadataFields(strategy RenameStrategy) []string {
+	if consumer, ok := strategy.(MetadataConsumer); ok {
+		return consumer.MetadataFields()
+	}
+	return nil
+}
+
+// NeedsMetadata reports whether strategy reads metadata
+func NeedsMetadata(strategy RenameStrategy) bool {
+	return len(MetadataFields(strategy)) > 0
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"fmt"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizationForm is a Unicode normalization form
+// macOS file systems return names in NFD (が is stored as か + combining dakuten),
+// while names typed on Windows and Linux are usually NFC
+type NormalizationForm string
+
+const (
+	FormNFC  NormalizationForm = "nfc"  // Composed (Windows, Linux, typed text)
+	FormNFD  NormalizationForm = "nfd"  // Decomposed (macOS)
+	FormNFKC NormalizationForm = "nfkc" // Composed, compatibility characters folded (ｶﾞ → ガ, ① → 1)
+	FormNFKD NormalizationForm = "nfkd" // Decomposed, compatibility characters folded
+)
+
+// Validate checks the form name
+func (f NormalizationForm) Validate() error {
+	switch f {
+	case FormNFC, FormNFD, FormNFKC, FormNFKD:
+		return nil
+	}
+	return fmt.Errorf("unknown normalization form: %q", f)
+}
+
+// Normalize returns text in form f
+func (f NormalizationForm) Normalize(text string) string {
+	switch f {
+	case FormNFD:
+		return norm.NFD.String(text)
+	case FormNFKC:
+		return norm.NFKC.String(text)
+	case FormNFKD:
+		return norm.NFKD.String(text)
+	}
+	return norm.NFC.String(text)
+}
+
+// NormalizeStrategy converts names to a Unicode normalization form
+// e.g. NFD names copied from a Mac become NFC so they display correctly on Windows shares
+type NormalizeStrategy struct {
+	form NormalizationForm
+}
+
+// NewNormalizeStrategy creates a strategy converting names to form
+func NewNormalizeStrategy(form NormalizationForm) (*NormalizeStrategy, error) {
+	if err := form.Validate(); err != nil {
+		return nil, err
+	}
+	return &NormalizeStrategy{form: form}, nil
+}
+
+// Apply normalizes filename
+func (s *NormalizeStrategy) Apply(filename string) string {
+	return s.form.Normalize(filename)
+}
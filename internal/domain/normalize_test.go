@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeStrategy_Apply(t *testing.T) {
+	tests := []struct {
+		name     string
+		form     NormalizationForm
+		input    string
+		expected string
+	}{
+		{"nfd to nfc", FormNFC, "パン.txt", "パン.txt"},
+		{"nfc to nfd", FormNFD, "パン.txt", "パン.txt"},
+		{"nfc keeps half-width", FormNFC, "ｶﾞｲﾄﾞ.txt", "ｶﾞｲﾄﾞ.txt"},
+		{"nfkc folds half-width", FormNFKC, "ｶﾞｲﾄﾞ.txt", "ガイド.txt"},
+		{"nfkc folds full-width and circled", FormNFKC, "ＡＢＣ①.txt", "ABC1.txt"},
+		{"nfkd decomposes", FormNFKD, "ｶﾞ.txt", "ガ.txt"},
+		{"ascii unchanged", FormNFD, "report.txt", "report.txt"},
+		{"accented latin", FormNFC, "café.txt", "café.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := NewNormalizeStrategy(tt.form)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, strategy.Apply(tt.input))
+		})
+	}
+}
+
+func TestNewNormalizeStrategy_Invalid(t *testing.T) {
+	_, err := NewNormalizeStrategy("nfx")
+	assert.Error(t, err)
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NumberPosition decides where the sequence number is placed
+type NumberPosition string
+
+const (
+	NumberPrefix      NumberPosition = "prefix"      // 0001_photo.jpg
+	NumberSuffix      NumberPosition = "suffix"      // photo_0001.jpg (before the extension)
+	NumberPlaceholder NumberPosition = "placeholder" // Replaces {n} in the name, e.g. IMG_{n}.jpg
+)
+
+// NumberPlaceholderToken is replaced by the number in placeholder mode
+const NumberPlaceholderToken = "{n}"
+
+// NumberingOptions configures sequence numbering (as sent by the frontend)
+type NumberingOptions struct {
+	Start     int            `json:"start"`
+	Step      int            `json:"step"`
+	Padding   int            `json:"padding"` // Minimum digits, zero padded
+	Position  NumberPosition `json:"position"`
+	Separator string         `json:"separator"` // Between number and name (prefix/suffix only)
+}
+
+// NumberingStrategy numbers files by their position in the batch
+// Following Decorator Pattern - optionally wraps another strategy applied first
+type NumberingStrategy struct {
+	base    RenameStrategy
+	options NumberingOptions
+}
+
+// NewNumberingStrategy creates a numbering strategy; base may be nil
+func NewNumberingStrategy(base RenameStrategy, options NumberingOptions) (*NumberingStrategy, error) {
+	switch options.Position {
+	case NumberPrefix, NumberSuffix, NumberPlaceholder:
+	default:
+		return nil, fmt.Errorf("unknown number position: %q", options.Position)
+	}
+	if options.Step == 0 {
+		return nil, fmt.Errorf("number step must not be zero")
+	}
+	if options.Padding < 0 {
+		return nil, fmt.Errorf("number padding must not be negative: %d", options.Padding)
+	}
+
+	return &NumberingStrategy{
+		base:    base,
+		options: options,
+	}, nil
+}
+
+// Apply numbers filename as the first file of the batch
+func (s *NumberingStrategy) Apply(filename string) string {
+	return s.ApplyContext(filename, RenameContext{})
+}
+
+// MetadataFields returns the metadata fields read by the wrapped strategy
+func (s *NumberingStrategy) MetadataFields() []string {
+	if s.base == nil {
+		return nil
+	}
+	return MetadataFields(s.base)
+}
+
+// ApplyContext applies the wrapped strategy, then inserts the number for ctx.Index
+func (s *NumberingStrategy) ApplyContext(filename string, ctx RenameContext) string {
+	name := filename
+	if s.base != nil {
+		name = ApplyStrategy(s.base, name, ctx)
+	}
+
+	number := s.format(s.options.Start + ctx.Index*s.options.Step)
+
+	switch s.options.Position {
+	case NumberPrefix:
+		return number + s.options.Separator + name
+	case NumberSuffix:
+		stem, ext := ctx.SplitName(name)
+		return stem + s.options.Separator + number + ext
+	}
+	return strings.ReplaceAll(name, NumberPlaceholderToken, number)
+}
+
+// format zero pads n to the configured width (sign kept in front)
+func (s *NumberingStrategy) format(n int) string {
+	if n < 0 {
+		return "-" + fmt.Sprintf("%0*d", s.options.Padding, -n)
+	}
+	return fmt.Sprintf("%0*d", s.options.Padding, n)
+}
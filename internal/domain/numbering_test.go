@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumberingStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  NumberingOptions
+		input    string
+		index    int
+		expected string
+	}{
+		{"prefix", NumberingOptions{Start: 1, Step: 1, Padding: 4, Position: NumberPrefix, Separator: "_"}, "photo.jpg", 0, "0001_photo.jpg"},
+		{"suffix before extension", NumberingOptions{Start: 1, Step: 1, Padding: 3, Position: NumberSuffix, Separator: "-"}, "photo.jpg", 4, "photo-005.jpg"},
+		{"suffix before multi-part extension", NumberingOptions{Start: 1, Step: 1, Padding: 2, Position: NumberSuffix, Separator: "_"}, "logs.tar.gz", 0, "logs_01.tar.gz"},
+		{"placeholder", NumberingOptions{Start: 10, Step: 10, Padding: 0, Position: NumberPlaceholder}, "IMG_{n}.jpg", 2, "IMG_30.jpg"},
+		{"placeholder missing", NumberingOptions{Start: 1, Step: 1, Position: NumberPlaceholder}, "photo.jpg", 0, "photo.jpg"},
+		{"negative step", NumberingOptions{Start: 3, Step: -1, Padding: 2, Position: NumberPrefix}, "a.txt", 1, "02a.txt"},
+		{"negative number", NumberingOptions{Start: 0, Step: -1, Padding: 2, Position: NumberPrefix}, "a.txt", 1, "-01a.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := NewNumberingStrategy(nil, tt.options)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, strategy.ApplyContext(tt.input, RenameContext{Index: tt.index}))
+		})
+	}
+}
+
+func TestNumberingStrategy_WrapsBase(t *testing.T) {
+	base, _ := NewRegexMatchStrategy(`^.*(\.jpg)$`, "IMG_{n}$1")
+	strategy, err := NewNumberingStrategy(base, NumberingOptions{Start: 1, Step: 1, Padding: 4, Position: NumberPlaceholder})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "IMG_0001.jpg", ApplyStrategy(strategy, "DSC1234.jpg", RenameContext{Index: 0}))
+	assert.Equal(t, "IMG_0002.jpg", ApplyStrategy(strategy, "holiday.jpg", RenameContext{Index: 1}))
+	// Without context the first number is used
+	assert.Equal(t, "IMG_0001.jpg", strategy.Apply("other.jpg"))
+}
+
+func TestNewNumberingStrategy_Invalid(t *testing.T) {
+	_, err := NewNumberingStrategy(nil, NumberingOptions{Step: 1, Position: "middle"})
+	assert.Error(t, err)
+
+	_, err = NewNumberingStrategy(nil, NumberingOptions{Step: 0, Position: NumberPrefix})
+	assert.Error(t, err)
+
+	_, err = NewNumberingStrategy(nil, NumberingOptions{Step: 1, Padding: -1, Position: NumberPrefix})
+	assert.Error(t, err)
+}
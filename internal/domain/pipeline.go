@@ -0,0 +1,175 @@
+package domain
+
+import "fmt"
+
+// PipelineRule is a single step of a pipeline
+type PipelineRule struct {
+	Strategy RenameStrategy
+	Enabled  bool
+}
+
+// PipelineStrategy applies an ordered list of rules, each to the result of the previous one
+// Following Composite Pattern (OCP - Open/Closed Principle)
+type PipelineStrategy struct {
+	rules []PipelineRule
+}
+
+// NewPipelineStrategy creates a pipeline from rules (disabled rules are skipped when applying)
+func NewPipelineStrategy(rules []PipelineRule) *PipelineStrategy {
+	return &PipelineStrategy{
+		rules: rules,
+	}
+}
+
+// Apply applies all enabled rules in order
+func (s *PipelineStrategy) Apply(filename string) string {
+	return s.ApplyContext(filename, RenameContext{})
+}
+
+// ApplyContext applies all enabled rules in order, passing ctx to each of them
+func (s *PipelineStrategy) ApplyContext(filename string, ctx RenameContext) string {
+	name := filename
+	for _, rule := range s.rules {
+		if !rule.Enabled || rule.Strategy == nil {
+			continue
+		}
+		name = ApplyStrategy(rule.Strategy, name, ctx)
+	}
+	return name
+}
+
+// MetadataFields returns the metadata fields read by the enabled rules
+func (s *PipelineStrategy) MetadataFields() []string {
+	var fields []string
+	for _, rule := range s.rules {
+		if rule.Enabled && rule.Strategy != nil {
+			fields = append(fields, MetadataFields(rule.Strategy)...)
+		}
+	}
+	return fields
+}
+
+// RuleType identifies the kind of a configured rule
+type RuleType string
+
+const (
+	RuleReplace       RuleType = "replace"       // Pattern/replacement (exact or regex)
+	RuleNumbering     RuleType = "numbering"     // Sequence numbers
+	RuleCase          RuleType = "case"          // Case conversion
+	RuleTemplate      RuleType = "template"      // Name built from fields like {exif.date}
+	RuleHash          RuleType = "hash"          // Name from the content digest
+	RuleNormalize     RuleType = "normalize"     // Unicode normalization (NFC/NFD/NFKC/NFKD)
+	RuleConvert       RuleType = "convert"       // Width, kana and kanji numeral conversions
+	RuleTransliterate RuleType = "transliterate" // Conversion to ASCII (romaji, Latin letters)
+	RuleSanitize      RuleType = "sanitize"      // Fixes names that are invalid on the target platforms
+)
+
+// RuleConfig is the serializable definition of a pipeline rule
+// Used by the frontend and stored in history so a pipeline can be replayed
+type RuleConfig struct {
+	Type            RuleType          `json:"type"`
+	Enabled         bool              `json:"enabled"`
+	Pattern         string            `json:"pattern,omitempty"`
+	Replacement     string            `json:"replacement,omitempty"`
+	IsRegex         bool              `json:"isRegex,omitempty"`
+	CaseInsensitive bool              `json:"caseInsensitive,omitempty"`
+	Normalize       bool              `json:"normalize,omitempty"` // Match the pattern in NFC
+	Numbering       *NumberingOptions `json:"numbering,omitempty"`
+	CaseStyle       CaseStyle         `json:"caseStyle,omitempty"`
+	Scope           Scope             `json:"scope,omitempty"`
+	Template        string            `json:"template,omitempty"`
+	Hash            *HashOptions      `json:"hash,omitempty"`
+	Form            NormalizationForm `json:"form,omitempty"`
+	Conversions     []Conversion      `json:"conversions,omitempty"`
+	// Replaces characters that cannot be transliterated or are invalid (DefaultFallback when omitted)
+	Fallback  *string    `json:"fallback,omitempty"`
+	Platforms []Platform `json:"platforms,omitempty"` // Sanitize targets (all platforms when omitted)
+}
+
+// Build creates the strategy described by the rule
+// Scope limits any rule to the stem or extension (case and convert rules default to the stem, others to the full name)
+// Template and hash rules ignore scope
+func (r RuleConfig) Build() (RenameStrategy, error) {
+	var strategy RenameStrategy
+	var err error
+
+	switch r.Type {
+	case RuleReplace:
+		strategy, err = NewPatternStrategy(r.Pattern, r.Replacement, r.IsRegex, r.CaseInsensitive, r.Normalize)
+	case RuleNumbering:
+		if r.Numbering == nil {
+			return nil, fmt.Errorf("numbering options are missing")
+		}
+		strategy, err = NewNumberingStrategy(nil, *r.Numbering)
+	case RuleCase:
+		// Case conversion handles its scope itself (full converts stem and extension separately)
+		scope := r.Scope
+		if scope == "" {
+			scope = ScopeStem
+		}
+		return NewCaseStrategy(r.CaseStyle, scope)
+	case RuleTemplate:
+		// Templates always build the stem and keep the extension
+		// The pattern (if any) is a regular expression whose groups the template can use
+		if r.Pattern != "" {
+			return NewRegexTemplateStrategy(r.Template, r.Pattern, r.CaseInsensitive)
+		}
+		return NewTemplateStrategy(r.Template)
+	case RuleHash:
+		// The digest replaces the stem and the extension is kept
+		if r.Hash == nil {
+			return nil, fmt.Errorf("hash options are missing")
+		}
+		return NewHashStrategy(*r.Hash)
+	case RuleNormalize:
+		strategy, err = NewNormalizeStrategy(r.Form)
+	case RuleConvert:
+		// Converting extensions (.jpg → ．ｊｐｇ) is rarely wanted, so the stem is the default
+		strategy, err = NewConvertStrategy(r.Conversions...)
+		if err == nil && r.Scope == "" {
+			return NewScopedStrategy(strategy, ScopeStem)
+		}
+	case RuleTransliterate:
+		fallback := DefaultFallback
+		if r.Fallback != nil {
+			fallback = *r.Fallback
+		}
+		strategy, err = NewTransliterateStrategy(fallback)
+	case RuleSanitize:
+		replacement := DefaultFallback
+		if r.Fallback != nil {
+			replacement = *r.Fallback
+		}
+		strategy, err = NewSanitizeStrategy(r.Platforms, replacement)
+	default:
+		return nil, fmt.Errorf("unknown rule type: %q", r.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Scope == "" || r.Scope == ScopeFull {
+		return strategy, nil
+	}
+	return NewScopedStrategy(strategy, r.Scope)
+}
+
+// NewPipelineFromConfig builds a pipeline from rule definitions
+// Disabled rules are kept (and not validated) so they can be toggled back on
+func NewPipelineFromConfig(configs []RuleConfig) (*PipelineStrategy, error) {
+	rules := make([]PipelineRule, len(configs))
+	for i, config := range configs {
+		rules[i].Enabled = config.Enabled
+		if !config.Enabled {
+			continue
+		}
+
+		strategy, err := config.Build()
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i+1, err)
+		}
+		rules[i].Strategy = strategy
+	}
+
+	return NewPipelineStrategy(rules), nil
+}
@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelineStrategy(t *testing.T) {
+	stripPrefix, _ := NewRegexMatchStrategy(`^DSC_`, "")
+	normalizeSpaces, _ := NewRegexMatchStrategy(`\s+`, "_")
+	disabled := NewExactMatchStrategy("_", "-")
+
+	pipeline := NewPipelineStrategy([]PipelineRule{
+		{Strategy: stripPrefix, Enabled: true},
+		{Strategy: normalizeSpaces, Enabled: true},
+		{Strategy: disabled, Enabled: false},
+	})
+
+	assert.Equal(t, "beach_day_1.jpg", pipeline.Apply("DSC_beach  day 1.jpg"))
+}
+
+func TestPipelineStrategy_PassesContext(t *testing.T) {
+	numbering, _ := NewNumberingStrategy(nil, NumberingOptions{Start: 1, Step: 1, Padding: 2, Position: NumberPrefix, Separator: "_"})
+	pipeline := NewPipelineStrategy([]PipelineRule{
+		{Strategy: NewExactMatchStrategy("IMG", "photo"), Enabled: true},
+		{Strategy: numbering, Enabled: true},
+	})
+
+	assert.Equal(t, "03_photo.jpg", ApplyStrategy(pipeline, "IMG.jpg", RenameContext{Index: 2}))
+}
+
+func TestNewPipelineFromConfig(t *testing.T) {
+	pipeline, err := NewPipelineFromConfig([]RuleConfig{
+		{Type: RuleReplace, Enabled: true, Pattern: "img", Replacement: "photo", CaseInsensitive: true},
+		{Type: RuleReplace, Enabled: false, Pattern: "[invalid(", IsRegex: true},
+		{Type: RuleNumbering, Enabled: true, Numbering: &NumberingOptions{Start: 1, Step: 1, Padding: 3, Position: NumberSuffix, Separator: "-"}},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "photo_x-002.jpg", ApplyStrategy(pipeline, "IMG_x.jpg", RenameContext{Index: 1}))
+}
+
+func TestNewPipelineFromConfig_Case(t *testing.T) {
+	pipeline, err := NewPipelineFromConfig([]RuleConfig{
+		{Type: RuleCase, Enabled: true, CaseStyle: CaseSnake},
+		{Type: RuleCase, Enabled: true, CaseStyle: CaseLower, Scope: ScopeExtension},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "my_vacation_photo.jpg", pipeline.Apply("MyVacationPhoto.JPG"))
+}
+
+func TestNewPipelineFromConfig_Scope(t *testing.T) {
+	pipeline, err := NewPipelineFromConfig([]RuleConfig{
+		{Type: RuleReplace, Enabled: true, Pattern: "jpeg", Replacement: "photo", Scope: ScopeStem},
+		{Type: RuleReplace, Enabled: true, Pattern: "jpeg", Replacement: "jpg", Scope: ScopeExtension},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "photo_01.jpg", pipeline.Apply("jpeg_01.jpeg"))
+}
+
+func TestNewPipelineFromConfig_Normalize(t *testing.T) {
+	pipeline, err := NewPipelineFromConfig([]RuleConfig{
+		{Type: RuleNormalize, Enabled: true, Form: FormNFC},
+		{Type: RuleReplace, Enabled: true, Pattern: "ガイド", Replacement: "guide"},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "guide.pdf", pipeline.Apply("\u30ab\u3099イト\u3099.pdf"))
+}
+
+func TestNewPipelineFromConfig_Convert(t *testing.T) {
+	pipeline, err := NewPipelineFromConfig([]RuleConfig{
+		{Type: RuleConvert, Enabled: true, Conversions: []Conversion{ConvertFullWidth}},
+		{Type: RuleConvert, Enabled: true, Conversions: []Conversion{ConvertHalfWidth}, Scope: ScopeFull},
+	})
+	assert.NoError(t, err)
+
+	// The stem is converted by default, so the extension keeps its dot
+	assert.Equal(t, "report 1.pdf", pipeline.Apply("report 1.pdf"))
+
+	pipeline, err = NewPipelineFromConfig([]RuleConfig{
+		{Type: RuleConvert, Enabled: true, Conversions: []Conversion{ConvertFullWidth}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "ｒｅｐｏｒｔ　１.pdf", pipeline.Apply("report 1.pdf"))
+}
+
+func TestNewPipelineFromConfig_Transliterate(t *testing.T) {
+	dash := "-"
+	pipeline, err := NewPipelineFromConfig([]RuleConfig{
+		{Type: RuleTransliterate, Enabled: true},
+		{Type: RuleTransliterate, Enabled: true, Fallback: &dash},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "_sakura.jpg", pipeline.Apply("桜さくら.jpg"))
+
+	pipeline, err = NewPipelineFromConfig([]RuleConfig{{Type: RuleTransliterate, Enabled: true, Fallback: &dash}})
+	assert.NoError(t, err)
+	assert.Equal(t, "-sakura.jpg", pipeline.Apply("桜さくら.jpg"))
+}
+
+func TestNewPipelineFromConfig_Sanitize(t *testing.T) {
+	pipeline, err := NewPipelineFromConfig([]RuleConfig{
+		{Type: RuleReplace, Enabled: true, Pattern: "-", Replacement: ":"},
+		{Type: RuleSanitize, Enabled: true, Platforms: []Platform{PlatformWindows}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "10_30.txt", pipeline.Apply("10-30.txt"))
+	assert.Equal(t, "con_.txt", pipeline.Apply("con.txt"))
+}
+
+func TestNewPipelineFromConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []RuleConfig
+	}{
+		{"invalid regex", []RuleConfig{{Type: RuleReplace, Enabled: true, Pattern: "[invalid(", IsRegex: true}}},
+		{"missing numbering options", []RuleConfig{{Type: RuleNumbering, Enabled: true}}},
+		{"unknown case style", []RuleConfig{{Type: RuleCase, Enabled: true, CaseStyle: "sarcastic"}}},
+		{"unknown normalization form", []RuleConfig{{Type: RuleNormalize, Enabled: true, Form: "nfx"}}},
+		{"missing conversions", []RuleConfig{{Type: RuleConvert, Enabled: true}}},
+		{"invalid fallback", []RuleConfig{{Type: RuleTransliterate, Enabled: true, Fallback: func() *string { s := "/"; return &s }()}}},
+		{"unknown platform", []RuleConfig{{Type: RuleSanitize, Enabled: true, Platforms: []Platform{"amiga"}}}},
+		{"unknown scope", []RuleConfig{{Type: RuleReplace, Enabled: true, Pattern: "a", Scope: "middle"}}},
+		{"unknown type", []RuleConfig{{Type: "shuffle", Enabled: true}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPipelineFromConfig(tt.rules)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "rule 1")
+		})
+	}
+}
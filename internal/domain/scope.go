@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Scope selects which part of a filename a rule applies to
+type Scope string
+
+const (
+	ScopeStem      Scope = "stem"      // Name without extension
+	ScopeExtension Scope = "extension" // Extension without the leading dot
+	ScopeFull      Scope = "full"      // Whole name
+)
+
+// compressionExtensions are extensions that form one extension together with a preceding ".tar"
+var compressionExtensions = map[string]bool{
+	".gz":   true,
+	".bz2":  true,
+	".xz":   true,
+	".zst":  true,
+	".lz":   true,
+	".lzma": true,
+	".z":    true,
+}
+
+// SplitName splits a filename into stem and extension (including the dot)
+// Dotfiles like ".bashrc" have no extension and compressed archives keep both parts (".tar.gz")
+func SplitName(name string) (stem, ext string) {
+	ext = filepath.Ext(name)
+	if ext == name {
+		return name, ""
+	}
+	stem = strings.TrimSuffix(name, ext)
+
+	if compressionExtensions[strings.ToLower(ext)] {
+		inner := filepath.Ext(stem)
+		if strings.EqualFold(inner, ".tar") && inner != stem {
+			return strings.TrimSuffix(stem, inner), inner + ext
+		}
+	}
+
+	return stem, ext
+}
+
+// ScopedStrategy restricts a strategy to the stem or the extension of a filename
+// Following Decorator Pattern (OCP - Open/Closed Principle)
+type ScopedStrategy struct {
+	strategy RenameStrategy
+	scope    Scope
+}
+
+// NewScopedStrategy wraps strategy so it only sees the selected part of the name
+func NewScopedStrategy(strategy RenameStrategy, scope Scope) (*ScopedStrategy, error) {
+	switch scope {
+	case ScopeStem, ScopeExtension, ScopeFull:
+	default:
+		return nil, fmt.Errorf("unknown scope: %q", scope)
+	}
+
+	return &ScopedStrategy{
+		strategy: strategy,
+		scope:    scope,
+	}, nil
+}
+
+// MetadataFields returns the metadata fields read by the wrapped strategy
+func (s *ScopedStrategy) MetadataFields() []string {
+	return MetadataFields(s.strategy)
+}
+
+// Apply applies the wrapped strategy to the selected part of filename
+func (s *ScopedStrategy) Apply(filename string) string {
+	return s.ApplyContext(filename, RenameContext{})
+}
+
+// ApplyContext applies the wrapped strategy to the selected part of filename, passing ctx
+// An extension replaced by an empty string removes the dot as well
+func (s *ScopedStrategy) ApplyContext(filename string, ctx RenameContext) string {
+	switch s.scope {
+	case ScopeStem:
+		stem, ext := ctx.SplitName(filename)
+		return ApplyStrategy(s.strategy, stem, ctx) + ext
+	case ScopeExtension:
+		stem, ext := ctx.SplitName(filename)
+		if ext == "" {
+			return filename
+		}
+		newExt := ApplyStrategy(s.strategy, strings.TrimPrefix(ext, "."), ctx)
+		if newExt == "" {
+			return stem
+		}
+		return stem + "." + newExt
+	}
+	return ApplyStrategy(s.strategy, filename, ctx)
+}
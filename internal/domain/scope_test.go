@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitName(t *testing.T) {
+	tests := []struct {
+		name string
+		stem string
+		ext  string
+	}{
+		{"photo.jpg", "photo", ".jpg"},
+		{"archive.tar.gz", "archive", ".tar.gz"},
+		{"backup.TAR.XZ", "backup", ".TAR.XZ"},
+		{"notes.tar", "notes", ".tar"},
+		{"data.gz", "data", ".gz"},
+		{"report.v2.pdf", "report.v2", ".pdf"},
+		{".bashrc", ".bashrc", ""},
+		{".config.json", ".config", ".json"},
+		{".tar.gz", ".tar", ".gz"},
+		{"Makefile", "Makefile", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stem, ext := SplitName(tt.name)
+			assert.Equal(t, tt.stem, stem)
+			assert.Equal(t, tt.ext, ext)
+		})
+	}
+}
+
+func TestScopedStrategy(t *testing.T) {
+	jpgToImage, _ := NewPatternStrategy("jpg", "image", false, false, false)
+	extToPng, _ := NewPatternStrategy("jpg", "png", false, false, false)
+	dropExt, _ := NewPatternStrategy(`^.*$`, "", true, false, false)
+
+	tests := []struct {
+		name     string
+		strategy RenameStrategy
+		scope    Scope
+		input    string
+		expected string
+	}{
+		{"stem only", jpgToImage, ScopeStem, "jpg_export.jpg", "image_export.jpg"},
+		{"extension only", extToPng, ScopeExtension, "jpg_export.jpg", "jpg_export.png"},
+		{"full name", jpgToImage, ScopeFull, "jpg_export.jpg", "image_export.image"},
+		{"stem of multi-part extension", jpgToImage, ScopeStem, "jpg.tar.gz", "image.tar.gz"},
+		{"stem of dotfile", jpgToImage, ScopeStem, ".jpgrc", ".imagerc"},
+		{"extension of dotfile", extToPng, ScopeExtension, ".jpg", ".jpg"},
+		{"removed extension drops dot", dropExt, ScopeExtension, "photo.jpg", "photo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := NewScopedStrategy(tt.strategy, tt.scope)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, strategy.Apply(tt.input))
+		})
+	}
+}
+
+func TestScopedStrategy_PassesContext(t *testing.T) {
+	numbering, _ := NewNumberingStrategy(nil, NumberingOptions{Start: 1, Step: 1, Padding: 2, Position: NumberSuffix, Separator: "_"})
+	strategy, err := NewScopedStrategy(numbering, ScopeStem)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "logs_03.tar.gz", ApplyStrategy(strategy, "logs.tar.gz", RenameContext{Index: 2}))
+}
+
+func TestScopedStrategy_Directory(t *testing.T) {
+	upper, _ := NewCaseStrategy(CaseUpper, ScopeFull)
+	strategy, err := NewScopedStrategy(upper, ScopeStem)
+	assert.NoError(t, err)
+
+	// Directory names have no extension
+	assert.Equal(t, "PROJECT.V2", ApplyStrategy(strategy, "project.v2", RenameContext{IsDir: true}))
+	assert.Equal(t, "PROJECT.v2", ApplyStrategy(strategy, "project.v2", RenameContext{}))
+}
+
+func TestNewScopedStrategy_InvalidScope(t *testing.T) {
+	_, err := NewScopedStrategy(&ExactMatchStrategy{}, "middle")
+	assert.Error(t, err)
+}
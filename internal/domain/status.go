@@ -0,0 +1,24 @@
+package domain
+
+// FileStatus summarizes what will happen to a file when the previewed batch is executed
+type FileStatus string
+
+const (
+	StatusWillRename           FileStatus = "will-rename"            // The file gets a new name
+	StatusUnchanged            FileStatus = "unchanged"              // The name stays the same
+	StatusCollidesWithExisting FileStatus = "collides-with-existing" // The new name is taken; the conflict policy decides
+	StatusCollidesWithinBatch  FileStatus = "collides-within-batch"  // Other files of the batch get the same name; the conflict policy decides
+	StatusInvalidName          FileStatus = "invalid-name"           // The new name is invalid on a target platform
+	StatusSourceMissing        FileStatus = "source-missing"         // The file no longer exists
+	StatusPermissionDenied     FileStatus = "permission-denied"      // The file or its folder cannot be changed
+)
+
+// IsError reports whether the status prevents the batch from being executed
+// Collisions are not errors: the conflict policy resolves them (the fail policy aborts in Execute)
+func (s FileStatus) IsError() bool {
+	switch s {
+	case StatusInvalidName, StatusSourceMissing, StatusPermissionDenied:
+		return true
+	}
+	return false
+}
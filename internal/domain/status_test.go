@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStatus_IsError(t *testing.T) {
+	tests := []struct {
+		status   FileStatus
+		expected bool
+	}{
+		{StatusWillRename, false},
+		{StatusUnchanged, false},
+		{StatusCollidesWithExisting, false},
+		{StatusCollidesWithinBatch, false},
+		{StatusInvalidName, true},
+		{StatusSourceMissing, true},
+		{StatusPermissionDenied, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.status.IsError())
+		})
+	}
+}
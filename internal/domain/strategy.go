@@ -3,6 +3,8 @@ package domain
 import (
 	"regexp"
 	"strings"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 // RenameStrategy defines the interface for different rename strategies
@@ -15,6 +17,7 @@ type RenameStrategy interface {
 type ExactMatchStrategy struct {
 	pattern     string
 	replacement string
+	normalize   bool // Match in NFC so composed and decomposed characters are equal
 }
 
 // NewExactMatchStrategy creates a new exact match strategy
@@ -27,13 +30,21 @@ func NewExactMatchStrategy(pattern, replacement string) *ExactMatchStrategy {
 
 // Apply applies exact string replacement
 func (s *ExactMatchStrategy) Apply(filename string) string {
-	return strings.ReplaceAll(filename, s.pattern, s.replacement)
+	if !s.normalize {
+		return strings.ReplaceAll(filename, s.pattern, s.replacement)
+	}
+	normalized := norm.NFC.String(filename)
+	if !strings.Contains(normalized, s.pattern) {
+		return filename
+	}
+	return strings.ReplaceAll(normalized, s.pattern, s.replacement)
 }
 
 // RegexMatchStrategy implements regular expression matching
 type RegexMatchStrategy struct {
 	regex       *regexp.Regexp
 	replacement string
+	normalize   bool // Match in NFC so composed and decomposed characters are equal
 }
 
 // NewRegexMatchStrategy creates a new regex match strategy
@@ -51,7 +62,21 @@ func NewRegexMatchStrategy(pattern, replacement string) (*RegexMatchStrategy, er
 
 // Apply applies regex replacement
 func (s *RegexMatchStrategy) Apply(filename string) string {
-	return s.regex.ReplaceAllString(filename, s.replacement)
+	return replaceRegex(s.regex, filename, s.replacement, s.normalize)
+}
+
+// replaceRegex replaces the matches of regex in filename
+// With normalize, matching is done on the NFC form of filename; names without a match keep
+// their original form, names with a match come out in NFC
+func replaceRegex(regex *regexp.Regexp, filename, replacement string, normalize bool) string {
+	if !normalize {
+		return regex.ReplaceAllString(filename, replacement)
+	}
+	normalized := norm.NFC.String(filename)
+	if !regex.MatchString(normalized) {
+		return filename
+	}
+	return regex.ReplaceAllString(normalized, replacement)
 }
 
 // PatternProvider is an interface for strategies that can expose their pattern and replacement
@@ -102,8 +127,13 @@ func (s *CaseInsensitiveStrategy) Apply(filename string) string {
 		replacement := provider.GetReplacement()
 
 		// For exact match strategies, escape the pattern
-		if _, isExact := s.strategy.(*ExactMatchStrategy); isExact {
+		normalize := false
+		switch strategy := s.strategy.(type) {
+		case *ExactMatchStrategy:
 			pattern = regexp.QuoteMeta(pattern)
+			normalize = strategy.normalize
+		case *RegexMatchStrategy:
+			normalize = strategy.normalize
 		}
 
 		// Add case-insensitive flag if not already present
@@ -112,9 +142,41 @@ func (s *CaseInsensitiveStrategy) Apply(filename string) string {
 		}
 
 		regex := regexp.MustCompile(pattern)
-		return regex.ReplaceAllString(filename, replacement)
+		return replaceRegex(regex, filename, replacement, normalize)
 	}
 
 	// Fallback to default behavior
 	return s.strategy.Apply(filename)
 }
+
+// NewPatternStrategy builds the strategy for a single pattern/replacement pair
+// Shared by the GUI and CLI so both produce identical results
+// With normalize, pattern and names are compared in NFC (e.g. a typed "が" matches an NFD name from a Mac)
+func NewPatternStrategy(pattern, replacement string, isRegex, caseInsensitive, normalize bool) (RenameStrategy, error) {
+	var strategy RenameStrategy
+
+	if normalize {
+		pattern = norm.NFC.String(pattern)
+		replacement = norm.NFC.String(replacement)
+	}
+
+	if isRegex {
+		regexStrategy, err := NewRegexMatchStrategy(pattern, replacement)
+		if err != nil {
+			return nil, err
+		}
+		regexStrategy.normalize = normalize
+		strategy = regexStrategy
+	} else {
+		exactStrategy := NewExactMatchStrategy(pattern, replacement)
+		exactStrategy.normalize = normalize
+		strategy = exactStrategy
+	}
+
+	// Apply case-insensitive if needed
+	if caseInsensitive {
+		strategy = NewCaseInsensitiveStrategy(strategy)
+	}
+
+	return strategy, nil
+}
@@ -105,3 +105,43 @@ func TestCaseInsensitiveWithRegex(t *testing.T) {
 		})
 	}
 }
+
+// TestNewPatternStrategy tests strategy construction from pattern options
+func TestNewPatternStrategy(t *testing.T) {
+	tests := []struct {
+		name            string
+		pattern         string
+		replacement     string
+		isRegex         bool
+		caseInsensitive bool
+		normalize       bool
+		input           string
+		expected        string
+	}{
+		{"exact", "test", "TEST", false, false, false, "test.txt", "TEST.txt"},
+		{"exact case-insensitive", "test", "X", false, true, false, "TeSt.txt", "X.txt"},
+		{"regex", `(\d+)`, "#$1", true, false, false, "file12.txt", "file#12.txt"},
+		{"regex case-insensitive", `img`, "photo", true, true, false, "IMG_1.jpg", "photo_1.jpg"},
+		// "\u30cf\u309a" is パ decomposed as macOS stores it
+		{"exact nfd name", "パスポート", "passport", false, false, true, "\u30cf\u309aスホ\u309aート.pdf", "passport.pdf"},
+		{"exact nfd name without normalize", "パスポート", "passport", false, false, false, "\u30cf\u309aスホ\u309aート.pdf", "\u30cf\u309aスホ\u309aート.pdf"},
+		{"exact nfd pattern", "\u304b\u3099", "ga", false, false, true, "が.txt", "ga.txt"},
+		{"regex nfd name", `^(\p{Katakana}+)_(\d+)`, "${2}_$1", true, false, true, "\u30ab\u3099イド_01.txt", "01_ガイド.txt"},
+		{"regex case-insensitive nfd name", `^ガイド_DRAFT`, "ガイド", true, true, true, "\u30ab\u3099イド_draft.txt", "ガイド.txt"},
+		// Names that do not match keep their original form
+		{"no match keeps nfd", "z", "y", false, false, true, "\u30ab\u3099.txt", "\u30ab\u3099.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := NewPatternStrategy(tt.pattern, tt.replacement, tt.isRegex, tt.caseInsensitive, tt.normalize)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, strategy.Apply(tt.input))
+		})
+	}
+
+	t.Run("invalid regex", func(t *testing.T) {
+		_, err := NewPatternStrategy(`[invalid(`, "x", true, false, false)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,316 @@
+package domain
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Template fields describing the file itself
+const (
+	FieldStem   = "stem"   // Original name without extension
+	FieldName   = "name"   // Alias of stem (unless the pattern has a group called "name")
+	FieldExt    = "ext"    // Original extension without the dot
+	FieldParent = "parent" // Name of the containing folder
+	FieldIndex  = "index"  // Position in the batch, starting at 1
+)
+
+// TemplateError is a template syntax error at a position (1-based column, counted in characters)
+type TemplateError struct {
+	Column  int
+	Message string
+}
+
+// Error formats the error with its column
+func (e *TemplateError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Column, e.Message)
+}
+
+// templateFilter transforms the text of a field
+type templateFilter func(string) string
+
+// templatePart is a literal text or a {field:arg|filter} reference
+type templatePart struct {
+	literal string
+	field   string
+	arg     string
+	capture int // Index of the capture group, -1 for other fields
+	filters []templateFilter
+}
+
+// TemplateStrategy builds the name (without extension) from tokens
+// e.g. "{exif.date:2006-01-02_150405}_{exif.model}" → 2024-05-01_143015_Canon EOS R5.jpg,
+// "{track:02} - {artist} - {title}" → 03 - Artist - Song.mp3
+// or "{parent|slug}_{index:03}" → summer-trip_001.jpg
+// The extension is kept; {n} is left as is so a numbering rule can fill it in
+// Fields can be followed by filters: {stem|lower|truncate:20}
+type TemplateStrategy struct {
+	parts          []templatePart
+	pattern        *regexp.Regexp // Optional; its capture groups are available as {1} or {name}
+	metadataFields []string
+}
+
+// NewTemplateStrategy parses template
+func NewTemplateStrategy(template string) (*TemplateStrategy, error) {
+	return newTemplateStrategy(template, nil)
+}
+
+// NewRegexTemplateStrategy parses template; the capture groups of pattern (matched against the
+// name without extension) are available as {1}, {2}... or by name
+// Files that do not match pattern are left unchanged
+func NewRegexTemplateStrategy(template, pattern string, caseInsensitive bool) (*TemplateStrategy, error) {
+	if caseInsensitive {
+		pattern = "(?i)" + pattern
+	}
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return newTemplateStrategy(template, regex)
+}
+
+// newTemplateStrategy parses template, reporting syntax errors as *TemplateError
+func newTemplateStrategy(template string, pattern *regexp.Regexp) (*TemplateStrategy, error) {
+	strategy := &TemplateStrategy{pattern: pattern}
+
+	var literal strings.Builder
+	flush := func() {
+		if literal.Len() > 0 {
+			strategy.parts = append(strategy.parts, templatePart{literal: literal.String(), capture: -1})
+			literal.Reset()
+		}
+	}
+
+	for pos := 0; pos < len(template); {
+		switch {
+		case strings.HasPrefix(template[pos:], "{{"):
+			literal.WriteByte('{')
+			pos += 2
+		case strings.HasPrefix(template[pos:], "}}"):
+			literal.WriteByte('}')
+			pos += 2
+		case template[pos] == '}':
+			return nil, templateError(template, pos, "unexpected }, use }} for a literal brace")
+		case template[pos] == '{':
+			end := strings.IndexByte(template[pos:], '}')
+			if end < 0 {
+				return nil, templateError(template, pos, "unclosed {")
+			}
+			if nested := strings.IndexByte(template[pos+1:pos+end], '{'); nested >= 0 {
+				return nil, templateError(template, pos+1+nested, "unexpected { inside a field")
+			}
+
+			flush()
+			part, err := strategy.parseField(template, pos+1, template[pos+1:pos+end])
+			if err != nil {
+				return nil, err
+			}
+			strategy.parts = append(strategy.parts, part)
+			pos += end + 1
+		default:
+			_, size := utf8.DecodeRuneInString(template[pos:])
+			literal.WriteString(template[pos : pos+size])
+			pos += size
+		}
+	}
+	flush()
+
+	return strategy, nil
+}
+
+// parseField parses the text between braces starting at offset: field[:arg][|filter[:arg]]...
+func (s *TemplateStrategy) parseField(template string, offset int, text string) (templatePart, error) {
+	segments := strings.Split(text, "|")
+	field, arg, _ := strings.Cut(segments[0], ":")
+	field = strings.TrimSpace(field)
+	part := templatePart{field: field, arg: arg, capture: -1}
+
+	switch {
+	case field == "":
+		return part, templateError(template, offset, "empty field")
+	case field == strings.Trim(NumberPlaceholderToken, "{}"):
+		if len(segments) > 1 || arg != "" {
+			return part, templateError(template, offset, "{n} is filled in by numbering and takes no format or filters")
+		}
+		return templatePart{literal: NumberPlaceholderToken, capture: -1}, nil
+	case isDigits(field):
+		group, _ := strconv.Atoi(field)
+		if s.pattern == nil || group > s.pattern.NumSubexp() {
+			return part, templateError(template, offset, fmt.Sprintf("no capture group %d in the pattern", group))
+		}
+		part.capture = group
+	case s.pattern != nil && s.pattern.SubexpIndex(field) >= 0:
+		part.capture = s.pattern.SubexpIndex(field)
+	case field == FieldStem, field == FieldName, field == FieldExt, field == FieldParent:
+	case field == FieldIndex:
+		if arg != "" && !isDigits(arg) {
+			return part, templateError(template, offset+len(field)+1, fmt.Sprintf("invalid padding %q for {index}", arg))
+		}
+	case IsMetadataField(field):
+		if _, isHash := ParseHashField(field); isHash && arg != "" && !isDigits(arg) {
+			return part, templateError(template, offset+len(field)+1, fmt.Sprintf("invalid length %q for {%s}", arg, field))
+		}
+		if field == FieldSize && arg != "" && arg != "human" {
+			return part, templateError(template, offset+len(field)+1, fmt.Sprintf("invalid format %q for {size}, use {size:human}", arg))
+		}
+		if !slices.Contains(s.metadataFields, field) {
+			s.metadataFields = append(s.metadataFields, field)
+		}
+	default:
+		return part, templateError(template, offset, fmt.Sprintf("unknown field %q", field))
+	}
+
+	position := offset + len(segments[0]) + 1
+	for _, segment := range segments[1:] {
+		filter, err := parseTemplateFilter(segment)
+		if err != nil {
+			return part, templateError(template, position, err.Error())
+		}
+		part.filters = append(part.filters, filter)
+		position += len(segment) + 1
+	}
+
+	return part, nil
+}
+
+// parseTemplateFilter parses name[:arg]
+// Filters: lower, upper, title, camel, pascal, snake, kebab, slug, trim and truncate:N
+func parseTemplateFilter(text string) (templateFilter, error) {
+	name, arg, hasArg := strings.Cut(strings.TrimSpace(text), ":")
+
+	switch CaseStyle(name) {
+	case CaseLower, CaseUpper, CaseTitle, CaseCamel, CasePascal, CaseSnake, CaseKebab:
+		style := CaseStyle(name)
+		return func(value string) string { return ConvertCase(value, style) }, nil
+	}
+
+	switch name {
+	case "slug":
+		return slugify, nil
+	case "trim":
+		return strings.TrimSpace, nil
+	case "truncate":
+		length, err := strconv.Atoi(arg)
+		if !hasArg || err != nil || length <= 0 {
+			return nil, fmt.Errorf("truncate needs a positive length, e.g. truncate:40")
+		}
+		return func(value string) string { return truncateRunes(value, length) }, nil
+	case "":
+		return nil, fmt.Errorf("empty filter")
+	}
+	return nil, fmt.Errorf("unknown filter %q", name)
+}
+
+// templateError creates a TemplateError at byte offset pos of template
+func templateError(template string, pos int, message string) *TemplateError {
+	return &TemplateError{Column: utf8.RuneCountInString(template[:pos]) + 1, Message: message}
+}
+
+// MetadataFields returns the metadata fields referenced by the template
+func (s *TemplateStrategy) MetadataFields() []string {
+	return s.metadataFields
+}
+
+// Apply renders the template without metadata
+func (s *TemplateStrategy) Apply(filename string) string {
+	return s.ApplyContext(filename, RenameContext{})
+}
+
+// ApplyContext renders the template with the metadata in ctx
+// Missing fields render empty; the name is left unchanged when it renders empty, when the
+// pattern does not match, or when none of the metadata fields is present (e.g. a photo without EXIF)
+func (s *TemplateStrategy) ApplyContext(filename string, ctx RenameContext) string {
+	stem, ext := ctx.SplitName(filename)
+
+	var captures []string
+	if s.pattern != nil {
+		if captures = s.pattern.FindStringSubmatch(stem); captures == nil {
+			return filename
+		}
+	}
+
+	var builder strings.Builder
+	found := false
+	for _, part := range s.parts {
+		if part.field == "" {
+			builder.WriteString(part.literal)
+			continue
+		}
+
+		var value string
+		switch {
+		case part.capture >= 0:
+			value = captures[part.capture]
+		case part.field == FieldStem, part.field == FieldName:
+			value = stem
+		case part.field == FieldExt:
+			value = strings.TrimPrefix(ext, ".")
+		case part.field == FieldParent:
+			if ctx.Path != "" {
+				value = filepath.Base(filepath.Dir(ctx.Path))
+			}
+		case part.field == FieldIndex:
+			value = formatInt(int64(ctx.Index+1), part.arg)
+		default:
+			value = ctx.Metadata.Format(part.field, part.arg)
+			found = found || value != ""
+		}
+
+		for _, filter := range part.filters {
+			value = filter(value)
+		}
+		// Field values must not create subfolders
+		builder.WriteString(strings.ReplaceAll(value, "/", "-"))
+	}
+
+	name := builder.String()
+	if strings.TrimSpace(name) == "" || (len(s.metadataFields) > 0 && !found) {
+		return filename
+	}
+	return name + ext
+}
+
+// slugify lowercases text and joins its letters and digits with hyphens
+func slugify(text string) string {
+	var builder strings.Builder
+	pendingHyphen := false
+	for _, r := range strings.ToLower(text) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			pendingHyphen = builder.Len() > 0
+			continue
+		}
+		if pendingHyphen {
+			builder.WriteByte('-')
+			pendingHyphen = false
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// truncateRunes shortens text to at most length characters
+func truncateRunes(text string, length int) string {
+	runes := []rune(text)
+	if len(runes) <= length {
+		return text
+	}
+	return string(runes[:length])
+}
+
+// isDigits reports whether text is a non-empty run of ASCII digits
+func isDigits(text string) bool {
+	if text == "" {
+		return false
+	}
+	for _, c := range text {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateStrategy_ApplyContext(t *testing.T) {
+	metadata := Metadata{
+		FieldEXIFDate:     time.Date(2024, 5, 1, 14, 30, 15, 0, time.UTC),
+		FieldEXIFModel:    "Canon EOS R5",
+		FieldEXIFFNumber:  2.8,
+		FieldEXIFISO:      400,
+		FieldEXIFLatitude: 35.658581,
+		FieldEXIFMake:     "A/B Optics",
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		filename string
+		ctx      RenameContext
+		expected string
+	}{
+		{"date and model", "{exif.date:2006-01-02_150405}_{exif.model}", "IMG_0001.JPG", RenameContext{Metadata: metadata}, "2024-05-01_143015_Canon EOS R5.JPG"},
+		{"default date layout", "{exif.date}", "a.heic", RenameContext{Metadata: metadata}, "2024-05-01_143015.heic"},
+		{"numbers", "f{exif.fnumber}_iso{exif.iso}_{exif.gps.lat:2}", "a.jpg", RenameContext{Metadata: metadata}, "f2.8_iso400_35.66.jpg"},
+		{"original name", "{exif.model} {name}.{ext}", "trip.tar.gz", RenameContext{Metadata: metadata}, "Canon EOS R5 trip.tar.gz.tar.gz"},
+		{"slash in value", "{exif.make}", "a.jpg", RenameContext{Metadata: metadata}, "A-B Optics.jpg"},
+		{"missing field", "{exif.model}_{exif.lens}_{name}", "a.jpg", RenameContext{Metadata: metadata}, "Canon EOS R5__a.jpg"},
+		{"empty result keeps name", "{exif.lens}", "a.jpg", RenameContext{}, "a.jpg"},
+		{"number placeholder kept", "{exif.model}_{n}", "a.jpg", RenameContext{Metadata: metadata}, "Canon EOS R5_{n}.jpg"},
+		{"no metadata keeps name", "{name}_{exif.model}", "a.jpg", RenameContext{}, "a.jpg"},
+		{"directory", "{name}_copy", "v1.2", RenameContext{IsDir: true}, "v1.2_copy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := NewTemplateStrategy(tt.template)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, strategy.ApplyContext(tt.filename, tt.ctx))
+		})
+	}
+}
+
+func TestNewTemplateStrategy_Errors(t *testing.T) {
+	tests := []struct {
+		template string
+		column   int
+	}{
+		{"{exif.date", 1},
+		{"ab{unknown}", 4},
+		{"{exif.modle}_{stem}", 2},
+		{"{stem}_{exif.gps}", 9},
+		{"{}", 2},
+		{"a}b", 2},
+		{"{a{b}", 3},
+		{"写真_{stem|shout}", 10},
+		{"{stem|lower|truncate}", 13},
+		{"{stem|truncate:0}", 7},
+		{"{stem|}", 7},
+		{"{index:abc}", 8},
+		{"{size:kb}", 7},
+		{"{1}", 2},
+		{"{n|upper}", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			_, err := NewTemplateStrategy(tt.template)
+
+			var templateErr *TemplateError
+			if assert.ErrorAs(t, err, &templateErr) {
+				assert.Equal(t, tt.column, templateErr.Column)
+			}
+		})
+	}
+}
+
+func TestTemplateStrategy_Tokens(t *testing.T) {
+	metadata := Metadata{
+		FieldModTime: time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC),
+		FieldSize:    1536,
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		filename string
+		expected string
+	}{
+		{"stem and ext", "{stem}-{ext}", "report.tar.gz", "report-tar.gz.tar.gz"},
+		{"parent and index", "{parent}_{index:03}", "a.jpg", "Summer Trip_005.jpg"},
+		{"mtime", "{mtime:2006-01-02}_{stem}", "a.jpg", "2024-05-01_a.jpg"},
+		{"human size", "{stem} ({size:human})", "a.jpg", "a (1.5 KB).jpg"},
+		{"filters", "{parent|slug}_{stem|upper|truncate:3}", "photo.jpg", "summer-trip_PHO.jpg"},
+		{"trim and case", "{stem|trim|snake}", "  My Photo  .jpg", "my_photo.jpg"},
+		{"escaped braces", "{{{stem}}}", "a.jpg", "{a}.jpg"},
+		{"multibyte", "{stem|truncate:2}_写真", "夏休み.jpg", "夏休_写真.jpg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := NewTemplateStrategy(tt.template)
+			ctx := RenameContext{Index: 4, Path: "/photos/Summer Trip/" + tt.filename, Metadata: metadata}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, strategy.ApplyContext(tt.filename, ctx))
+		})
+	}
+}
+
+func TestRegexTemplateStrategy(t *testing.T) {
+	strategy, err := NewRegexTemplateStrategy("{date}_{2|lower}", `^(?P<date>\d{8})_(\w+)$`, false)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "20240501_beach.jpg", strategy.Apply("20240501_BEACH.jpg"))
+	// Files that do not match are left unchanged
+	assert.Equal(t, "IMG_0001.jpg", strategy.Apply("IMG_0001.jpg"))
+
+	insensitive, err := NewRegexTemplateStrategy("{1}", `^img_(\d+)$`, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "0001.JPG", insensitive.Apply("IMG_0001.JPG"))
+
+	// A group called "name" takes precedence over the stem alias
+	named, err := NewRegexTemplateStrategy("{name}", `^(?P<name>[a-z]+)`, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "abc.txt", named.Apply("abc123.txt"))
+
+	_, err = NewRegexTemplateStrategy("{3}", `(a)(b)`, false)
+	assert.Error(t, err)
+	_, err = NewRegexTemplateStrategy("{1}", `(a`, false)
+	assert.Error(t, err)
+}
+
+func TestMetadata_Format(t *testing.T) {
+	metadata := Metadata{"int": 7, "size": int64(5) << 30, "small": 999, "float": 1.25}
+
+	assert.Equal(t, "007", metadata.Format("int", "03"))
+	assert.Equal(t, "7", metadata.Format("int", ""))
+	assert.Equal(t, "5 GB", metadata.Format("size", "human"))
+	assert.Equal(t, "999 B", metadata.Format("small", "human"))
+	assert.Equal(t, "1.2", metadata.Format("float", "1"))
+	assert.Equal(t, "", metadata.Format("missing", ""))
+}
+
+func TestNeedsMetadata(t *testing.T) {
+	withMetadata, _ := NewTemplateStrategy("{exif.model}")
+	withoutMetadata, _ := NewTemplateStrategy("{name}_copy")
+	numbering, _ := NewNumberingStrategy(withMetadata, NumberingOptions{Start: 1, Step: 1, Position: NumberPrefix})
+	scoped, _ := NewScopedStrategy(withMetadata, ScopeStem)
+
+	assert.True(t, NeedsMetadata(withMetadata))
+	assert.False(t, NeedsMetadata(withoutMetadata))
+	assert.True(t, NeedsMetadata(numbering))
+	assert.True(t, NeedsMetadata(scoped))
+	assert.False(t, NeedsMetadata(NewExactMatchStrategy("a", "b")))
+
+	assert.True(t, NeedsMetadata(NewPipelineStrategy([]PipelineRule{
+		{Strategy: NewExactMatchStrategy("a", "b"), Enabled: true},
+		{Strategy: withMetadata, Enabled: true},
+	})))
+	assert.False(t, NeedsMetadata(NewPipelineStrategy([]PipelineRule{
+		{Strategy: withMetadata, Enabled: false},
+	})))
+}
+
+func TestTemplateStrategy_AudioFields(t *testing.T) {
+	metadata := Metadata{
+		FieldTrack:  3,
+		FieldArtist: "AC/DC",
+		FieldTitle:  "Thunderstruck",
+		FieldYear:   1990,
+	}
+	strategy, err := NewTemplateStrategy("{track:02} - {artist} - {title} ({year})")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{FieldTrack, FieldArtist, FieldTitle, FieldYear}, strategy.MetadataFields())
+	assert.Equal(t, "03 - AC-DC - Thunderstruck (1990).mp3", strategy.ApplyContext("01 track.mp3", RenameContext{Metadata: metadata}))
+	assert.Equal(t, "01 track.mp3", strategy.ApplyContext("01 track.mp3", RenameContext{}))
+}
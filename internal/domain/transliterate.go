@@ -0,0 +1,234 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultFallback replaces characters that cannot be transliterated (e.g. kanji)
+const DefaultFallback = "_"
+
+// TransliterateStrategy converts names to ASCII
+// Kana become Hepburn romaji (ガイド → gaido, 東京タワー → _tawaa), Cyrillic and Greek become Latin
+// (Москва → Moskva, Αθήνα → Athina) and accented letters lose their accents (café → cafe)
+// Runs of characters without a mapping are replaced by a single fallback
+type TransliterateStrategy struct {
+	fallback string
+}
+
+// NewTransliterateStrategy creates a strategy replacing unmappable characters with fallback ("" drops them)
+func NewTransliterateStrategy(fallback string) (*TransliterateStrategy, error) {
+	for _, r := range fallback {
+		if r >= utf8.RuneSelf || !unicode.IsPrint(r) || r == '/' || r == '\\' {
+			return nil, fmt.Errorf("fallback must be printable ASCII without path separators: %q", fallback)
+		}
+	}
+	return &TransliterateStrategy{fallback: fallback}, nil
+}
+
+// Apply transliterates filename
+func (s *TransliterateStrategy) Apply(filename string) string {
+	return Transliterate(filename, s.fallback)
+}
+
+// Transliterate converts text to ASCII, replacing each run of unmappable characters with fallback
+func Transliterate(text, fallback string) string {
+	runes := []rune(norm.NFC.String(kanaToFullWidth(text)))
+
+	var builder strings.Builder
+	lastVowel := byte(0) // Vowel of the last romanized kana, repeated by ー
+	sokuon := false      // っ doubles the consonant of the next kana
+	unmapped := false    // The previous character had no mapping
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		if isKana(r) {
+			unmapped = false
+			if toHiragana(r) == 'っ' {
+				sokuon = true
+				i++
+				continue
+			}
+
+			romaji, size := romanizeKana(runes[i:])
+			if sokuon && romaji != "" && !strings.ContainsRune("aiueon", rune(romaji[0])) {
+				if strings.HasPrefix(romaji, "ch") {
+					builder.WriteByte('t')
+				} else {
+					builder.WriteByte(romaji[0])
+				}
+			}
+			sokuon = false
+			builder.WriteString(romaji)
+			if romaji != "" {
+				lastVowel = romaji[len(romaji)-1]
+			}
+			i += size
+			continue
+		}
+
+		if r == 'ー' && lastVowel != 0 && lastVowel != 'n' {
+			builder.WriteByte(lastVowel)
+			i++
+			continue
+		}
+		sokuon = false
+		lastVowel = 0
+
+		latin, ok := transliterateRune(r)
+		if !ok {
+			if !unmapped {
+				builder.WriteString(fallback)
+			}
+			unmapped = true
+			i++
+			continue
+		}
+		unmapped = false
+
+		// Multi-letter mappings of capitals are capitalized, or upper-cased inside an upper-case word (ЩИ → SHCHI)
+		if unicode.IsUpper(r) && len(latin) > 1 {
+			if i+1 < len(runes) && unicode.IsUpper(runes[i+1]) {
+				latin = strings.ToUpper(latin)
+			} else {
+				latin = strings.ToUpper(latin[:1]) + latin[1:]
+			}
+		}
+		builder.WriteString(latin)
+		i++
+	}
+
+	return builder.String()
+}
+
+// transliterateRune maps a single non-kana character to ASCII
+func transliterateRune(r rune) (string, bool) {
+	if r < utf8.RuneSelf {
+		return string(r), true
+	}
+	if unicode.Is(unicode.Mn, r) {
+		// A combining mark left after normalization (e.g. a dakuten on ア)
+		return "", true
+	}
+	if latin, ok := transliterationTable[r]; ok {
+		return latin, true
+	}
+	if half := toHalfWidth(r); half < utf8.RuneSelf {
+		return string(half), true
+	}
+
+	// Accented letters: drop the combining marks (é → e, ά → α → a)
+	decomposed := []rune(norm.NFD.String(string(r)))
+	if len(decomposed) > 1 && decomposed[0] != r {
+		base := decomposed[0]
+		for _, mark := range decomposed[1:] {
+			if !unicode.Is(unicode.Mn, mark) {
+				return "", false
+			}
+		}
+		return transliterateRune(base)
+	}
+	return "", false
+}
+
+// transliterationTable maps Cyrillic, Greek, Latin letters without decomposition and punctuation to ASCII
+// Cyrillic follows the common passport style (Russian and Ukrainian), Greek follows ELOT 743
+var transliterationTable = map[rune]string{
+	// Cyrillic
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+	'А': "A", 'Б': "B", 'В': "V", 'Г': "G", 'Д': "D", 'Е': "E", 'Ё': "Yo", 'Ж': "Zh",
+	'З': "Z", 'И': "I", 'Й': "Y", 'К': "K", 'Л': "L", 'М': "M", 'Н': "N", 'О': "O",
+	'П': "P", 'Р': "R", 'С': "S", 'Т': "T", 'У': "U", 'Ф': "F", 'Х': "Kh", 'Ц': "Ts",
+	'Ч': "Ch", 'Ш': "Sh", 'Щ': "Shch", 'Ъ': "", 'Ы': "Y", 'Ь': "", 'Э': "E", 'Ю': "Yu",
+	'Я': "Ya", 'І': "I", 'Ї': "Yi", 'Є': "Ye", 'Ґ': "G",
+
+	// Greek (accented letters are decomposed first)
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th",
+	'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p",
+	'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps",
+	'ω': "o",
+	'Α': "A", 'Β': "V", 'Γ': "G", 'Δ': "D", 'Ε': "E", 'Ζ': "Z", 'Η': "I", 'Θ': "Th",
+	'Ι': "I", 'Κ': "K", 'Λ': "L", 'Μ': "M", 'Ν': "N", 'Ξ': "X", 'Ο': "O", 'Π': "P",
+	'Ρ': "R", 'Σ': "S", 'Τ': "T", 'Υ': "Y", 'Φ': "F", 'Χ': "Ch", 'Ψ': "Ps", 'Ω': "O",
+
+	// Latin letters that do not decompose
+	'ß': "ss", 'æ': "ae", 'Æ': "Ae", 'œ': "oe", 'Œ': "Oe", 'ø': "o", 'Ø': "O",
+	'ł': "l", 'Ł': "L", 'đ': "d", 'Đ': "D", 'ð': "d", 'Ð': "D", 'þ': "th", 'Þ': "Th",
+	'ı': "i", 'ħ': "h", 'Ħ': "H",
+
+	// Japanese punctuation
+	'　': " ", '、': ",", '。': ".", '・': "-", 'ー': "-", '〜': "~",
+	'「': "(", '」': ")", '『': "(", '』': ")", '【': "(", '】': ")",
+}
+
+// isKana reports whether r is a hiragana or katakana letter (not ー or ・)
+func isKana(r rune) bool {
+	return (r >= 'ぁ' && r <= 'ゖ') || (r >= 'ァ' && r <= 'ヺ')
+}
+
+// kanaRomaji maps hiragana to modified Hepburn
+var kanaRomaji = map[string]string{
+	"あ": "a", "い": "i", "う": "u", "え": "e", "お": "o",
+	"か": "ka", "き": "ki", "く": "ku", "け": "ke", "こ": "ko",
+	"が": "ga", "ぎ": "gi", "ぐ": "gu", "げ": "ge", "ご": "go",
+	"さ": "sa", "し": "shi", "す": "su", "せ": "se", "そ": "so",
+	"ざ": "za", "じ": "ji", "ず": "zu", "ぜ": "ze", "ぞ": "zo",
+	"た": "ta", "ち": "chi", "つ": "tsu", "て": "te", "と": "to",
+	"だ": "da", "ぢ": "ji", "づ": "zu", "で": "de", "ど": "do",
+	"な": "na", "に": "ni", "ぬ": "nu", "ね": "ne", "の": "no",
+	"は": "ha", "ひ": "hi", "ふ": "fu", "へ": "he", "ほ": "ho",
+	"ば": "ba", "び": "bi", "ぶ": "bu", "べ": "be", "ぼ": "bo",
+	"ぱ": "pa", "ぴ": "pi", "ぷ": "pu", "ぺ": "pe", "ぽ": "po",
+	"ま": "ma", "み": "mi", "む": "mu", "め": "me", "も": "mo",
+	"や": "ya", "ゆ": "yu", "よ": "yo",
+	"ら": "ra", "り": "ri", "る": "ru", "れ": "re", "ろ": "ro",
+	"わ": "wa", "ゐ": "i", "ゑ": "e", "を": "o", "ん": "n", "ゔ": "vu",
+	"ぁ": "a", "ぃ": "i", "ぅ": "u", "ぇ": "e", "ぉ": "o",
+	"ゃ": "ya", "ゅ": "yu", "ょ": "yo", "ゎ": "wa", "ゕ": "ka", "ゖ": "ke",
+
+	// Sounds written with small vowels (mostly loanwords)
+	"ふぁ": "fa", "ふぃ": "fi", "ふぇ": "fe", "ふぉ": "fo",
+	"てぃ": "ti", "でぃ": "di", "とぅ": "tu", "どぅ": "du", "てゅ": "tyu", "でゅ": "dyu",
+	"うぃ": "wi", "うぇ": "we", "うぉ": "wo",
+	"ゔぁ": "va", "ゔぃ": "vi", "ゔぇ": "ve", "ゔぉ": "vo",
+	"しぇ": "she", "じぇ": "je", "ちぇ": "che", "いぇ": "ye",
+	"つぁ": "tsa", "つぃ": "tsi", "つぇ": "tse", "つぉ": "tso", "くぁ": "kwa",
+
+	// Contracted sounds
+	"きゃ": "kya", "きゅ": "kyu", "きょ": "kyo",
+	"ぎゃ": "gya", "ぎゅ": "gyu", "ぎょ": "gyo",
+	"しゃ": "sha", "しゅ": "shu", "しょ": "sho",
+	"じゃ": "ja", "じゅ": "ju", "じょ": "jo",
+	"ちゃ": "cha", "ちゅ": "chu", "ちょ": "cho",
+	"ぢゃ": "ja", "ぢゅ": "ju", "ぢょ": "jo",
+	"にゃ": "nya", "にゅ": "nyu", "にょ": "nyo",
+	"ひゃ": "hya", "ひゅ": "hyu", "ひょ": "hyo",
+	"びゃ": "bya", "びゅ": "byu", "びょ": "byo",
+	"ぴゃ": "pya", "ぴゅ": "pyu", "ぴょ": "pyo",
+	"みゃ": "mya", "みゅ": "myu", "みょ": "myo",
+	"りゃ": "rya", "りゅ": "ryu", "りょ": "ryo",
+
+	// Katakana-only letters
+	"ヷ": "va", "ヸ": "vi", "ヹ": "ve", "ヺ": "vo",
+}
+
+// romanizeKana romanizes the kana at the start of runes (a contracted sound when possible)
+// and returns the number of runes read
+func romanizeKana(runes []rune) (string, int) {
+	first := string(toHiragana(runes[0]))
+	if len(runes) > 1 && isKana(runes[1]) {
+		if romaji, ok := kanaRomaji[first+string(toHiragana(runes[1]))]; ok {
+			return romaji, 2
+		}
+	}
+	return kanaRomaji[first], 1
+}
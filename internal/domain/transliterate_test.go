@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransliterate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"hiragana", "さくら.jpg", "sakura.jpg"},
+		{"katakana", "ガイド.pdf", "gaido.pdf"},
+		{"hepburn shi chi tsu fu ji", "しちつふじ", "shichitsufuji"},
+		{"contracted sounds", "きょうしゅうじゃ", "kyoushuuja"},
+		{"sokuon", "ざっし", "zasshi"},
+		{"sokuon before ch", "マッチ", "matchi"},
+		{"sokuon at end", "あっ", "a"},
+		{"long vowel mark", "コーヒー", "koohii"},
+		{"long vowel mark after n", "ラーメンー", "raamen-"},
+		{"n", "しんぶん", "shinbun"},
+		{"loanword sounds", "ファイル・ディスク・ヴァイオリン", "fairu-disuku-vaiorin"},
+		{"half-width kana", "ｶﾞｲﾄﾞ", "gaido"},
+		{"nfd kana", "ガイド", "gaido"},
+		{"kanji fallback", "東京タワー.png", "_tawaa.png"},
+		{"kanji runs", "第1回_会議", "_1___"},
+		{"cyrillic", "Москва 2024", "Moskva 2024"},
+		{"cyrillic digraphs", "Щука Жук", "Shchuka Zhuk"},
+		{"cyrillic upper-case word", "ЩИ", "SHCHI"},
+		{"ukrainian letters", "Їжак", "Yizhak"},
+		{"greek with accents", "Αθήνα", "Athina"},
+		{"greek", "ψυχή", "psychi"},
+		{"accented latin", "Crème brûlée – Zoë.txt", "Creme brulee _ Zoe.txt"},
+		{"nfd latin", "café", "cafe"},
+		{"special latin", "Straße Øresund Łódź", "Strasse Oresund Lodz"},
+		{"full-width ascii", "ＡＢＣ１２３", "ABC123"},
+		{"japanese punctuation", "「メモ」、あ。", "(memo),a."},
+		{"ascii unchanged", "report-v2_final (1).txt", "report-v2_final (1).txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Transliterate(tt.input, DefaultFallback))
+		})
+	}
+}
+
+func TestTransliterateStrategy_Fallback(t *testing.T) {
+	strategy, err := NewTransliterateStrategy("")
+	assert.NoError(t, err)
+	assert.Equal(t, "tawaa.png", strategy.Apply("東京タワー.png"))
+
+	strategy, err = NewTransliterateStrategy("x")
+	assert.NoError(t, err)
+	assert.Equal(t, "x-x", strategy.Apply("漢字-🙂"))
+
+	for _, invalid := range []string{"/", "\\", "字", "\t"} {
+		_, err := NewTransliterateStrategy(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,299 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// Platform is a target whose file name rules a new name must follow
+type Platform string
+
+const (
+	PlatformMacOS   Platform = "macos"   // APFS/HFS+: no "/" or ":", 255 bytes
+	PlatformWindows Platform = "windows" // NTFS through the Win32 API: reserved characters and device names
+	PlatformLinux   Platform = "linux"   // ext4, btrfs...: no "/", 255 bytes
+	PlatformFAT     Platform = "fat"     // FAT32/exFAT (USB drives, SD cards): reserved characters, no trailing dots
+)
+
+// AllPlatforms lists every platform profile, strictest last
+var AllPlatforms = []Platform{PlatformMacOS, PlatformLinux, PlatformWindows, PlatformFAT}
+
+// maxNameLength is the usual limit of a single name (bytes on macOS and Linux, UTF-16 units on Windows and FAT)
+const maxNameLength = 255
+
+// windowsReservedCharacters cannot appear in names on Windows and FAT volumes
+const windowsReservedCharacters = `<>:"/\|?*`
+
+// Validate checks the platform name
+func (p Platform) Validate() error {
+	switch p {
+	case PlatformMacOS, PlatformWindows, PlatformLinux, PlatformFAT:
+		return nil
+	}
+	return fmt.Errorf("unknown platform: %q", p)
+}
+
+// PlatformFor returns the profile of an operating system (runtime.GOOS)
+func PlatformFor(goos string) Platform {
+	switch goos {
+	case "darwin", "ios":
+		return PlatformMacOS
+	case "windows":
+		return PlatformWindows
+	}
+	return PlatformLinux
+}
+
+// ParsePlatforms parses a comma-separated list of platforms ("all" selects every profile)
+func ParsePlatforms(text string) ([]Platform, error) {
+	if strings.TrimSpace(text) == "all" {
+		return AllPlatforms, nil
+	}
+	platforms := make([]Platform, 0)
+	for _, name := range strings.Split(text, ",") {
+		platform := Platform(strings.TrimSpace(name))
+		if err := platform.Validate(); err != nil {
+			return nil, err
+		}
+		platforms = append(platforms, platform)
+	}
+	return platforms, nil
+}
+
+// windowsStyle reports whether the platform follows the Win32 naming rules
+func (p Platform) windowsStyle() bool {
+	return p == PlatformWindows || p == PlatformFAT
+}
+
+// CaseInsensitive reports whether names differing only in case refer to the same file by default
+func (p Platform) CaseInsensitive() bool {
+	return p != PlatformLinux
+}
+
+// NameIssue explains why a name is invalid on a platform
+type NameIssue struct {
+	Platform Platform `json:"platform"`
+	Reason   string   `json:"reason"`
+}
+
+// ValidateName checks name against the rules of each platform
+// Returns nil when the name is valid everywhere
+func ValidateName(name string, platforms []Platform) []NameIssue {
+	var issues []NameIssue
+	for _, platform := range platforms {
+		for _, reason := range platform.nameProblems(name) {
+			issues = append(issues, NameIssue{Platform: platform, Reason: reason})
+		}
+	}
+	return issues
+}
+
+// nameProblems lists what makes name invalid on p
+func (p Platform) nameProblems(name string) []string {
+	if name == "" {
+		return []string{"empty name"}
+	}
+	if name == "." || name == ".." {
+		return []string{fmt.Sprintf("%q is reserved", name)}
+	}
+
+	var problems []string
+	if strings.ContainsRune(name, '/') {
+		problems = append(problems, `contains "/"`)
+	}
+	if strings.ContainsRune(name, 0) {
+		problems = append(problems, "contains a NUL character")
+	}
+
+	switch {
+	case p == PlatformMacOS:
+		// Finder shows ":" as "/" and many tools reject it
+		if strings.ContainsRune(name, ':') {
+			problems = append(problems, `contains ":"`)
+		}
+	case p.windowsStyle():
+		if reserved := reservedCharacters(name); reserved != "" {
+			problems = append(problems, fmt.Sprintf("contains reserved characters %s", reserved))
+		}
+		if strings.ContainsFunc(name, func(r rune) bool { return r > 0 && r < 0x20 }) {
+			problems = append(problems, "contains control characters")
+		}
+		if strings.HasSuffix(name, ".") || strings.HasSuffix(name, " ") {
+			problems = append(problems, "ends with a dot or space")
+		}
+	}
+	if p == PlatformWindows {
+		if device := reservedDeviceName(name); device != "" {
+			problems = append(problems, fmt.Sprintf("%s is a reserved device name", device))
+		}
+	}
+
+	if length, limit := p.nameLength(name), maxNameLength; length > limit {
+		unit := "bytes"
+		if p.windowsStyle() {
+			unit = "UTF-16 characters"
+		}
+		problems = append(problems, fmt.Sprintf("longer than %d %s (%d)", limit, unit, length))
+	}
+
+	return problems
+}
+
+// nameLength measures name in the units limited by p
+func (p Platform) nameLength(name string) int {
+	if p.windowsStyle() {
+		return len(utf16.Encode([]rune(name)))
+	}
+	return len(name)
+}
+
+// reservedCharacters returns the Windows reserved characters (other than "/") found in name, quoted
+func reservedCharacters(name string) string {
+	var found []string
+	for _, r := range windowsReservedCharacters {
+		if r != '/' && strings.ContainsRune(name, r) {
+			found = append(found, fmt.Sprintf("%q", r))
+		}
+	}
+	return strings.Join(found, " ")
+}
+
+// reservedDeviceName returns the device name (CON, NUL, COM1...) that name refers to on Windows, or ""
+// Extensions do not help: "nul.txt" still opens the NUL device
+func reservedDeviceName(name string) string {
+	base, _, _ := strings.Cut(name, ".")
+	base = strings.ToUpper(strings.TrimRight(base, " "))
+	switch base {
+	case "CON", "PRN", "AUX", "NUL":
+		return base
+	}
+
+	prefix, number := base[:min(3, len(base))], base[min(3, len(base)):]
+	if prefix != "COM" && prefix != "LPT" {
+		return ""
+	}
+	// Superscript digits count too (COM¹)
+	if (len(number) == 1 && number[0] >= '1' && number[0] <= '9') || number == "¹" || number == "²" || number == "³" {
+		return base
+	}
+	return ""
+}
+
+// SanitizeStrategy fixes names that are invalid on the target platforms
+// Reserved and control characters are replaced, trailing dots and spaces removed, device names
+// suffixed (CON.txt → CON_.txt) and long names shortened before the extension
+type SanitizeStrategy struct {
+	platforms   []Platform
+	replacement string
+}
+
+// NewSanitizeStrategy creates a strategy making names valid on platforms (all platforms when empty)
+// replacement takes the place of each invalid character and must itself be valid
+func NewSanitizeStrategy(platforms []Platform, replacement string) (*SanitizeStrategy, error) {
+	if len(platforms) == 0 {
+		platforms = AllPlatforms
+	}
+	for _, platform := range platforms {
+		if err := platform.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if strings.ContainsFunc(replacement, func(r rune) bool { return invalidCharacter(r, AllPlatforms) }) ||
+		strings.Trim(replacement, ". ") != replacement {
+		return nil, fmt.Errorf("invalid replacement: %q", replacement)
+	}
+
+	return &SanitizeStrategy{platforms: platforms, replacement: replacement}, nil
+}
+
+// invalidCharacter reports whether r cannot appear in a name on one of platforms
+func invalidCharacter(r rune, platforms []Platform) bool {
+	if r == '/' || r == 0 {
+		return true
+	}
+	for _, platform := range platforms {
+		switch {
+		case platform == PlatformMacOS && r == ':':
+			return true
+		case platform.windowsStyle() && (r < 0x20 || strings.ContainsRune(windowsReservedCharacters, r)):
+			return true
+		}
+	}
+	return false
+}
+
+// Apply makes filename valid on the target platforms
+func (s *SanitizeStrategy) Apply(filename string) string {
+	return s.ApplyContext(filename, RenameContext{})
+}
+
+// ApplyContext makes filename valid on the target platforms (directory names have no extension)
+func (s *SanitizeStrategy) ApplyContext(filename string, ctx RenameContext) string {
+	windowsStyle := false
+	for _, platform := range s.platforms {
+		windowsStyle = windowsStyle || platform.windowsStyle()
+	}
+
+	var builder strings.Builder
+	for _, r := range filename {
+		if invalidCharacter(r, s.platforms) {
+			builder.WriteString(s.replacement)
+		} else {
+			builder.WriteRune(r)
+		}
+	}
+	name := builder.String()
+
+	if windowsStyle {
+		name = strings.TrimRight(name, ". ")
+	}
+	if name == "" || name == "." || name == ".." {
+		name = strings.Repeat("_", max(1, len(name)))
+	}
+	for _, platform := range s.platforms {
+		if platform == PlatformWindows && reservedDeviceName(name) != "" {
+			// The device is named by the part before the first dot ("con.backup.txt" → "con_.backup.txt")
+			end := strings.IndexByte(name, '.')
+			if end < 0 {
+				end = len(name)
+			}
+			name = name[:end] + "_" + name[end:]
+		}
+	}
+
+	return s.shorten(name, ctx, windowsStyle)
+}
+
+// shorten cuts the stem until the name fits the length limit of every platform
+func (s *SanitizeStrategy) shorten(name string, ctx RenameContext, windowsStyle bool) string {
+	fits := func(candidate string) bool {
+		for _, platform := range s.platforms {
+			if platform.nameLength(candidate) > maxNameLength {
+				return false
+			}
+		}
+		return true
+	}
+	if fits(name) {
+		return name
+	}
+
+	stem, ext := ctx.SplitName(name)
+	if !fits("x" + ext) {
+		// An absurdly long extension: cut the whole name instead
+		stem, ext = name, ""
+	}
+	runes := []rune(stem)
+	for len(runes) > 1 && !fits(string(runes)+ext) {
+		runes = runes[:len(runes)-1]
+	}
+	stem = string(runes)
+	if windowsStyle {
+		stem = strings.TrimRight(stem, ". ")
+		if stem == "" {
+			stem = "_"
+		}
+	}
+	return stem + ext
+}
@@ -0,0 +1,170 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePlatforms(t *testing.T) {
+	platforms, err := ParsePlatforms("windows, fat")
+	assert.NoError(t, err)
+	assert.Equal(t, []Platform{PlatformWindows, PlatformFAT}, platforms)
+
+	platforms, err = ParsePlatforms("all")
+	assert.NoError(t, err)
+	assert.Equal(t, AllPlatforms, platforms)
+
+	_, err = ParsePlatforms("windows,amiga")
+	assert.Error(t, err)
+}
+
+func TestPlatformFor(t *testing.T) {
+	assert.Equal(t, PlatformMacOS, PlatformFor("darwin"))
+	assert.Equal(t, PlatformWindows, PlatformFor("windows"))
+	assert.Equal(t, PlatformLinux, PlatformFor("linux"))
+	assert.Equal(t, PlatformLinux, PlatformFor("freebsd"))
+}
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		platform Platform
+		reason   string // Expected reason ("" if valid)
+	}{
+		{"valid everywhere", "report 2024.txt", PlatformWindows, ""},
+		{"colon on linux", "10:30.txt", PlatformLinux, ""},
+		{"colon on macos", "10:30.txt", PlatformMacOS, `contains ":"`},
+		{"reserved characters on windows", `a<b>?.txt`, PlatformWindows, `contains reserved characters '<' '>' '?'`},
+		{"reserved characters on fat", "a|b.txt", PlatformFAT, `contains reserved characters '|'`},
+		{"backslash on linux", `a\b.txt`, PlatformLinux, ""},
+		{"slash", "a/b.txt", PlatformLinux, `contains "/"`},
+		{"control characters", "a\tb.txt", PlatformWindows, "contains control characters"},
+		{"trailing dot", "notes.", PlatformWindows, "ends with a dot or space"},
+		{"trailing space", "notes ", PlatformFAT, "ends with a dot or space"},
+		{"trailing dot on linux", "notes.", PlatformLinux, ""},
+		{"device name", "CON", PlatformWindows, "CON is a reserved device name"},
+		{"device name with extension", "nul.txt", PlatformWindows, "NUL is a reserved device name"},
+		{"numbered device", "com1.log", PlatformWindows, "COM1 is a reserved device name"},
+		{"not a device", "console.txt", PlatformWindows, ""},
+		{"com0 is not a device", "COM0", PlatformWindows, ""},
+		{"device name on linux", "CON", PlatformLinux, ""},
+		{"dot dot", "..", PlatformLinux, `".." is reserved`},
+		{"empty", "", PlatformMacOS, "empty name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := ValidateName(tt.input, []Platform{tt.platform})
+			if tt.reason == "" {
+				assert.Empty(t, issues)
+				return
+			}
+			assert.Equal(t, []NameIssue{{Platform: tt.platform, Reason: tt.reason}}, issues)
+		})
+	}
+}
+
+func TestValidateName_Length(t *testing.T) {
+	// 100 kana are 300 bytes but only 100 UTF-16 units
+	name := strings.Repeat("あ", 100)
+	issues := ValidateName(name, []Platform{PlatformLinux, PlatformWindows})
+	assert.Equal(t, []NameIssue{{Platform: PlatformLinux, Reason: "longer than 255 bytes (300)"}}, issues)
+
+	assert.Empty(t, ValidateName(strings.Repeat("a", 255), AllPlatforms))
+	assert.Len(t, ValidateName(strings.Repeat("a", 256), AllPlatforms), len(AllPlatforms))
+}
+
+func TestValidateName_SeveralPlatforms(t *testing.T) {
+	issues := ValidateName("a:b.", AllPlatforms)
+	assert.Equal(t, []NameIssue{
+		{Platform: PlatformMacOS, Reason: `contains ":"`},
+		{Platform: PlatformWindows, Reason: `contains reserved characters ':'`},
+		{Platform: PlatformWindows, Reason: "ends with a dot or space"},
+		{Platform: PlatformFAT, Reason: `contains reserved characters ':'`},
+		{Platform: PlatformFAT, Reason: "ends with a dot or space"},
+	}, issues)
+}
+
+func TestSanitizeStrategy(t *testing.T) {
+	tests := []struct {
+		name      string
+		platforms []Platform
+		input     string
+		expected  string
+	}{
+		{"reserved characters", []Platform{PlatformWindows}, `a<b>c?.txt`, "a_b_c_.txt"},
+		{"colon on macos", []Platform{PlatformMacOS}, "10:30 memo.txt", "10_30 memo.txt"},
+		{"colon kept on linux", []Platform{PlatformLinux}, "10:30 memo.txt", "10:30 memo.txt"},
+		{"control characters", []Platform{PlatformFAT}, "a\tb.txt", "a_b.txt"},
+		{"trailing dots and spaces", []Platform{PlatformWindows}, "notes. .", "notes"},
+		{"device name", []Platform{PlatformWindows}, "CON.txt", "CON_.txt"},
+		{"device name without extension", []Platform{PlatformWindows}, "lpt1", "lpt1_"},
+		{"device name with several dots", []Platform{PlatformWindows}, "con.backup.txt", "con_.backup.txt"},
+		{"device name with trailing space", []Platform{PlatformWindows}, "NUL .tar.gz", "NUL _.tar.gz"},
+		{"device name on linux", []Platform{PlatformLinux}, "CON.txt", "CON.txt"},
+		{"only dots", []Platform{PlatformWindows}, "...", "_"},
+		{"valid name unchanged", AllPlatforms, "report 2024.txt", "report 2024.txt"},
+		{"all platforms", nil, `a:b\c.txt`, "a_b_c.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := NewSanitizeStrategy(tt.platforms, DefaultFallback)
+			assert.NoError(t, err)
+			result := strategy.Apply(tt.input)
+			assert.Equal(t, tt.expected, result)
+			assert.Empty(t, ValidateName(result, strategy.platforms))
+		})
+	}
+}
+
+func TestSanitizeStrategy_OutputIsValid(t *testing.T) {
+	inputs := []string{
+		"con.backup.txt", "COM1.tar.gz", "aux", "Nul.", "prn .txt", "lpt²..txt", "CON.txt.", " con.txt",
+		`a<b>:c"d|e?f*g.txt`, "a\tb\x00c.txt", "...", ". .", "", "名前:メモ.txt",
+		strings.Repeat("あ", 100) + ".txt", "con" + strings.Repeat(".x", 200),
+	}
+	platformSets := [][]Platform{{PlatformWindows}, {PlatformMacOS}, {PlatformLinux}, {PlatformFAT}, AllPlatforms}
+
+	for _, platforms := range platformSets {
+		strategy, err := NewSanitizeStrategy(platforms, DefaultFallback)
+		assert.NoError(t, err)
+		for _, input := range inputs {
+			for _, ctx := range []RenameContext{{}, {IsDir: true}} {
+				result := strategy.ApplyContext(input, ctx)
+				assert.Empty(t, ValidateName(result, platforms), "%q on %v", input, platforms)
+			}
+		}
+	}
+}
+
+func TestSanitizeStrategy_Shorten(t *testing.T) {
+	strategy, err := NewSanitizeStrategy([]Platform{PlatformLinux}, DefaultFallback)
+	assert.NoError(t, err)
+
+	// Kana are cut whole, the extension is kept
+	result := strategy.Apply(strings.Repeat("あ", 100) + ".txt")
+	assert.Equal(t, strings.Repeat("あ", 83)+".txt", result)
+	assert.Empty(t, ValidateName(result, []Platform{PlatformLinux}))
+
+	// Directories have no extension
+	dir := strings.Repeat("a", 250) + ".backup"
+	assert.Equal(t, dir[:255], strategy.ApplyContext(dir, RenameContext{IsDir: true}))
+}
+
+func TestNewSanitizeStrategy_Invalid(t *testing.T) {
+	_, err := NewSanitizeStrategy([]Platform{"amiga"}, DefaultFallback)
+	assert.Error(t, err)
+
+	for _, invalid := range []string{"/", ":", "?", "."} {
+		_, err := NewSanitizeStrategy(nil, invalid)
+		assert.Error(t, err, invalid)
+	}
+
+	strategy, err := NewSanitizeStrategy([]Platform{PlatformWindows}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "ab.txt", strategy.Apply("a*b.txt"))
+}
@@ -0,0 +1,122 @@
+package metadata
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrNoTags is returned when a file has no readable audio tags (or its format is not supported)
+var ErrNoTags = errors.New("no audio tags")
+
+// Audio holds the tags of a music file (zero values when a tag is missing)
+type Audio struct {
+	Title  string
+	Artist string
+	Album  string
+	Track  int
+	Disc   int
+	Year   int
+}
+
+// empty reports whether no tag was found
+func (a *Audio) empty() bool {
+	return *a == Audio{}
+}
+
+// ReadAudio reads the tags of an MP3 (ID3v2, ID3v1), FLAC, Ogg Vorbis/Opus or MP4/M4A file
+func ReadAudio(r io.ReaderAt, size int64) (*Audio, error) {
+	header := make([]byte, 12)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, ErrNoTags
+	}
+
+	var audio *Audio
+	switch {
+	case string(header[:3]) == "ID3":
+		audio = readID3v2(r, size)
+	case string(header[:4]) == "fLaC":
+		audio = readFLAC(r, size)
+	case string(header[:4]) == "OggS":
+		audio = readOgg(r, size)
+	case string(header[4:8]) == "ftyp":
+		audio = readMP4(r, size)
+	}
+
+	// ID3v1 is appended to the end of MP3 files (with or without ID3v2)
+	if audio == nil || audio.empty() {
+		audio = readID3v1(r, size)
+	}
+	if audio == nil || audio.empty() {
+		return nil, ErrNoTags
+	}
+	return audio, nil
+}
+
+// hasField reports whether field is already set
+// The first value wins (a tag may repeat a field, or hold both TYER and TDRC)
+func (a *Audio) hasField(field string) bool {
+	switch field {
+	case "title":
+		return a.Title != ""
+	case "artist":
+		return a.Artist != ""
+	case "album":
+		return a.Album != ""
+	case "track":
+		return a.Track != 0
+	case "disc":
+		return a.Disc != 0
+	case "year":
+		return a.Year != 0
+	}
+	return false
+}
+
+// setText sets a tag from its text value; numbers like "3/12" keep the part before the slash
+// and years are taken from dates like "2004-05-01"
+func (a *Audio) setText(field, value string) {
+	value = strings.TrimSpace(strings.TrimRight(value, "\x00"))
+	if value == "" {
+		return
+	}
+
+	switch field {
+	case "title":
+		a.Title = value
+	case "artist":
+		a.Artist = value
+	case "album":
+		a.Album = value
+	case "track":
+		a.Track = leadingNumber(value)
+	case "disc":
+		a.Disc = leadingNumber(value)
+	case "year":
+		if len(value) >= 4 {
+			if year, err := strconv.Atoi(value[:4]); err == nil {
+				a.Year = year
+			}
+		}
+	}
+}
+
+// leadingNumber parses the digits at the start of value ("3/12" → 3)
+func leadingNumber(value string) int {
+	end := 0
+	for end < len(value) && value[end] >= '0' && value[end] <= '9' {
+		end++
+	}
+	number, _ := strconv.Atoi(value[:end])
+	return number
+}
+
+// latin1 decodes ISO-8859-1 text
+func latin1(data []byte) string {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
@@ -0,0 +1,202 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var sampleAudio = Audio{Title: "Für Elise", Artist: "Beethoven", Album: "Piano Works", Track: 3, Disc: 1, Year: 1810}
+
+func syncsafeBytes(value int) []byte {
+	return []byte{byte(value >> 21 & 0x7F), byte(value >> 14 & 0x7F), byte(value >> 7 & 0x7F), byte(value & 0x7F)}
+}
+
+func utf16Text(value string) []byte {
+	data := []byte{0xFF, 0xFE}
+	for _, unit := range utf16.Encode([]rune(value)) {
+		data = binary.LittleEndian.AppendUint16(data, unit)
+	}
+	return data
+}
+
+// id3Frame builds an ID3v2 frame of the given version
+func id3Frame(version byte, id string, data []byte) []byte {
+	switch version {
+	case 2:
+		return append([]byte{id[0], id[1], id[2], byte(len(data) >> 16), byte(len(data) >> 8), byte(len(data))}, data...)
+	case 3:
+		header := append([]byte(id), 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(header[4:], uint32(len(data)))
+		return append(header, data...)
+	default:
+		header := append([]byte(id), syncsafeBytes(len(data))...)
+		return append(append(header, 0, 0), data...)
+	}
+}
+
+func buildID3v2(version byte, frames ...[]byte) []byte {
+	body := append(bytes.Join(frames, nil), make([]byte, 32)...) // Padding
+	header := append([]byte{'I', 'D', '3', version, 0, 0}, syncsafeBytes(len(body))...)
+	// Followed by an MPEG frame header
+	return append(append(header, body...), 0xFF, 0xFB, 0x90, 0x00)
+}
+
+func buildID3v1() []byte {
+	tag := make([]byte, 128)
+	copy(tag, "TAG")
+	copy(tag[3:], "F\xFCr Elise") // ISO-8859-1
+	copy(tag[33:], "Beethoven")
+	copy(tag[63:], "Piano Works")
+	copy(tag[93:], "1810")
+	tag[126] = 3
+	return append([]byte{0xFF, 0xFB, 0x90, 0x00}, tag...)
+}
+
+func vorbisComment(comments ...string) []byte {
+	data := binary.LittleEndian.AppendUint32(nil, 6)
+	data = append(data, "vendor"...)
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(comments)))
+	for _, comment := range comments {
+		data = binary.LittleEndian.AppendUint32(data, uint32(len(comment)))
+		data = append(data, comment...)
+	}
+	return data
+}
+
+var sampleComments = []string{"title=Für Elise", "ARTIST=Beethoven", "ARTIST=Other", "ALBUM=Piano Works", "TRACKNUMBER=3/12", "DISCNUMBER=1", "DATE=1810-04-27", "COMMENT=x"}
+
+func buildFLAC() []byte {
+	streamInfo := append([]byte{0, 0, 0, 34}, make([]byte, 34)...)
+	comment := vorbisComment(sampleComments...)
+	header := []byte{0x80 | flacVorbisComment, byte(len(comment) >> 16), byte(len(comment) >> 8), byte(len(comment))}
+	return bytes.Join([][]byte{[]byte("fLaC"), streamInfo, header, comment}, nil)
+}
+
+// buildOgg splits packets into pages of at most maxSegments segments
+func buildOgg(maxSegments int, packets ...[]byte) []byte {
+	segments := make([][]byte, 0)
+	for _, packet := range packets {
+		for len(packet) >= 255 {
+			segments = append(segments, packet[:255])
+			packet = packet[255:]
+		}
+		segments = append(segments, packet)
+	}
+
+	var file []byte
+	for len(segments) > 0 {
+		count := min(maxSegments, len(segments))
+		header := make([]byte, 27)
+		copy(header, "OggS")
+		binary.LittleEndian.PutUint32(header[14:], 0x1234)
+		header[26] = byte(count)
+		file = append(file, header...)
+		for _, segment := range segments[:count] {
+			file = append(file, byte(len(segment)))
+		}
+		for _, segment := range segments[:count] {
+			file = append(file, segment...)
+		}
+		segments = segments[count:]
+	}
+	return file
+}
+
+func buildM4A() []byte {
+	text := func(typ, value string) []byte {
+		return bmffBox(typ, bmffBox("data", []byte{0, 0, 0, 1, 0, 0, 0, 0}, []byte(value)))
+	}
+	number := func(typ string, value uint16) []byte {
+		return bmffBox(typ, bmffBox("data", make([]byte, 8), []byte{0, 0, byte(value >> 8), byte(value), 0, 12, 0, 0}))
+	}
+
+	ilst := bmffBox("ilst",
+		text("\xA9nam", "Für Elise"), text("\xA9ART", "Beethoven"), text("\xA9alb", "Piano Works"),
+		text("\xA9day", "1810"), number("trkn", 3), number("disk", 1), text("\xA9too", "encoder"),
+	)
+	meta := bmffBox("meta", []byte{0, 0, 0, 0}, bmffBox("hdlr", make([]byte, 8), []byte("mdir"), make([]byte, 13)), ilst)
+	moov := bmffBox("moov", bmffBox("mvhd", make([]byte, 100)), bmffBox("udta", meta))
+	return bytes.Join([][]byte{bmffBox("ftyp", []byte("M4A \x00\x00\x00\x00M4A mp42isom")), moov, bmffBox("mdat", make([]byte, 16))}, nil)
+}
+
+func TestReadAudio(t *testing.T) {
+	largeComment := "COMMENT=" + string(bytes.Repeat([]byte("x"), 1000))
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"id3v2.3 utf-16", buildID3v2(3,
+			id3Frame(3, "TIT2", append([]byte{1}, utf16Text("Für Elise")...)),
+			id3Frame(3, "TPE1", []byte("\x00Beethoven")),
+			id3Frame(3, "TALB", []byte("\x00Piano Works\x00")),
+			id3Frame(3, "TRCK", []byte("\x003/12")),
+			id3Frame(3, "TPOS", []byte("\x001/2")),
+			id3Frame(3, "TYER", []byte("\x001810")),
+			id3Frame(3, "APIC", make([]byte, 300)),
+		)},
+		{"id3v2.4 utf-8", buildID3v2(4,
+			id3Frame(4, "TIT2", []byte("\x03Für Elise\x00Alternative")),
+			id3Frame(4, "TPE1", []byte("\x03Beethoven")),
+			id3Frame(4, "TALB", []byte("\x03Piano Works")),
+			id3Frame(4, "TRCK", []byte("\x033")),
+			id3Frame(4, "TPOS", []byte("\x031")),
+			id3Frame(4, "TDRC", []byte("\x031810-04-27")),
+		)},
+		{"id3v2.2", buildID3v2(2,
+			id3Frame(2, "TT2", []byte("\x00F\xFCr Elise")),
+			id3Frame(2, "TP1", []byte("\x00Beethoven")),
+			id3Frame(2, "TAL", []byte("\x00Piano Works")),
+			id3Frame(2, "TRK", []byte("\x003")),
+			id3Frame(2, "TPA", []byte("\x001")),
+			id3Frame(2, "TYE", []byte("\x001810")),
+		)},
+		{"flac", buildFLAC()},
+		{"ogg vorbis", buildOgg(4, []byte("\x01vorbis-identification"), append([]byte("\x03vorbis"), vorbisComment(append(sampleComments, largeComment)...)...))},
+		{"ogg opus", buildOgg(255, []byte("OpusHead-identification"), append([]byte("OpusTags"), vorbisComment(sampleComments...)...))},
+		{"m4a", buildM4A()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audio, err := ReadAudio(bytes.NewReader(tt.data), int64(len(tt.data)))
+
+			assert.NoError(t, err)
+			assert.Equal(t, sampleAudio, *audio)
+		})
+	}
+}
+
+func TestReadAudio_ID3v1(t *testing.T) {
+	data := buildID3v1()
+
+	audio, err := ReadAudio(bytes.NewReader(data), int64(len(data)))
+
+	assert.NoError(t, err)
+	assert.Equal(t, Audio{Title: "Für Elise", Artist: "Beethoven", Album: "Piano Works", Track: 3, Year: 1810}, *audio)
+}
+
+func TestReadAudio_NoTags(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"text", []byte("hello, this is not a song")},
+		{"empty id3v2", buildID3v2(3)},
+		{"compressed frames only", buildID3v2(3, append(id3Frame(3, "TIT2", []byte("\x00x"))[:8], 0, 0x80, 0, 'x'))},
+		{"flac without comments", append([]byte("fLaC"), append([]byte{0x80, 0, 0, 34}, make([]byte, 34)...)...)},
+		{"truncated ogg", buildOgg(255, []byte("\x01vorbis"), append([]byte("\x03vorbis"), vorbisComment(sampleComments...)...))[:60]},
+		{"heic", buildHEIC(buildTIFF(binary.LittleEndian))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadAudio(bytes.NewReader(tt.data), int64(len(tt.data)))
+			assert.ErrorIs(t, err, ErrNoTags)
+		})
+	}
+}
@@ -0,0 +1,353 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// ErrNoEXIF is returned when a file has no readable EXIF block (or its format is not supported)
+var ErrNoEXIF = errors.New("no EXIF data")
+
+// EXIF holds the fields read from the EXIF block of a photo
+type EXIF struct {
+	DateTimeOriginal time.Time // Capture time (local time unless the camera recorded an offset)
+	Make             string
+	Model            string
+	LensModel        string
+	ISO              int
+	FNumber          float64
+	FocalLength      float64 // Millimeters
+	GPS              *GPS    // nil when the photo has no position
+}
+
+// GPS is a position in decimal degrees (south and west are negative)
+type GPS struct {
+	Latitude    float64
+	Longitude   float64
+	Altitude    float64 // Meters (negative below sea level)
+	HasAltitude bool
+}
+
+// EXIF tags (IFD0, Exif IFD and GPS IFD)
+const (
+	tagMake               = 0x010F
+	tagModel              = 0x0110
+	tagDateTime           = 0x0132
+	tagExifIFD            = 0x8769
+	tagGPSIFD             = 0x8825
+	tagExposureISO        = 0x8827
+	tagFNumber            = 0x829D
+	tagDateTimeOriginal   = 0x9003
+	tagOffsetTimeOriginal = 0x9011
+	tagFocalLength        = 0x920A
+	tagLensModel          = 0xA434
+
+	tagGPSLatitudeRef  = 0x0001
+	tagGPSLatitude     = 0x0002
+	tagGPSLongitudeRef = 0x0003
+	tagGPSLongitude    = 0x0004
+	tagGPSAltitudeRef  = 0x0005
+	tagGPSAltitude     = 0x0006
+)
+
+// exifDateLayout is the date format used by EXIF
+const exifDateLayout = "2006:01:02 15:04:05"
+
+// ReadEXIF reads the EXIF block of a JPEG, TIFF (including TIFF based raw formats) or HEIC/HEIF file
+func ReadEXIF(r io.ReaderAt, size int64) (*EXIF, error) {
+	header := make([]byte, 12)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, ErrNoEXIF
+	}
+
+	switch {
+	case header[0] == 0xFF && header[1] == 0xD8:
+		return readJPEG(r, size)
+	case bytes.HasPrefix(header, []byte("II*\x00")), bytes.HasPrefix(header, []byte("MM\x00*")):
+		return parseTIFF(r, 0, size)
+	case string(header[4:8]) == "ftyp":
+		return readHEIF(r, size)
+	}
+	return nil, ErrNoEXIF
+}
+
+// readJPEG finds the APP1 "Exif" segment before the image data
+func readJPEG(r io.ReaderAt, size int64) (*EXIF, error) {
+	offset := int64(2)
+	marker := make([]byte, 4)
+
+	for offset+4 <= size {
+		if _, err := r.ReadAt(marker, offset); err != nil {
+			return nil, ErrNoEXIF
+		}
+		if marker[0] != 0xFF {
+			return nil, ErrNoEXIF
+		}
+		if marker[1] == 0xFF {
+			// Fill byte
+			offset++
+			continue
+		}
+		if marker[1] == 0xDA || marker[1] == 0xD9 {
+			// Start of scan / end of image: no metadata after this point
+			break
+		}
+
+		length := int64(binary.BigEndian.Uint16(marker[2:]))
+		if length < 2 {
+			return nil, ErrNoEXIF
+		}
+		if marker[1] == 0xE1 && length >= 8 {
+			signature := make([]byte, 6)
+			if _, err := r.ReadAt(signature, offset+4); err == nil && string(signature) == "Exif\x00\x00" {
+				return parseTIFF(r, offset+10, length-8)
+			}
+		}
+		offset += 2 + length
+	}
+
+	return nil, ErrNoEXIF
+}
+
+// tiffReader reads a TIFF structure starting at base
+type tiffReader struct {
+	r     io.ReaderAt
+	base  int64
+	size  int64
+	order binary.ByteOrder
+}
+
+// ifdEntry is a raw IFD entry
+type ifdEntry struct {
+	tag      uint16
+	typ      uint16
+	count    uint32
+	valuePos int64 // Offset of the value relative to base
+}
+
+// Sizes of the TIFF field types in bytes
+var tiffTypeSizes = map[uint16]int64{
+	1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 6: 1, 7: 1, 8: 2, 9: 4, 10: 8, 11: 4, 12: 8,
+}
+
+// maxValueSize limits the size of a single value read from a (possibly corrupt) file
+const maxValueSize = 64 * 1024
+
+// parseTIFF parses the TIFF header at base and collects the supported tags
+func parseTIFF(r io.ReaderAt, base, size int64) (*EXIF, error) {
+	tiff := &tiffReader{r: r, base: base, size: size}
+
+	header, err := tiff.read(0, 8)
+	if err != nil {
+		return nil, ErrNoEXIF
+	}
+	switch string(header[:2]) {
+	case "II":
+		tiff.order = binary.LittleEndian
+	case "MM":
+		tiff.order = binary.BigEndian
+	default:
+		return nil, ErrNoEXIF
+	}
+	if tiff.order.Uint16(header[2:]) != 42 {
+		return nil, ErrNoEXIF
+	}
+
+	ifd0, err := tiff.readIFD(int64(tiff.order.Uint32(header[4:])))
+	if err != nil {
+		return nil, err
+	}
+
+	exif := &EXIF{
+		Make:  tiff.ascii(ifd0[tagMake]),
+		Model: tiff.ascii(ifd0[tagModel]),
+	}
+	dateTime := tiff.ascii(ifd0[tagDateTime])
+
+	if entry, ok := ifd0[tagExifIFD]; ok {
+		if sub, err := tiff.readIFD(int64(tiff.uint(entry))); err == nil {
+			if original := tiff.ascii(sub[tagDateTimeOriginal]); original != "" {
+				dateTime = original
+			}
+			exif.DateTimeOriginal = parseEXIFDate(dateTime, tiff.ascii(sub[tagOffsetTimeOriginal]))
+			exif.LensModel = tiff.ascii(sub[tagLensModel])
+			exif.ISO = int(tiff.uint(sub[tagExposureISO]))
+			exif.FNumber = tiff.rational(sub[tagFNumber], 0)
+			exif.FocalLength = tiff.rational(sub[tagFocalLength], 0)
+		}
+	}
+	if exif.DateTimeOriginal.IsZero() {
+		exif.DateTimeOriginal = parseEXIFDate(dateTime, "")
+	}
+
+	if entry, ok := ifd0[tagGPSIFD]; ok {
+		if gps, err := tiff.readIFD(int64(tiff.uint(entry))); err == nil {
+			exif.GPS = tiff.gps(gps)
+		}
+	}
+
+	return exif, nil
+}
+
+// read returns n bytes at offset (relative to base)
+func (t *tiffReader) read(offset, n int64) ([]byte, error) {
+	if offset < 0 || n < 0 || n > maxValueSize || offset+n > t.size {
+		return nil, fmt.Errorf("invalid TIFF offset %d", offset)
+	}
+	data := make([]byte, n)
+	if _, err := t.r.ReadAt(data, t.base+offset); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// readIFD reads the entries of the IFD at offset, keyed by tag
+func (t *tiffReader) readIFD(offset int64) (map[uint16]ifdEntry, error) {
+	countBytes, err := t.read(offset, 2)
+	if err != nil {
+		return nil, ErrNoEXIF
+	}
+	count := int64(t.order.Uint16(countBytes))
+
+	data, err := t.read(offset+2, count*12)
+	if err != nil {
+		return nil, ErrNoEXIF
+	}
+
+	entries := make(map[uint16]ifdEntry, count)
+	for i := int64(0); i < count; i++ {
+		raw := data[i*12 : i*12+12]
+		entry := ifdEntry{
+			tag:      t.order.Uint16(raw[0:]),
+			typ:      t.order.Uint16(raw[2:]),
+			count:    t.order.Uint32(raw[4:]),
+			valuePos: offset + 2 + i*12 + 8,
+		}
+		// Values larger than 4 bytes are stored at an offset
+		if tiffTypeSizes[entry.typ]*int64(entry.count) > 4 {
+			entry.valuePos = int64(t.order.Uint32(raw[8:]))
+		}
+		entries[entry.tag] = entry
+	}
+	return entries, nil
+}
+
+// value returns the raw bytes of an entry (nil for missing entries)
+func (t *tiffReader) value(entry ifdEntry) []byte {
+	size := tiffTypeSizes[entry.typ]
+	if size == 0 || entry.count == 0 {
+		return nil
+	}
+	data, err := t.read(entry.valuePos, size*int64(entry.count))
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// ascii returns a string value without padding
+func (t *tiffReader) ascii(entry ifdEntry) string {
+	if entry.typ != 2 && entry.typ != 7 {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimRight(string(t.value(entry)), "\x00"))
+}
+
+// uint returns the first value of a BYTE, SHORT or LONG entry
+func (t *tiffReader) uint(entry ifdEntry) uint32 {
+	data := t.value(entry)
+	switch {
+	case data == nil:
+		return 0
+	case entry.typ == 1:
+		return uint32(data[0])
+	case entry.typ == 3:
+		return uint32(t.order.Uint16(data))
+	case entry.typ == 4:
+		return t.order.Uint32(data)
+	}
+	return 0
+}
+
+// rational returns the index-th value of a RATIONAL or SRATIONAL entry
+func (t *tiffReader) rational(entry ifdEntry, index int) float64 {
+	if (entry.typ != 5 && entry.typ != 10) || uint32(index) >= entry.count {
+		return 0
+	}
+	data := t.value(entry)
+	if data == nil {
+		return 0
+	}
+
+	numerator := t.order.Uint32(data[index*8:])
+	denominator := t.order.Uint32(data[index*8+4:])
+	if denominator == 0 {
+		return 0
+	}
+	if entry.typ == 10 {
+		return float64(int32(numerator)) / float64(int32(denominator))
+	}
+	return float64(numerator) / float64(denominator)
+}
+
+// gps converts the GPS IFD to decimal degrees
+func (t *tiffReader) gps(entries map[uint16]ifdEntry) *GPS {
+	latitude, okLat := t.degrees(entries[tagGPSLatitude])
+	longitude, okLon := t.degrees(entries[tagGPSLongitude])
+	if !okLat || !okLon {
+		return nil
+	}
+	if t.ascii(entries[tagGPSLatitudeRef]) == "S" {
+		latitude = -latitude
+	}
+	if t.ascii(entries[tagGPSLongitudeRef]) == "W" {
+		longitude = -longitude
+	}
+
+	gps := &GPS{Latitude: latitude, Longitude: longitude}
+	if entry, ok := entries[tagGPSAltitude]; ok {
+		gps.Altitude = t.rational(entry, 0)
+		gps.HasAltitude = true
+		if ref := t.value(entries[tagGPSAltitudeRef]); len(ref) > 0 && ref[0] == 1 {
+			gps.Altitude = -gps.Altitude
+		}
+	}
+	return gps
+}
+
+// degrees converts degrees, minutes and seconds rationals to decimal degrees
+func (t *tiffReader) degrees(entry ifdEntry) (float64, bool) {
+	if entry.count < 3 {
+		return 0, false
+	}
+	value := t.rational(entry, 0) + t.rational(entry, 1)/60 + t.rational(entry, 2)/3600
+	return math.Round(value*1e7) / 1e7, true
+}
+
+// parseEXIFDate parses an EXIF date with an optional offset like "+09:00"
+// Invalid or blank dates ("0000:00:00 00:00:00") return the zero time
+func parseEXIFDate(value, offset string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+
+	location := time.Local
+	if offset != "" {
+		if zone, err := time.Parse("-07:00", offset); err == nil {
+			_, seconds := zone.Zone()
+			location = time.FixedZone(offset, seconds)
+		}
+	}
+
+	date, err := time.ParseInLocation(exifDateLayout, value, location)
+	if err != nil {
+		return time.Time{}
+	}
+	return date
+}
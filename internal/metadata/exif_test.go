@@ -0,0 +1,244 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testEntry is an IFD entry for building test files
+type testEntry struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	data  []byte
+}
+
+func asciiEntry(tag uint16, value string) testEntry {
+	return testEntry{tag: tag, typ: 2, count: uint32(len(value) + 1), data: append([]byte(value), 0)}
+}
+
+func shortEntry(order binary.ByteOrder, tag uint16, value uint16) testEntry {
+	data := make([]byte, 2)
+	order.PutUint16(data, value)
+	return testEntry{tag: tag, typ: 3, count: 1, data: data}
+}
+
+func longEntry(order binary.ByteOrder, tag uint16, value uint32) testEntry {
+	data := make([]byte, 4)
+	order.PutUint32(data, value)
+	return testEntry{tag: tag, typ: 4, count: 1, data: data}
+}
+
+func rationalEntry(order binary.ByteOrder, tag uint16, values ...[2]uint32) testEntry {
+	data := make([]byte, 8*len(values))
+	for i, value := range values {
+		order.PutUint32(data[i*8:], value[0])
+		order.PutUint32(data[i*8+4:], value[1])
+	}
+	return testEntry{tag: tag, typ: 5, count: uint32(len(values)), data: data}
+}
+
+// writeIFD appends an IFD (with its out-of-line values) to buf and returns its offset
+func writeIFD(buf []byte, order binary.ByteOrder, entries []testEntry) ([]byte, uint32) {
+	offset := len(buf)
+	dataOffset := offset + 2 + 12*len(entries) + 4
+
+	ifd := make([]byte, 2+12*len(entries)+4)
+	order.PutUint16(ifd, uint16(len(entries)))
+	data := make([]byte, 0)
+	for i, entry := range entries {
+		raw := ifd[2+12*i:]
+		order.PutUint16(raw[0:], entry.tag)
+		order.PutUint16(raw[2:], entry.typ)
+		order.PutUint32(raw[4:], entry.count)
+		if len(entry.data) <= 4 {
+			copy(raw[8:12], entry.data)
+			continue
+		}
+		order.PutUint32(raw[8:], uint32(dataOffset+len(data)))
+		data = append(data, entry.data...)
+		if len(data)%2 == 1 {
+			data = append(data, 0)
+		}
+	}
+
+	buf = append(buf, ifd...)
+	return append(buf, data...), uint32(offset)
+}
+
+// buildTIFF creates a TIFF block with camera, capture and GPS information
+func buildTIFF(order binary.ByteOrder) []byte {
+	buf := make([]byte, 8)
+	if order == binary.LittleEndian {
+		copy(buf, "II")
+	} else {
+		copy(buf, "MM")
+	}
+	order.PutUint16(buf[2:], 42)
+
+	buf, exifIFD := writeIFD(buf, order, []testEntry{
+		asciiEntry(tagDateTimeOriginal, "2024:05:01 14:30:15"),
+		asciiEntry(tagOffsetTimeOriginal, "+09:00"),
+		asciiEntry(tagLensModel, "RF24-70mm F2.8 L IS USM"),
+		shortEntry(order, tagExposureISO, 400),
+		rationalEntry(order, tagFNumber, [2]uint32{28, 10}),
+		rationalEntry(order, tagFocalLength, [2]uint32{50, 1}),
+	})
+	buf, gpsIFD := writeIFD(buf, order, []testEntry{
+		asciiEntry(tagGPSLatitudeRef, "N"),
+		rationalEntry(order, tagGPSLatitude, [2]uint32{35, 1}, [2]uint32{39, 1}, [2]uint32{3144, 100}),
+		asciiEntry(tagGPSLongitudeRef, "E"),
+		rationalEntry(order, tagGPSLongitude, [2]uint32{139, 1}, [2]uint32{44, 1}, [2]uint32{5616, 100}),
+		{tag: tagGPSAltitudeRef, typ: 1, count: 1, data: []byte{0}},
+		rationalEntry(order, tagGPSAltitude, [2]uint32{405, 10}),
+	})
+	buf, ifd0 := writeIFD(buf, order, []testEntry{
+		asciiEntry(tagMake, "Canon"),
+		asciiEntry(tagModel, "Canon EOS R5"),
+		asciiEntry(tagDateTime, "2024:06:01 10:00:00"),
+		longEntry(order, tagExifIFD, exifIFD),
+		longEntry(order, tagGPSIFD, gpsIFD),
+	})
+
+	order.PutUint32(buf[4:], ifd0)
+	return buf
+}
+
+// buildJPEG wraps a TIFF block in a JPEG APP1 segment after a JFIF APP0 segment
+func buildJPEG(tiff []byte) []byte {
+	var buf bytes.Buffer
+	buf.Write([]byte{0xFF, 0xD8})
+	buf.Write([]byte{0xFF, 0xE0, 0x00, 0x10})
+	buf.Write([]byte("JFIF\x00\x01\x01\x00\x00\x01\x00\x01\x00\x00"))
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	buf.Write([]byte{0xFF, 0xE1})
+	binary.Write(&buf, binary.BigEndian, uint16(len(segment)+2))
+	buf.Write(segment)
+
+	buf.Write([]byte{0xFF, 0xDA, 0x00, 0x02, 0x12, 0x34, 0xFF, 0xD9})
+	return buf.Bytes()
+}
+
+func bmffBox(typ string, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header, uint32(8+len(body)))
+	copy(header[4:], typ)
+	return append(header, body...)
+}
+
+// buildHEIC creates a minimal HEIC file whose item 2 is the Exif block stored in mdat
+func buildHEIC(tiff []byte) []byte {
+	exifItem := append([]byte{0, 0, 0, 6}, append([]byte("Exif\x00\x00"), tiff...)...)
+
+	ftyp := bmffBox("ftyp", []byte("heic\x00\x00\x00\x00mif1heic"))
+	hdlr := bmffBox("hdlr", make([]byte, 8), []byte("pict"), make([]byte, 13))
+	iinf := bmffBox("iinf", []byte{0, 0, 0, 0, 0, 2},
+		bmffBox("infe", []byte{2, 0, 0, 0, 0, 1, 0, 0}, []byte("hvc1\x00")),
+		bmffBox("infe", []byte{2, 0, 0, 0, 0, 2, 0, 0}, []byte("Exif\x00")),
+	)
+
+	buildMeta := func(exifOffset uint32) []byte {
+		iloc := []byte{0, 0, 0, 0, 0x44, 0x00, 0, 2}
+		for _, item := range []struct {
+			id     uint16
+			offset uint32
+			length uint32
+		}{{1, 0, 0}, {2, exifOffset, uint32(len(exifItem))}} {
+			entry := make([]byte, 2+2+2+4+4)
+			binary.BigEndian.PutUint16(entry[0:], item.id)
+			binary.BigEndian.PutUint16(entry[4:], 1)
+			binary.BigEndian.PutUint32(entry[6:], item.offset)
+			binary.BigEndian.PutUint32(entry[10:], item.length)
+			iloc = append(iloc, entry...)
+		}
+		return bmffBox("meta", []byte{0, 0, 0, 0}, hdlr, iinf, bmffBox("iloc", iloc))
+	}
+
+	exifOffset := uint32(len(ftyp) + len(buildMeta(0)) + 8)
+	return bytes.Join([][]byte{ftyp, buildMeta(exifOffset), bmffBox("mdat", exifItem)}, nil)
+}
+
+func assertSampleEXIF(t *testing.T, exif *EXIF) {
+	assert.Equal(t, "Canon", exif.Make)
+	assert.Equal(t, "Canon EOS R5", exif.Model)
+	assert.Equal(t, "RF24-70mm F2.8 L IS USM", exif.LensModel)
+	assert.Equal(t, 400, exif.ISO)
+	assert.InDelta(t, 2.8, exif.FNumber, 1e-9)
+	assert.InDelta(t, 50, exif.FocalLength, 1e-9)
+
+	expected := time.Date(2024, 5, 1, 14, 30, 15, 0, time.FixedZone("+09:00", 9*3600))
+	assert.True(t, expected.Equal(exif.DateTimeOriginal))
+	assert.Equal(t, "2024-05-01 14:30:15", exif.DateTimeOriginal.Format("2006-01-02 15:04:05"))
+
+	if assert.NotNil(t, exif.GPS) {
+		assert.InDelta(t, 35.658733, exif.GPS.Latitude, 1e-6)
+		assert.InDelta(t, 139.748933, exif.GPS.Longitude, 1e-6)
+		assert.InDelta(t, 40.5, exif.GPS.Altitude, 1e-9)
+		assert.True(t, exif.GPS.HasAltitude)
+	}
+}
+
+func TestReadEXIF(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"tiff little endian", buildTIFF(binary.LittleEndian)},
+		{"tiff big endian", buildTIFF(binary.BigEndian)},
+		{"jpeg", buildJPEG(buildTIFF(binary.BigEndian))},
+		{"heic", buildHEIC(buildTIFF(binary.LittleEndian))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exif, err := ReadEXIF(bytes.NewReader(tt.data), int64(len(tt.data)))
+			assert.NoError(t, err)
+			assertSampleEXIF(t, exif)
+		})
+	}
+}
+
+func TestReadEXIF_FallbackToDateTime(t *testing.T) {
+	order := binary.LittleEndian
+	buf := make([]byte, 8)
+	copy(buf, "II")
+	order.PutUint16(buf[2:], 42)
+	buf, ifd0 := writeIFD(buf, order, []testEntry{
+		asciiEntry(tagModel, "iPhone 15 Pro"),
+		asciiEntry(tagDateTime, "2023:12:24 18:00:00"),
+	})
+	order.PutUint32(buf[4:], ifd0)
+
+	exif, err := ReadEXIF(bytes.NewReader(buf), int64(len(buf)))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "iPhone 15 Pro", exif.Model)
+	assert.Equal(t, "2023-12-24 18:00:00", exif.DateTimeOriginal.Format("2006-01-02 15:04:05"))
+	assert.Nil(t, exif.GPS)
+}
+
+func TestReadEXIF_NoEXIF(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"text", []byte("hello, this is not a photo")},
+		{"jpeg without exif", []byte{0xFF, 0xD8, 0xFF, 0xDA, 0x00, 0x02, 0xFF, 0xD9, 0, 0, 0, 0}},
+		{"truncated tiff", buildTIFF(binary.LittleEndian)[:12]},
+		{"truncated jpeg", buildJPEG(buildTIFF(binary.BigEndian))[:40]},
+		{"heic without meta", bmffBox("ftyp", []byte("heic\x00\x00\x00\x00mif1heic"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadEXIF(bytes.NewReader(tt.data), int64(len(tt.data)))
+			assert.ErrorIs(t, err, ErrNoEXIF)
+		})
+	}
+}
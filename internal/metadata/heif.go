@@ -0,0 +1,226 @@
+package metadata
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// box is an ISO base media file format box
+type box struct {
+	typ     string
+	start   int64 // Offset of the payload
+	end     int64 // Offset after the box
+	payload []byte
+}
+
+// maxBoxPayload limits the boxes loaded into memory (item info and locations are small)
+const maxBoxPayload = 4 * 1024 * 1024
+
+// readBoxes lists the boxes between start and end
+func readBoxes(r io.ReaderAt, start, end int64) []box {
+	boxes := make([]box, 0)
+	header := make([]byte, 16)
+
+	for offset := start; offset+8 <= end; {
+		if _, err := r.ReadAt(header[:8], offset); err != nil {
+			break
+		}
+		size := int64(binary.BigEndian.Uint32(header))
+		typ := string(header[4:8])
+		headerSize := int64(8)
+
+		switch size {
+		case 0:
+			size = end - offset
+		case 1:
+			if _, err := r.ReadAt(header[8:16], offset+8); err != nil {
+				return boxes
+			}
+			size = int64(binary.BigEndian.Uint64(header[8:16]))
+			headerSize = 16
+		}
+		if size < headerSize || offset+size > end {
+			break
+		}
+
+		boxes = append(boxes, box{typ: typ, start: offset + headerSize, end: offset + size})
+		offset += size
+	}
+
+	return boxes
+}
+
+// load reads the payload of b
+func (b *box) load(r io.ReaderAt) bool {
+	size := b.end - b.start
+	if size > maxBoxPayload {
+		return false
+	}
+	b.payload = make([]byte, size)
+	_, err := r.ReadAt(b.payload, b.start)
+	return err == nil
+}
+
+// findBox returns the first box of type typ
+func findBox(boxes []box, typ string) (box, bool) {
+	for _, b := range boxes {
+		if b.typ == typ {
+			return b, true
+		}
+	}
+	return box{}, false
+}
+
+// readHEIF finds the "Exif" item of a HEIC/HEIF/AVIF file through the meta box
+func readHEIF(r io.ReaderAt, size int64) (*EXIF, error) {
+	meta, ok := findBox(readBoxes(r, 0, size), "meta")
+	if !ok {
+		return nil, ErrNoEXIF
+	}
+
+	// meta is a full box: skip version and flags
+	children := readBoxes(r, meta.start+4, meta.end)
+	iinf, okInfo := findBox(children, "iinf")
+	iloc, okLocation := findBox(children, "iloc")
+	if !okInfo || !okLocation || !iinf.load(r) || !iloc.load(r) {
+		return nil, ErrNoEXIF
+	}
+
+	itemID, ok := findExifItem(iinf.payload)
+	if !ok {
+		return nil, ErrNoEXIF
+	}
+	offset, length, ok := findItemLocation(iloc.payload, itemID)
+	if !ok || length < 4 || offset+length > size {
+		return nil, ErrNoEXIF
+	}
+
+	// The item starts with the offset of the TIFF header (after "Exif\0\0")
+	prefix := make([]byte, 4)
+	if _, err := r.ReadAt(prefix, offset); err != nil {
+		return nil, ErrNoEXIF
+	}
+	headerOffset := int64(binary.BigEndian.Uint32(prefix))
+	if 4+headerOffset >= length {
+		return nil, ErrNoEXIF
+	}
+
+	return parseTIFF(r, offset+4+headerOffset, length-4-headerOffset)
+}
+
+// beReader reads big-endian fields from a payload, remembering failures
+type beReader struct {
+	data []byte
+	pos  int
+	ok   bool
+}
+
+func (b *beReader) uint(size int) uint64 {
+	if !b.ok || size < 0 || b.pos+size > len(b.data) {
+		b.ok = false
+		return 0
+	}
+	var value uint64
+	for _, c := range b.data[b.pos : b.pos+size] {
+		value = value<<8 | uint64(c)
+	}
+	b.pos += size
+	return value
+}
+
+// findExifItem returns the ID of the item of type "Exif" in an iinf payload
+func findExifItem(iinf []byte) (uint32, bool) {
+	reader := &beReader{data: iinf, ok: true}
+	version := reader.uint(1)
+	reader.uint(3)
+	if version == 0 {
+		reader.uint(2)
+	} else {
+		reader.uint(4)
+	}
+	if !reader.ok {
+		return 0, false
+	}
+
+	for reader.pos+8 <= len(iinf) {
+		size := int(binary.BigEndian.Uint32(iinf[reader.pos:]))
+		typ := string(iinf[reader.pos+4 : reader.pos+8])
+		if size < 8 || reader.pos+size > len(iinf) {
+			return 0, false
+		}
+
+		if typ == "infe" {
+			entry := &beReader{data: iinf[reader.pos+8 : reader.pos+size], ok: true}
+			entryVersion := entry.uint(1)
+			entry.uint(3)
+			if entryVersion >= 2 {
+				var id uint64
+				if entryVersion == 2 {
+					id = entry.uint(2)
+				} else {
+					id = entry.uint(4)
+				}
+				entry.uint(2) // item_protection_index
+				itemType := entry.uint(4)
+				if entry.ok && itemType == 0x45786966 { // "Exif"
+					return uint32(id), true
+				}
+			}
+		}
+		reader.pos += size
+	}
+
+	return 0, false
+}
+
+// findItemLocation returns the file offset and length of an item from an iloc payload
+// Only items stored in the file itself (construction method 0) with one extent are supported
+func findItemLocation(iloc []byte, itemID uint32) (offset, length int64, ok bool) {
+	reader := &beReader{data: iloc, ok: true}
+	version := reader.uint(1)
+	reader.uint(3)
+	sizes := reader.uint(1)
+	offsetSize, lengthSize := int(sizes>>4), int(sizes&0x0F)
+	sizes = reader.uint(1)
+	baseOffsetSize, indexSize := int(sizes>>4), int(sizes&0x0F)
+	if version == 0 {
+		indexSize = 0
+	}
+
+	var itemCount uint64
+	if version < 2 {
+		itemCount = reader.uint(2)
+	} else {
+		itemCount = reader.uint(4)
+	}
+
+	for i := uint64(0); i < itemCount && reader.ok; i++ {
+		var id uint64
+		if version < 2 {
+			id = reader.uint(2)
+		} else {
+			id = reader.uint(4)
+		}
+		constructionMethod := uint64(0)
+		if version >= 1 {
+			constructionMethod = reader.uint(2) & 0x0F
+		}
+		reader.uint(2) // data_reference_index
+		baseOffset := reader.uint(baseOffsetSize)
+		extentCount := reader.uint(2)
+
+		for e := uint64(0); e < extentCount && reader.ok; e++ {
+			reader.uint(indexSize)
+			extentOffset := reader.uint(offsetSize)
+			extentLength := reader.uint(lengthSize)
+			if uint32(id) == itemID && e == 0 && constructionMethod == 0 && extentCount == 1 {
+				if !reader.ok {
+					return 0, 0, false
+				}
+				return int64(baseOffset + extentOffset), int64(extentLength), true
+			}
+		}
+	}
+
+	return 0, 0, false
+}
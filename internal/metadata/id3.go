@@ -0,0 +1,211 @@
+package metadata
+
+import (
+	"encoding/binary"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+// maxTagSize limits the tag data loaded into memory (tags with large cover art are skipped)
+const maxTagSize = 16 * 1024 * 1024
+
+// id3Frames maps ID3v2.2 and ID3v2.3/2.4 frame IDs to tag fields
+var id3Frames = map[string]string{
+	"TT2": "title", "TP1": "artist", "TAL": "album", "TRK": "track", "TPA": "disc", "TYE": "year",
+	"TIT2": "title", "TPE1": "artist", "TALB": "album", "TRCK": "track", "TPOS": "disc", "TYER": "year", "TDRC": "year",
+}
+
+// readID3v2 reads the text frames of an ID3v2.2, 2.3 or 2.4 tag at the start of the file
+func readID3v2(r io.ReaderAt, size int64) *Audio {
+	header := make([]byte, 10)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil
+	}
+	version, flags := header[3], header[5]
+	tagSize := int64(syncsafe(header[6:10]))
+	if version < 2 || version > 4 || tagSize > maxTagSize || 10+tagSize > size {
+		return nil
+	}
+
+	tag := make([]byte, tagSize)
+	if _, err := r.ReadAt(tag, 10); err != nil {
+		return nil
+	}
+	// Before 2.4, unsynchronisation applies to the whole tag
+	if flags&0x80 != 0 && version < 4 {
+		tag = unsynchronise(tag)
+	}
+
+	pos := 0
+	if flags&0x40 != 0 && version >= 3 {
+		if len(tag) < 4 {
+			return nil
+		}
+		if version == 3 {
+			pos = 4 + int(binary.BigEndian.Uint32(tag))
+		} else {
+			pos = int(syncsafe(tag[:4]))
+		}
+	}
+
+	idSize, headerSize := 4, 10
+	if version == 2 {
+		idSize, headerSize = 3, 6
+	}
+
+	audio := &Audio{}
+	for pos+headerSize <= len(tag) {
+		id := string(tag[pos : pos+idSize])
+		if id[0] == 0 {
+			// Padding
+			break
+		}
+
+		var frameSize int
+		var frameFlags uint16
+		switch version {
+		case 2:
+			frameSize = int(tag[pos+3])<<16 | int(tag[pos+4])<<8 | int(tag[pos+5])
+		case 3:
+			frameSize = int(binary.BigEndian.Uint32(tag[pos+4:]))
+			frameFlags = binary.BigEndian.Uint16(tag[pos+8:])
+		case 4:
+			frameSize = int(syncsafe(tag[pos+4 : pos+8]))
+			frameFlags = binary.BigEndian.Uint16(tag[pos+8:])
+		}
+		pos += headerSize
+		if frameSize < 0 || pos+frameSize > len(tag) {
+			break
+		}
+		data := tag[pos : pos+frameSize]
+		pos += frameSize
+
+		field, ok := id3Frames[id]
+		if !ok || audio.hasField(field) {
+			continue
+		}
+		data, ok = frameData(version, frameFlags, data)
+		if !ok {
+			continue
+		}
+		audio.setText(field, decodeID3Text(data))
+	}
+
+	return audio
+}
+
+// frameData removes frame level encodings; compressed and encrypted frames are not supported
+func frameData(version byte, flags uint16, data []byte) ([]byte, bool) {
+	switch version {
+	case 3:
+		if flags&0x00C0 != 0 {
+			return nil, false
+		}
+	case 4:
+		if flags&0x000C != 0 {
+			return nil, false
+		}
+		if flags&0x0002 != 0 {
+			data = unsynchronise(data)
+		}
+		if flags&0x0001 != 0 {
+			// Data length indicator
+			if len(data) < 4 {
+				return nil, false
+			}
+			data = data[4:]
+		}
+	}
+	return data, true
+}
+
+// decodeID3Text decodes a text frame (encoding byte followed by the text)
+// Only the first of several null separated values is returned
+func decodeID3Text(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+
+	encoding, text := data[0], data[1:]
+	switch encoding {
+	case 1, 2:
+		value := decodeUTF16(text, encoding == 2)
+		first, _, _ := strings.Cut(value, "\x00")
+		return first
+	case 3:
+		first, _, _ := strings.Cut(string(text), "\x00")
+		return first
+	default:
+		first, _, _ := strings.Cut(latin1(text), "\x00")
+		return first
+	}
+}
+
+// decodeUTF16 decodes UTF-16 text with a byte order mark (or big endian without one when bigEndian)
+func decodeUTF16(data []byte, bigEndian bool) string {
+	littleEndian := false
+	if len(data) >= 2 {
+		switch {
+		case data[0] == 0xFF && data[1] == 0xFE:
+			littleEndian = true
+			data = data[2:]
+		case data[0] == 0xFE && data[1] == 0xFF:
+			data = data[2:]
+		case !bigEndian:
+			littleEndian = true
+		}
+	}
+
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		if littleEndian {
+			units = append(units, binary.LittleEndian.Uint16(data[i:]))
+		} else {
+			units = append(units, binary.BigEndian.Uint16(data[i:]))
+		}
+	}
+	return string(utf16.Decode(units))
+}
+
+// syncsafe decodes a 28-bit integer stored in 4 bytes of 7 bits
+func syncsafe(data []byte) uint32 {
+	return uint32(data[0]&0x7F)<<21 | uint32(data[1]&0x7F)<<14 | uint32(data[2]&0x7F)<<7 | uint32(data[3]&0x7F)
+}
+
+// unsynchronise removes the zero bytes inserted after 0xFF
+func unsynchronise(data []byte) []byte {
+	result := make([]byte, 0, len(data))
+	for i := 0; i < len(data); i++ {
+		result = append(result, data[i])
+		if data[i] == 0xFF && i+1 < len(data) && data[i+1] == 0x00 {
+			i++
+		}
+	}
+	return result
+}
+
+// readID3v1 reads the 128-byte ID3v1 (or v1.1 with track number) tag at the end of the file
+func readID3v1(r io.ReaderAt, size int64) *Audio {
+	if size < 128 {
+		return nil
+	}
+	tag := make([]byte, 128)
+	if _, err := r.ReadAt(tag, size-128); err != nil || string(tag[:3]) != "TAG" {
+		return nil
+	}
+
+	text := func(data []byte) string {
+		return strings.TrimRight(latin1(data), "\x00 ")
+	}
+
+	audio := &Audio{}
+	audio.setText("title", text(tag[3:33]))
+	audio.setText("artist", text(tag[33:63]))
+	audio.setText("album", text(tag[63:93]))
+	audio.setText("year", text(tag[93:97]))
+	if tag[125] == 0 && tag[126] != 0 {
+		audio.Track = int(tag[126])
+	}
+	return audio
+}
@@ -0,0 +1,67 @@
+package metadata
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// mp4Fields maps iTunes metadata atoms to tag fields
+var mp4Fields = map[string]string{
+	"\xA9nam": "title",
+	"\xA9ART": "artist",
+	"\xA9alb": "album",
+	"\xA9day": "year",
+	"trkn":    "track",
+	"disk":    "disc",
+}
+
+// readMP4 reads the iTunes metadata list (moov/udta/meta/ilst) of an MP4/M4A file
+func readMP4(r io.ReaderAt, size int64) *Audio {
+	moov, ok := findBox(readBoxes(r, 0, size), "moov")
+	if !ok {
+		return nil
+	}
+	udta, ok := findBox(readBoxes(r, moov.start, moov.end), "udta")
+	if !ok {
+		return nil
+	}
+	meta, ok := findBox(readBoxes(r, udta.start, udta.end), "meta")
+	if !ok {
+		return nil
+	}
+	// meta is a full box: skip version and flags
+	ilst, ok := findBox(readBoxes(r, meta.start+4, meta.end), "ilst")
+	if !ok {
+		return nil
+	}
+
+	audio := &Audio{}
+	for _, item := range readBoxes(r, ilst.start, ilst.end) {
+		field, known := mp4Fields[item.typ]
+		if !known {
+			continue
+		}
+		data, ok := findBox(readBoxes(r, item.start, item.end), "data")
+		// data starts with the value type and locale (4 bytes each)
+		if !ok || data.end-data.start < 8 || !data.load(r) {
+			continue
+		}
+		value := data.payload[8:]
+
+		switch field {
+		case "track", "disc":
+			// Reserved, number, total (16 bits each)
+			if len(value) >= 4 {
+				number := int(binary.BigEndian.Uint16(value[2:]))
+				if field == "track" {
+					audio.Track = number
+				} else {
+					audio.Disc = number
+				}
+			}
+		default:
+			audio.setText(field, string(value))
+		}
+	}
+	return audio
+}
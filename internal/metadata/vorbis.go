@@ -0,0 +1,149 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+)
+
+// vorbisFields maps Vorbis comment names to tag fields
+var vorbisFields = map[string]string{
+	"TITLE":       "title",
+	"ARTIST":      "artist",
+	"ALBUM":       "album",
+	"TRACKNUMBER": "track",
+	"DISCNUMBER":  "disc",
+	"DATE":        "year",
+	"YEAR":        "year",
+}
+
+// flacVorbisComment is the metadata block type of Vorbis comments in FLAC
+const flacVorbisComment = 4
+
+// readFLAC finds the Vorbis comment block among the FLAC metadata blocks
+func readFLAC(r io.ReaderAt, size int64) *Audio {
+	header := make([]byte, 4)
+	for offset := int64(4); offset+4 <= size; {
+		if _, err := r.ReadAt(header, offset); err != nil {
+			return nil
+		}
+		last := header[0]&0x80 != 0
+		blockType := header[0] & 0x7F
+		length := int64(header[1])<<16 | int64(header[2])<<8 | int64(header[3])
+		offset += 4
+
+		if blockType == flacVorbisComment {
+			if length > maxTagSize || offset+length > size {
+				return nil
+			}
+			block := make([]byte, length)
+			if _, err := r.ReadAt(block, offset); err != nil {
+				return nil
+			}
+			return parseVorbisComment(block)
+		}
+		if last {
+			break
+		}
+		offset += length
+	}
+	return nil
+}
+
+// readOgg reads the comment header (the second packet) of the first Ogg Vorbis or Opus stream
+func readOgg(r io.ReaderAt, size int64) *Audio {
+	packets := make([][]byte, 0, 2)
+	var packet []byte
+	var serial uint32
+	header := make([]byte, 27)
+
+	for offset := int64(0); offset+27 <= size && len(packets) < 2; {
+		if _, err := r.ReadAt(header, offset); err != nil || string(header[:4]) != "OggS" {
+			return nil
+		}
+		pageSerial := binary.LittleEndian.Uint32(header[14:])
+		if offset == 0 {
+			serial = pageSerial
+		}
+
+		segments := make([]byte, header[26])
+		if _, err := r.ReadAt(segments, offset+27); err != nil {
+			return nil
+		}
+		offset += 27 + int64(len(segments))
+
+		for _, length := range segments {
+			if pageSerial == serial && len(packets) < 2 {
+				if int64(len(packet))+int64(length) > maxTagSize {
+					return nil
+				}
+				data := make([]byte, length)
+				if _, err := r.ReadAt(data, offset); err != nil {
+					return nil
+				}
+				packet = append(packet, data...)
+				// A segment shorter than 255 bytes ends the packet
+				if length < 255 {
+					packets = append(packets, packet)
+					packet = nil
+				}
+			}
+			offset += int64(length)
+		}
+	}
+	if len(packets) < 2 {
+		return nil
+	}
+
+	comment := packets[1]
+	switch {
+	case bytes.HasPrefix(comment, []byte("\x03vorbis")):
+		return parseVorbisComment(comment[7:])
+	case bytes.HasPrefix(comment, []byte("OpusTags")):
+		return parseVorbisComment(comment[8:])
+	}
+	return nil
+}
+
+// parseVorbisComment reads "NAME=value" comments after the vendor string (little endian lengths)
+func parseVorbisComment(data []byte) *Audio {
+	pos := 0
+	next := func() ([]byte, bool) {
+		if pos+4 > len(data) {
+			return nil, false
+		}
+		length := int(binary.LittleEndian.Uint32(data[pos:]))
+		pos += 4
+		if length < 0 || pos+length > len(data) {
+			return nil, false
+		}
+		value := data[pos : pos+length]
+		pos += length
+		return value, true
+	}
+
+	if _, ok := next(); !ok {
+		return nil
+	}
+	if pos+4 > len(data) {
+		return nil
+	}
+	count := int(binary.LittleEndian.Uint32(data[pos:]))
+	pos += 4
+
+	audio := &Audio{}
+	for i := 0; i < count; i++ {
+		comment, ok := next()
+		if !ok {
+			break
+		}
+		name, value, found := strings.Cut(string(comment), "=")
+		field, known := vorbisFields[strings.ToUpper(name)]
+		if !found || !known || audio.hasField(field) {
+			continue
+		}
+		audio.setText(field, value)
+	}
+	return audio
+}
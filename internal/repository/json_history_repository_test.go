@@ -122,3 +122,24 @@ func TestJSONHistoryRepository_Save_MaxHistoryLimit(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 100, loaded.Count())
 }
+
+func TestJSONHistoryRepository_SaveAndLoad_Pipeline(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	repo := NewJSONHistoryRepository(configPath)
+
+	entry := domain.HistoryEntry{
+		Rules: []domain.RuleConfig{
+			{Type: domain.RuleReplace, Enabled: true, Pattern: `\s+`, Replacement: "_", IsRegex: true},
+			{Type: domain.RuleNumbering, Enabled: false, Numbering: &domain.NumberingOptions{Start: 1, Step: 1, Padding: 4, Position: domain.NumberPrefix}},
+		},
+	}
+	history := domain.NewHistory()
+	history.Add(entry)
+
+	assert.NoError(t, repo.Save(history))
+
+	loaded, err := repo.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, loaded.Count())
+	assert.True(t, entry.Equal(loaded.GetAll()[0]))
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"rename/internal/domain"
+)
+
+// JSONJournalRepository implements JournalRepository using JSON file storage
+// Following SRP (Single Responsibility Principle) - only handles persistence
+type JSONJournalRepository struct {
+	journalPath string
+}
+
+// journalData is the JSON structure for persistence
+type journalData struct {
+	Batches []domain.RenameBatch `json:"batches"`
+}
+
+// NewJSONJournalRepository creates a new JSON-based undo journal repository
+func NewJSONJournalRepository(journalPath string) *JSONJournalRepository {
+	return &JSONJournalRepository{
+		journalPath: journalPath,
+	}
+}
+
+// Save persists the journal to JSON file
+func (r *JSONJournalRepository) Save(journal *domain.Journal) error {
+	// Create directory if it doesn't exist
+	dir := filepath.Dir(r.journalPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	data := journalData{
+		Batches: journal.GetAll(),
+	}
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(r.journalPath, jsonData, 0644)
+}
+
+// Load reads the journal from JSON file
+func (r *JSONJournalRepository) Load() (*domain.Journal, error) {
+	// Return empty journal if file doesn't exist
+	if _, err := os.Stat(r.journalPath); os.IsNotExist(err) {
+		return domain.NewJournal(), nil
+	}
+
+	jsonData, err := os.ReadFile(r.journalPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var data journalData
+	if err := json.Unmarshal(jsonData, &data); err != nil {
+		return nil, err
+	}
+
+	journal := domain.NewJournal()
+	if data.Batches != nil {
+		journal.SetBatches(data.Batches)
+	}
+
+	return journal, nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"rename/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONJournalRepository_SaveAndLoad(t *testing.T) {
+	journalPath := filepath.Join(t.TempDir(), "journal.json")
+	repo := NewJSONJournalRepository(journalPath)
+
+	modTime := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	journal := domain.NewJournal()
+	journal.Add(domain.RenameBatch{
+		ExecutedAt: modTime,
+		Operations: []domain.RenameOperation{
+			{OldPath: "/path/a.txt", NewPath: "/path/b.txt", Size: 42, ModTime: modTime},
+		},
+	})
+
+	err := repo.Save(journal)
+	assert.NoError(t, err)
+	assert.FileExists(t, journalPath)
+
+	loaded, err := repo.Load()
+	assert.NoError(t, err)
+
+	batches := loaded.GetAll()
+	assert.Equal(t, 1, len(batches))
+	op := batches[0].Operations[0]
+	assert.Equal(t, "/path/a.txt", op.OldPath)
+	assert.Equal(t, "/path/b.txt", op.NewPath)
+	assert.Equal(t, int64(42), op.Size)
+	assert.True(t, modTime.Equal(op.ModTime))
+}
+
+func TestJSONJournalRepository_Load_FileNotExists(t *testing.T) {
+	repo := NewJSONJournalRepository(filepath.Join(t.TempDir(), "nonexistent.json"))
+
+	loaded, err := repo.Load()
+	assert.NoError(t, err)
+	assert.NotNil(t, loaded)
+	assert.Equal(t, 0, loaded.Count())
+}
+
+func TestJSONJournalRepository_Save_CreatesDirectory(t *testing.T) {
+	journalPath := filepath.Join(t.TempDir(), "subdir", "journal.json")
+	repo := NewJSONJournalRepository(journalPath)
+
+	err := repo.Save(domain.NewJournal())
+	assert.NoError(t, err)
+	assert.FileExists(t, journalPath)
+}
@@ -0,0 +1,20 @@
+//go:build unix
+
+package service
+
+import (
+	"io/fs"
+
+	"golang.org/x/sys/unix"
+)
+
+// checkWritableDir checks that entries of dir can be added and removed (write and search permission)
+func checkWritableDir(dir string) error {
+	if err := unix.Access(dir, unix.W_OK|unix.X_OK); err != nil {
+		if err == unix.EACCES || err == unix.EROFS || err == unix.EPERM {
+			return &fs.PathError{Op: "access", Path: dir, Err: fs.ErrPermission}
+		}
+		return &fs.PathError{Op: "access", Path: dir, Err: err}
+	}
+	return nil
+}
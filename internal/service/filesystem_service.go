@@ -2,6 +2,12 @@ package service
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
+
+	"rename/internal/domain"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 // FileSystemService provides file system operations
@@ -19,7 +25,73 @@ func (fs *FileSystemService) RenameFile(oldPath, newPath string) error {
 }
 
 // FileExists checks if a file exists at the given path
+// Symbolic links are not followed, so a dangling link still takes its name
 func (fs *FileSystemService) FileExists(path string) bool {
-	_, err := os.Stat(path)
+	_, err := os.Lstat(path)
 	return err == nil
 }
+
+// SameFile reports whether both paths are spellings of the same existing directory entry
+// On case-insensitive or normalizing file systems (APFS, NTFS) "photo.JPG" and "photo.jpg" are the same file
+// Symbolic links and hard links are separate entries: renaming onto them goes through the conflict policy
+func (fs *FileSystemService) SameFile(path1, path2 string) bool {
+	if !strings.EqualFold(norm.NFC.String(path1), norm.NFC.String(path2)) {
+		return false
+	}
+	info1, err := os.Lstat(path1)
+	if err != nil {
+		return false
+	}
+	info2, err := os.Lstat(path2)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(info1, info2)
+}
+
+// CanRename checks that the folder containing path lets its entries be renamed
+// Returns an error wrapping fs.ErrPermission when it does not (see access_*.go)
+func (fs *FileSystemService) CanRename(path string) error {
+	return checkWritableDir(filepath.Dir(path))
+}
+
+// Stat returns the size and timestamps of the file at path
+// Change and birth times come from platform specific calls (see stat_*.go)
+func (fs *FileSystemService) Stat(path string) (domain.FileStat, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return domain.FileStat{}, err
+	}
+
+	changeTime, birthTime := fileTimes(path, info)
+	return domain.FileStat{
+		Size:       info.Size(),
+		ModTime:    info.ModTime(),
+		ChangeTime: changeTime,
+		BirthTime:  birthTime,
+		IsDir:      info.IsDir(),
+	}, nil
+}
+
+// ReadDir lists the entries of the directory at path, sorted by name
+// Symbolic links are reported as files so walks do not follow them into loops
+func (fs *FileSystemService) ReadDir(path string) ([]domain.DirEntry, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.DirEntry, len(entries))
+	for i, entry := range entries {
+		result[i] = domain.DirEntry{
+			Name:  entry.Name(),
+			IsDir: entry.IsDir(),
+		}
+	}
+	return result, nil
+}
+
+// ReadFile returns the content of the file at path
+func (fs *FileSystemService) ReadFile(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
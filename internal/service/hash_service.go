@@ -0,0 +1,67 @@
+package service
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"rename/internal/digest"
+	"rename/internal/domain"
+)
+
+// hashKey identifies a cached digest
+type hashKey struct {
+	path      string
+	algorithm domain.HashAlgorithm
+}
+
+// hashEntry is a cached digest, valid while the file keeps its size and modification time
+type hashEntry struct {
+	size    int64
+	modTime time.Time
+	digest  string
+}
+
+// HashService computes content digests of files
+// Following SRP (Single Responsibility Principle) - the algorithms live in the digest package
+type HashService struct {
+	mu    sync.Mutex
+	cache map[hashKey]hashEntry
+}
+
+// NewHashService creates a new HashService
+func NewHashService() *HashService {
+	return &HashService{
+		cache: make(map[hashKey]hashEntry),
+	}
+}
+
+// Hash returns the lowercase hex digest of the file at path
+// Results are reused while stat reports the same size and modification time,
+// so regenerating a preview does not read large files again
+func (s *HashService) Hash(path string, algorithm domain.HashAlgorithm, stat domain.FileStat) (string, error) {
+	key := hashKey{path: path, algorithm: algorithm}
+
+	s.mu.Lock()
+	entry, ok := s.cache[key]
+	s.mu.Unlock()
+	if ok && entry.size == stat.Size && entry.modTime.Equal(stat.ModTime) {
+		return entry.digest, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hex, err := digest.Hex(file, string(algorithm))
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	s.cache[key] = hashEntry{size: stat.Size, modTime: stat.ModTime, digest: hex}
+	s.mu.Unlock()
+	return hex, nil
+}
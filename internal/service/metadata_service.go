@@ -0,0 +1,128 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"rename/internal/domain"
+	"rename/internal/metadata"
+)
+
+// metadataEntry is a cached result, valid while the file keeps its size and modification time
+type metadataEntry struct {
+	size     int64
+	modTime  time.Time
+	metadata domain.Metadata
+}
+
+// MetadataService reads file metadata for template strategies
+// Following SRP (Single Responsibility Principle) - parsing lives in the metadata package
+type MetadataService struct {
+	mu    sync.Mutex
+	cache map[string]metadataEntry
+}
+
+// NewMetadataService creates a new MetadataService
+func NewMetadataService() *MetadataService {
+	return &MetadataService{
+		cache: make(map[string]metadataEntry),
+	}
+}
+
+// Metadata returns the EXIF and audio tag fields of the file at path
+// Results are reused while stat reports the same size and modification time
+// Files without EXIF or audio tags return empty metadata (not an error)
+func (s *MetadataService) Metadata(path string, stat domain.FileStat) (domain.Metadata, error) {
+	s.mu.Lock()
+	entry, ok := s.cache[path]
+	s.mu.Unlock()
+	if ok && entry.size == stat.Size && entry.modTime.Equal(stat.ModTime) {
+		return entry.metadata, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	fields := domain.Metadata{}
+	exif, err := metadata.ReadEXIF(file, stat.Size)
+	switch {
+	case errors.Is(err, metadata.ErrNoEXIF):
+	case err != nil:
+		return nil, err
+	default:
+		addEXIF(fields, exif)
+	}
+
+	audio, err := metadata.ReadAudio(file, stat.Size)
+	switch {
+	case errors.Is(err, metadata.ErrNoTags):
+	case err != nil:
+		return nil, err
+	default:
+		addAudio(fields, audio)
+	}
+
+	s.mu.Lock()
+	s.cache[path] = metadataEntry{size: stat.Size, modTime: stat.ModTime, metadata: fields}
+	s.mu.Unlock()
+	return fields, nil
+}
+
+// addAudio maps the audio tags that are present to metadata keys
+func addAudio(fields domain.Metadata, audio *metadata.Audio) {
+	for key, value := range map[string]string{
+		domain.FieldTitle:  audio.Title,
+		domain.FieldArtist: audio.Artist,
+		domain.FieldAlbum:  audio.Album,
+	} {
+		if value != "" {
+			fields[key] = value
+		}
+	}
+	for key, value := range map[string]int{
+		domain.FieldTrack: audio.Track,
+		domain.FieldDisc:  audio.Disc,
+		domain.FieldYear:  audio.Year,
+	} {
+		if value > 0 {
+			fields[key] = value
+		}
+	}
+}
+
+// addEXIF maps the EXIF fields that are present to metadata keys
+func addEXIF(fields domain.Metadata, exif *metadata.EXIF) {
+	if !exif.DateTimeOriginal.IsZero() {
+		fields[domain.FieldEXIFDate] = exif.DateTimeOriginal
+	}
+	for key, value := range map[string]string{
+		domain.FieldEXIFMake:  exif.Make,
+		domain.FieldEXIFModel: exif.Model,
+		domain.FieldEXIFLens:  exif.LensModel,
+	} {
+		if value != "" {
+			fields[key] = value
+		}
+	}
+	if exif.ISO > 0 {
+		fields[domain.FieldEXIFISO] = exif.ISO
+	}
+	if exif.FNumber > 0 {
+		fields[domain.FieldEXIFFNumber] = exif.FNumber
+	}
+	if exif.FocalLength > 0 {
+		fields[domain.FieldEXIFFocal] = exif.FocalLength
+	}
+	if exif.GPS != nil {
+		fields[domain.FieldEXIFLatitude] = exif.GPS.Latitude
+		fields[domain.FieldEXIFLongitude] = exif.GPS.Longitude
+		if exif.GPS.HasAltitude {
+			fields[domain.FieldEXIFAltitude] = exif.GPS.Altitude
+		}
+	}
+}
@@ -0,0 +1,18 @@
+//go:build darwin
+
+package service
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// fileTimes returns the change and birth time of path (APFS and HFS+ record both)
+func fileTimes(path string, info os.FileInfo) (changeTime, birthTime time.Time) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, time.Time{}
+	}
+	return time.Unix(stat.Ctimespec.Unix()), time.Unix(stat.Birthtimespec.Unix())
+}
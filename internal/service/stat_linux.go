@@ -0,0 +1,36 @@
+//go:build linux
+
+package service
+
+import (
+	"os"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// fileTimes returns the change and birth time of path
+// Birth time needs statx (Linux 4.11+) and a file system that records it (ext4, btrfs, xfs)
+func fileTimes(path string, info os.FileInfo) (changeTime, birthTime time.Time) {
+	var stat unix.Statx_t
+	if err := unix.Statx(unix.AT_FDCWD, path, 0, unix.STATX_CTIME|unix.STATX_BTIME, &stat); err != nil {
+		return fallbackChangeTime(info), time.Time{}
+	}
+
+	if stat.Mask&unix.STATX_CTIME != 0 {
+		changeTime = time.Unix(stat.Ctime.Sec, int64(stat.Ctime.Nsec))
+	}
+	if stat.Mask&unix.STATX_BTIME != 0 {
+		birthTime = time.Unix(stat.Btime.Sec, int64(stat.Btime.Nsec))
+	}
+	return changeTime, birthTime
+}
+
+// fallbackChangeTime reads the change time from the stat result when statx is unavailable
+func fallbackChangeTime(info os.FileInfo) time.Time {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return time.Unix(stat.Ctim.Unix())
+	}
+	return time.Time{}
+}
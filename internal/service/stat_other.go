@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !windows
+
+package service
+
+import (
+	"os"
+	"time"
+)
+
+// fileTimes is not supported on this platform: only the modification time is available
+func fileTimes(path string, info os.FileInfo) (changeTime, birthTime time.Time) {
+	return time.Time{}, time.Time{}
+}
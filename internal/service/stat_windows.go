@@ -0,0 +1,18 @@
+//go:build windows
+
+package service
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// fileTimes returns the creation time of path; Windows has no change time
+func fileTimes(path string, info os.FileInfo) (changeTime, birthTime time.Time) {
+	data, ok := info.Sys().(*syscall.Win32FileAttributeData)
+	if !ok {
+		return time.Time{}, time.Time{}
+	}
+	return time.Time{}, time.Unix(0, data.CreationTime.Nanoseconds())
+}
@@ -0,0 +1,42 @@
+//go:build darwin && cgo
+
+package service
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework Foundation
+#import <Foundation/Foundation.h>
+#include <stdlib.h>
+
+// trashItem moves path to the trash of its volume and returns NULL, or an error message the caller frees
+static char *trashItem(const char *path) {
+	@autoreleasepool {
+		NSURL *url = [NSURL fileURLWithPath:[NSString stringWithUTF8String:path]];
+		NSError *error = nil;
+		if ([[NSFileManager defaultManager] trashItemAtURL:url resultingItemURL:nil error:&error]) {
+			return NULL;
+		}
+		const char *message = error.localizedDescription.UTF8String;
+		return strdup(message != NULL ? message : "cannot move to the trash");
+	}
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"unsafe"
+)
+
+// moveToTrash moves path to the trash through NSFileManager,
+// which picks the trash of the file's volume and records it for "Put Back"
+func moveToTrash(path string) error {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	if message := C.trashItem(cPath); message != nil {
+		defer C.free(unsafe.Pointer(message))
+		return errors.New(C.GoString(message))
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+//go:build darwin && !cgo
+
+package service
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// trashScript asks Finder to delete the file given as the first argument,
+// so the path never has to be quoted inside the script
+var trashScript = []string{
+	"-e", "on run argv",
+	"-e", `tell application "Finder" to delete (POSIX file (item 1 of argv) as alias)`,
+	"-e", "end run",
+}
+
+// moveToTrash moves path to the trash through Finder, which keeps "Put Back" working
+func moveToTrash(path string) error {
+	args := append(append([]string{}, trashScript...), path)
+	output, err := exec.Command("osascript", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
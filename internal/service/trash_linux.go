@@ -0,0 +1,110 @@
+//go:build linux
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// maxTrashNames limits the search for a free name in the trash
+const maxTrashNames = 1000
+
+// moveToTrash follows the freedesktop.org trash specification so file managers can restore the file
+// Files are moved to the home trash, or to $topdir/.Trash-$uid when they live on another file system
+func moveToTrash(path string) error {
+	err := trashInto(homeTrash(), path, path)
+	if !errors.Is(err, unix.EXDEV) {
+		return err
+	}
+
+	top, err := mountPoint(path)
+	if err != nil {
+		return err
+	}
+	relative, err := filepath.Rel(top, path)
+	if err != nil {
+		return err
+	}
+	return trashInto(filepath.Join(top, fmt.Sprintf(".Trash-%d", os.Getuid())), path, relative)
+}
+
+// homeTrash returns $XDG_DATA_HOME/Trash (~/.local/share/Trash by default)
+func homeTrash() string {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		homeDir, _ := os.UserHomeDir()
+		dataHome = filepath.Join(homeDir, ".local", "share")
+	}
+	return filepath.Join(dataHome, "Trash")
+}
+
+// trashInto moves path into the trash folder dir, recording originalPath in a .trashinfo file
+func trashInto(dir, path, originalPath string) error {
+	filesDir := filepath.Join(dir, "files")
+	infoDir := filepath.Join(dir, "info")
+	if err := os.MkdirAll(filesDir, 0o700); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(infoDir, 0o700); err != nil {
+		return err
+	}
+
+	info := fmt.Sprintf("[Trash Info]\nPath=%s\nDeletionDate=%s\n",
+		(&url.URL{Path: originalPath}).EscapedPath(), time.Now().Format("2006-01-02T15:04:05"))
+
+	for n := 0; n < maxTrashNames; n++ {
+		name := trashName(filepath.Base(path), n)
+		target := filepath.Join(filesDir, name)
+		if _, err := os.Lstat(target); err == nil {
+			continue
+		}
+
+		// The info file is created exclusively to reserve the name
+		infoPath := filepath.Join(infoDir, name+".trashinfo")
+		infoFile, err := os.OpenFile(infoPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		_, err = infoFile.WriteString(info)
+		if closeErr := infoFile.Close(); err == nil {
+			err = closeErr
+		}
+		if err == nil {
+			err = os.Rename(path, target)
+		}
+		if err != nil {
+			os.Remove(infoPath)
+		}
+		return err
+	}
+
+	return fmt.Errorf("no free name in %s", dir)
+}
+
+// mountPoint returns the top directory of the file system holding path
+func mountPoint(path string) (string, error) {
+	var stat unix.Stat_t
+	if err := unix.Lstat(path, &stat); err != nil {
+		return "", err
+	}
+
+	dir := path
+	for {
+		parent := filepath.Dir(dir)
+		var parentStat unix.Stat_t
+		if parent == dir || unix.Stat(parent, &parentStat) != nil || parentStat.Dev != stat.Dev {
+			return dir, nil
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !windows
+
+package service
+
+import "errors"
+
+// moveToTrash is not supported on this platform
+func moveToTrash(path string) error {
+	return errors.New("the trash is not supported on this platform")
+}
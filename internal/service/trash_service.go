@@ -0,0 +1,34 @@
+package service
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// TrashService moves files to the trash of the desktop environment
+// Following SRP (Single Responsibility Principle) - each platform implements moveToTrash
+type TrashService struct{}
+
+// NewTrashService creates a new TrashService
+func NewTrashService() *TrashService {
+	return &TrashService{}
+}
+
+// Trash moves the file or folder at path to the trash, where the user can restore it
+func (t *TrashService) Trash(path string) error {
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	return moveToTrash(absolute)
+}
+
+// trashName returns the n-th candidate name for base in a trash folder: base, "stem (2).ext", ...
+func trashName(base string, n int) string {
+	if n == 0 {
+		return base
+	}
+	ext := filepath.Ext(base)
+	return fmt.Sprintf("%s (%d)%s", strings.TrimSuffix(base, ext), n+1, ext)
+}
@@ -0,0 +1,57 @@
+//go:build windows
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+var procSHFileOperation = syscall.NewLazyDLL("shell32.dll").NewProc("SHFileOperationW")
+
+const (
+	foDelete          = 0x0003
+	fofSilent         = 0x0004
+	fofNoConfirmation = 0x0010
+	fofAllowUndo      = 0x0040
+	fofNoErrorUI      = 0x0400
+	fofNoConfirmMkdir = 0x0200
+)
+
+// shFileOpStruct is SHFILEOPSTRUCTW
+type shFileOpStruct struct {
+	hwnd                  uintptr
+	wFunc                 uint32
+	pFrom                 *uint16
+	pTo                   *uint16
+	fFlags                uint16
+	fAnyOperationsAborted int32
+	hNameMappings         uintptr
+	lpszProgressTitle     *uint16
+}
+
+// moveToTrash moves path to the Recycle Bin through the shell, without dialogs
+func moveToTrash(path string) error {
+	from, err := syscall.UTF16FromString(path)
+	if err != nil {
+		return err
+	}
+	// pFrom is a list of paths terminated by an extra NUL
+	from = append(from, 0)
+
+	op := shFileOpStruct{
+		wFunc:  foDelete,
+		pFrom:  &from[0],
+		fFlags: fofAllowUndo | fofNoConfirmation | fofSilent | fofNoErrorUI | fofNoConfirmMkdir,
+	}
+	code, _, _ := procSHFileOperation.Call(uintptr(unsafe.Pointer(&op)))
+	if code != 0 {
+		return fmt.Errorf("SHFileOperation failed with code 0x%X", code)
+	}
+	if op.fAnyOperationsAborted != 0 {
+		return errors.New("the operation was cancelled")
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"rename/internal/domain"
+)
+
+// resolveBatchCollisions finds files of the batch that map to the same new name and applies the
+// conflict policy to them, so the batch lands the same way whatever order the moves run in
+// The first file in batch order keeps the name; under suffix the others get the first free
+// suffixed names (all of them when the name is also taken outside the batch), under skip the
+// others keep their names. Fail aborts in Execute, overwrite lets the last file win and prompt asks
+// Returns the original paths of all files sharing a name, keyed by each of their paths
+func (uc *RenameUseCase) resolveBatchCollisions(files []*domain.File) map[string][]string {
+	sources := uc.batchSources(files)
+	groups := uc.collisionGroups(files)
+
+	collisions := make(map[string][]string)
+	if len(groups) == 0 {
+		return collisions
+	}
+
+	// Names claimed by the batch, so a suffixed name never lands on another target
+	claimed := make(map[string]bool, len(files))
+	for _, file := range files {
+		if file.HasChanged() && !uc.trashes(file) {
+			claimed[uc.targetKey(file.Directory(), file.NewName())] = true
+		}
+	}
+
+	for _, group := range groups {
+		participants := make([]string, len(group))
+		for i, file := range group {
+			participants[i] = file.OriginalPath()
+		}
+		for _, file := range group {
+			collisions[file.OriginalPath()] = participants
+		}
+
+		switch uc.conflictPolicy {
+		case domain.ConflictSuffix:
+			losers := group[1:]
+			if uc.targetTaken(group[0], sources) {
+				losers = group
+			}
+			for _, file := range losers {
+				if name, err := uc.findBatchName(file, sources, claimed); err == nil {
+					file.SetNewName(name)
+				}
+			}
+		case domain.ConflictSkip:
+			for _, file := range group[1:] {
+				file.SetNewName(file.OriginalName())
+			}
+		}
+	}
+
+	return collisions
+}
+
+// collisionGroups returns the files sharing a new name (see targetKey), in batch order
+// Unchanged and trashed files are not renamed and take part in no group
+func (uc *RenameUseCase) collisionGroups(files []*domain.File) [][]*domain.File {
+	byTarget := make(map[string][]*domain.File)
+	keys := make([]string, 0)
+	for _, file := range files {
+		if !file.HasChanged() || uc.trashes(file) {
+			continue
+		}
+		key := uc.targetKey(file.Directory(), file.NewName())
+		if _, ok := byTarget[key]; !ok {
+			keys = append(keys, key)
+		}
+		byTarget[key] = append(byTarget[key], file)
+	}
+
+	groups := make([][]*domain.File, 0)
+	for _, key := range keys {
+		if len(byTarget[key]) > 1 {
+			groups = append(groups, byTarget[key])
+		}
+	}
+	return groups
+}
+
+// findBatchName finds a suffixed name for file that is neither claimed by the batch nor taken
+// by a file outside it, and claims it
+func (uc *RenameUseCase) findBatchName(file *domain.File, sources, claimed map[string]bool) (string, error) {
+	start := uc.suffix.Start()
+	for i := start; i < start+maxRetries; i++ {
+		candidate := uc.suffix.Apply(file.NewName(), i)
+		if file.IsDir() {
+			candidate = uc.suffix.ApplyToDirectory(file.NewName(), i)
+		}
+
+		key := uc.targetKey(file.Directory(), candidate)
+		path := filepath.Join(file.Directory(), candidate)
+		if claimed[key] || (!sources[path] && uc.fileSystem.FileExists(path)) {
+			continue
+		}
+		claimed[key] = true
+		return candidate, nil
+	}
+
+	return "", fmt.Errorf("Failed to find available name for %s after %d retries", file.OriginalName(), maxRetries)
+}
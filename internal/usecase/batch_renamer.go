@@ -0,0 +1,270 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// renameMove is a single source → target rename within a batch
+type renameMove struct {
+	source string
+	target string
+}
+
+// moveOutcome is the result of a single move
+// path is the final location of the file (the source when the move failed)
+// source is where the move started, which differs from the planned source when a parent folder moved first
+type moveOutcome struct {
+	source string
+	path   string
+	err    error
+}
+
+// errNotAttempted marks a move left out because the batch was stopped (see batchRenamer.stop)
+var errNotAttempted = errors.New("not attempted")
+
+// conflictResolver is called when the target of a move already exists
+// It returns the path to use instead, or an error to fail the move
+type conflictResolver func(index int, target string) (string, error)
+
+// batchRenamer performs a batch of renames as a whole
+// Moves whose target is the source of another move (chains like file1→file2, file2→file3
+// and cycles like a→b, b→a) are routed through temporary names, so the batch lands
+// exactly as planned instead of colliding with files that are about to be vacated
+type batchRenamer struct {
+	fileSystem FileSystemService
+	resolve    conflictResolver
+	// stop is checked before each move; once it returns true the remaining moves are not attempted
+	stop func() bool
+	// progress is called after each attempted move with its index
+	progress func(index int)
+	// completed holds indexes of successful moves in execution order
+	completed []int
+	// failed is set by the first move that fails (skipped moves are not failures)
+	failed bool
+}
+
+// newBatchRenamer creates a batchRenamer using resolve for conflicts with existing files
+func newBatchRenamer(fileSystem FileSystemService, resolve conflictResolver) *batchRenamer {
+	return &batchRenamer{
+		fileSystem: fileSystem,
+		resolve:    resolve,
+	}
+}
+
+// run executes all moves and returns one outcome per move
+// Moves run level by level starting with the shallowest sources; once a directory has moved,
+// the sources and targets below it are re-mapped, so a folder and its contents can be
+// renamed in the same batch
+func (r *batchRenamer) run(moves []renameMove) []moveOutcome {
+	outcomes := make([]moveOutcome, len(moves))
+	r.completed = make([]int, 0, len(moves))
+
+	pending := slices.Clone(moves)
+	for _, level := range levelsByDepth(pending) {
+		r.runLevel(pending, level, outcomes)
+
+		for _, i := range level {
+			if outcomes[i].path != pending[i].source {
+				remapBelow(pending, pending[i].source, outcomes[i].path)
+			}
+		}
+	}
+
+	return outcomes
+}
+
+// currentPath returns where path is after the completed moves of the last run
+// Used for entries inside renamed directories that were not moved themselves
+func (r *batchRenamer) currentPath(path string, outcomes []moveOutcome) string {
+	for _, i := range r.completed {
+		if strings.HasPrefix(path, outcomes[i].source+string(filepath.Separator)) {
+			path = outcomes[i].path + path[len(outcomes[i].source):]
+		}
+	}
+	return path
+}
+
+// stopped reports whether the remaining moves must be left out
+func (r *batchRenamer) stopped() bool {
+	return r.stop != nil && r.stop()
+}
+
+// record stores the outcome of move i, notes failures and reports progress
+func (r *batchRenamer) record(outcomes []moveOutcome, i int, outcome moveOutcome) {
+	outcomes[i] = outcome
+	if errors.Is(outcome.err, errNotAttempted) {
+		return
+	}
+	if outcome.err != nil && !errors.Is(outcome.err, errSkipped) {
+		r.failed = true
+	}
+	if r.progress != nil {
+		r.progress(i)
+	}
+}
+
+// runLevel executes the moves at indexes (all sources at the same depth)
+func (r *batchRenamer) runLevel(moves []renameMove, indexes []int, outcomes []moveOutcome) {
+	// Sources that will be vacated during this level
+	sources := make(map[string]bool, len(indexes))
+	for _, i := range indexes {
+		sources[moves[i].source] = true
+	}
+
+	// Phase 1: move files whose target is still occupied by another source to temporary names
+	tempPaths := make(map[int]string)
+	deferred := make([]int, 0)
+	for _, i := range indexes {
+		move := moves[i]
+		outcomes[i] = moveOutcome{source: move.source, path: move.source}
+		if move.target == move.source || !sources[move.target] {
+			continue
+		}
+		if r.stopped() {
+			outcomes[i].err = errNotAttempted
+			tempPaths[i] = ""
+			continue
+		}
+
+		tempPath, err := r.tempPath(move.source)
+		if err == nil {
+			err = r.fileSystem.RenameFile(move.source, tempPath)
+		}
+		if err != nil {
+			r.record(outcomes, i, moveOutcome{source: move.source, path: move.source, err: fmt.Errorf("Failed to rename %s: %v", filepath.Base(move.source), err)})
+			tempPaths[i] = ""
+			continue
+		}
+
+		tempPaths[i] = tempPath
+		deferred = append(deferred, i)
+	}
+
+	// Phase 2: moves into free targets
+	for _, i := range indexes {
+		if _, isDeferred := tempPaths[i]; isDeferred {
+			continue
+		}
+		if r.stopped() {
+			outcomes[i].err = errNotAttempted
+			continue
+		}
+		r.record(outcomes, i, r.finish(i, moves[i], moves[i].source))
+	}
+
+	// Phase 3: move temporaries into the now vacated targets
+	for _, i := range deferred {
+		move := moves[i]
+		outcome := moveOutcome{source: move.source, err: errNotAttempted}
+		if !r.stopped() {
+			outcome = r.finish(i, move, tempPaths[i])
+		}
+		if outcome.err != nil {
+			// Put the file back under its original name unless another file took it
+			outcome.path = tempPaths[i]
+			if !r.fileSystem.FileExists(move.source) && r.fileSystem.RenameFile(tempPaths[i], move.source) == nil {
+				outcome.path = move.source
+			}
+		}
+		r.record(outcomes, i, outcome)
+	}
+}
+
+// levelsByDepth groups move indexes by the depth of their source, shallowest first
+// Indexes keep their order within a level
+func levelsByDepth(moves []renameMove) [][]int {
+	byDepth := make(map[int][]int)
+	depths := make([]int, 0)
+	for i, move := range moves {
+		depth := strings.Count(filepath.Clean(move.source), string(filepath.Separator))
+		if _, ok := byDepth[depth]; !ok {
+			depths = append(depths, depth)
+		}
+		byDepth[depth] = append(byDepth[depth], i)
+	}
+	slices.Sort(depths)
+
+	levels := make([][]int, len(depths))
+	for i, depth := range depths {
+		levels[i] = byDepth[depth]
+	}
+	return levels
+}
+
+// remapBelow rewrites the sources and targets inside directory oldDir to be inside newDir
+func remapBelow(moves []renameMove, oldDir, newDir string) {
+	prefix := oldDir + string(filepath.Separator)
+	for i := range moves {
+		if strings.HasPrefix(moves[i].source, prefix) {
+			moves[i].source = newDir + moves[i].source[len(oldDir):]
+		}
+		if strings.HasPrefix(moves[i].target, prefix) {
+			moves[i].target = newDir + moves[i].target[len(oldDir):]
+		}
+	}
+}
+
+// finish renames from (the source or its temporary name) to the move target, resolving conflicts
+func (r *batchRenamer) finish(index int, move renameMove, from string) moveOutcome {
+	target := move.target
+	if target != from && r.fileSystem.FileExists(target) {
+		if r.fileSystem.SameFile(from, target) {
+			return r.renameSameFile(index, move, from)
+		}
+
+		resolved, err := r.resolve(index, target)
+		if err != nil {
+			return moveOutcome{source: move.source, path: move.source, err: err}
+		}
+		target = resolved
+	}
+
+	if err := r.fileSystem.RenameFile(from, target); err != nil {
+		return moveOutcome{source: move.source, path: move.source, err: fmt.Errorf("Failed to rename %s: %v", filepath.Base(move.source), err)}
+	}
+
+	r.completed = append(r.completed, index)
+	return moveOutcome{source: move.source, path: target}
+}
+
+// renameSameFile changes only the case (or Unicode normalization) of a name on a file system
+// that treats both spellings as the same file, going through a temporary name so the
+// file system cannot keep the old spelling or report the file as its own conflict
+func (r *batchRenamer) renameSameFile(index int, move renameMove, from string) moveOutcome {
+	tempPath, err := r.tempPath(from)
+	if err == nil {
+		err = r.fileSystem.RenameFile(from, tempPath)
+	}
+	if err != nil {
+		return moveOutcome{source: move.source, path: move.source, err: fmt.Errorf("Failed to rename %s: %v", filepath.Base(move.source), err)}
+	}
+
+	if err := r.fileSystem.RenameFile(tempPath, move.target); err != nil {
+		path := tempPath
+		if r.fileSystem.RenameFile(tempPath, from) == nil {
+			path = from
+		}
+		return moveOutcome{source: move.source, path: path, err: fmt.Errorf("Failed to rename %s: %v", filepath.Base(move.source), err)}
+	}
+
+	r.completed = append(r.completed, index)
+	return moveOutcome{source: move.source, path: move.target}
+}
+
+// tempPath returns an unused temporary name next to path
+func (r *batchRenamer) tempPath(path string) (string, error) {
+	dir := filepath.Dir(path)
+	name := filepath.Base(path)
+
+	for i := 0; i < maxRetries; i++ {
+		candidate := filepath.Join(dir, fmt.Sprintf(".%s.rename-tmp-%d", name, i))
+		if !r.fileSystem.FileExists(candidate) {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("no temporary name available after %d retries", maxRetries)
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"rename/internal/domain"
+)
+
+// TrashService moves files to the system trash (recoverable, unlike deleting them)
+type TrashService interface {
+	Trash(path string) error
+}
+
+// SetDuplicateOptions sets how files with the same contents are detected and handled
+// Detection needs a content hasher; without one no file is flagged
+func (uc *RenameUseCase) SetDuplicateOptions(options domain.DuplicateOptions) error {
+	if err := options.Validate(); err != nil {
+		return err
+	}
+	uc.duplicates = options
+	return nil
+}
+
+// SetTrash sets the trash used for duplicates under the trash action
+// Without a trash, those duplicates are reported as failures
+func (uc *RenameUseCase) SetTrash(trash TrashService) {
+	uc.trash = trash
+}
+
+// markDuplicates flags files whose contents match an earlier file of the batch and applies the duplicate action
+// Only files of equal size are hashed; directories and empty files are never duplicates
+// Returns the duplicates of each file that is kept, keyed by its path
+func (uc *RenameUseCase) markDuplicates(files []*domain.File) map[string][]string {
+	for _, file := range files {
+		file.SetDuplicateOf("")
+	}
+	if !uc.duplicates.Detect || uc.hasher == nil {
+		return nil
+	}
+
+	sizes := make(map[*domain.File]int64, len(files))
+	counts := make(map[int64]int)
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		if stat, err := uc.stat(file.OriginalPath()); err == nil && stat.Size > 0 {
+			sizes[file] = stat.Size
+			counts[stat.Size]++
+		}
+	}
+
+	candidates := make([]*domain.File, 0)
+	for _, file := range files {
+		if size, ok := sizes[file]; ok && counts[size] > 1 {
+			candidates = append(candidates, file)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	field := domain.HashField(uc.duplicates.Algorithm)
+	metadata := uc.loadMetadata(candidates, metadataPlan{hashes: []domain.HashAlgorithm{uc.duplicates.Algorithm}})
+
+	// The first file (in batch order) with a given size and digest is the one kept
+	type contentKey struct {
+		size   int64
+		digest string
+	}
+	kept := make(map[contentKey]*domain.File)
+	groups := make(map[string][]string)
+	for i, file := range candidates {
+		digest, ok := metadata[i][field].(string)
+		if !ok {
+			continue
+		}
+		key := contentKey{size: sizes[file], digest: digest}
+
+		original, found := kept[key]
+		if !found {
+			kept[key] = file
+			continue
+		}
+
+		file.SetDuplicateOf(original.OriginalPath())
+		groups[original.OriginalPath()] = append(groups[original.OriginalPath()], file.OriginalPath())
+		// Shared names are given by shareDuplicateNames once the name of the original is final
+		switch uc.duplicates.Action {
+		case domain.DuplicateSkip, domain.DuplicateTrash, domain.DuplicateShared:
+			file.SetNewName(file.OriginalName())
+		}
+	}
+
+	return groups
+}
+
+// shareDuplicateNames names duplicates under the shared action after the file they duplicate
+// It runs after resolveBatchCollisions, so the names follow the final name of the original and
+// skip suffixes claimed by the batch or taken by files outside it
+func (uc *RenameUseCase) shareDuplicateNames(files []*domain.File) {
+	if uc.duplicates.Action != domain.DuplicateShared {
+		return
+	}
+
+	byPath := make(map[string]*domain.File, len(files))
+	sources := uc.batchSources(files)
+	claimed := make(map[string]bool, len(files))
+	for _, file := range files {
+		byPath[file.OriginalPath()] = file
+		if file.DuplicateOf() != "" {
+			sources[file.OriginalPath()] = true
+		} else if file.HasChanged() {
+			claimed[uc.targetKey(file.Directory(), file.NewName())] = true
+		}
+	}
+
+	// Counters continue per original, so its duplicates are numbered in batch order
+	next := make(map[*domain.File]int)
+	for _, file := range files {
+		original := byPath[file.DuplicateOf()]
+		if original == nil {
+			continue
+		}
+		start, ok := next[original]
+		if !ok {
+			start = uc.suffix.Start()
+		}
+		for counter := start; counter < start+maxRetries; counter++ {
+			candidate := uc.suffix.Apply(original.NewName(), counter)
+			key := uc.targetKey(file.Directory(), candidate)
+			path := filepath.Join(file.Directory(), candidate)
+			if claimed[key] || (!sources[path] && uc.fileSystem.FileExists(path)) {
+				continue
+			}
+			claimed[key] = true
+			file.SetNewName(candidate)
+			next[original] = counter + 1
+			break
+		}
+	}
+}
+
+// trashes reports whether file will be moved to the trash instead of renamed
+func (uc *RenameUseCase) trashes(file *domain.File) bool {
+	return uc.duplicates.Action == domain.DuplicateTrash && file.DuplicateOf() != ""
+}
+
+// trashFile moves a duplicate to the trash
+func (uc *RenameUseCase) trashFile(file *domain.File) error {
+	if uc.trash == nil {
+		return fmt.Errorf("Failed to move %s to the trash: no trash available", file.OriginalName())
+	}
+	if err := uc.trash.Trash(file.OriginalPath()); err != nil {
+		return fmt.Errorf("Failed to move %s to the trash: %v", file.OriginalName(), err)
+	}
+	return nil
+}
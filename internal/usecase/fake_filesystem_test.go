@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"rename/internal/domain"
+)
+
+// fakeFileSystem is an in-memory FileSystemService keyed by path
+// Contents let tests verify which file ended up where after a batch
+// Like os.Rename, renaming onto an existing path replaces it
+// Directories are implied by the file paths and renaming one moves everything below it
+type fakeFileSystem struct {
+	files map[string]string
+	// caseInsensitive makes paths differing only in case refer to the same file (like APFS)
+	// A direct rename between two such spellings keeps the old spelling, as some volumes do
+	caseInsensitive bool
+	// readOnly holds folders whose entries cannot be renamed
+	readOnly map[string]bool
+	// failFrom holds paths that cannot be renamed (a lock only noticed by the rename itself)
+	failFrom map[string]bool
+	// unreadable holds folders and files that cannot be read (no permission)
+	unreadable map[string]bool
+}
+
+func newFakeFileSystem(files map[string]string) *fakeFileSystem {
+	fs := &fakeFileSystem{files: make(map[string]string), readOnly: make(map[string]bool), failFrom: make(map[string]bool), unreadable: make(map[string]bool)}
+	for path, content := range files {
+		fs.files[path] = content
+	}
+	return fs
+}
+
+func newCaseInsensitiveFileSystem(files map[string]string) *fakeFileSystem {
+	fs := newFakeFileSystem(files)
+	fs.caseInsensitive = true
+	return fs
+}
+
+// lookup returns the stored spelling of path
+func (fs *fakeFileSystem) lookup(path string) (string, bool) {
+	if _, ok := fs.files[path]; ok {
+		return path, true
+	}
+	if fs.caseInsensitive {
+		for stored := range fs.files {
+			if strings.EqualFold(stored, path) {
+				return stored, true
+			}
+		}
+	}
+	return "", false
+}
+
+// isDir reports whether files exist below path (directories are matched case-sensitively)
+func (fs *fakeFileSystem) isDir(path string) bool {
+	for stored := range fs.files {
+		if strings.HasPrefix(stored, path+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (fs *fakeFileSystem) RenameFile(oldPath, newPath string) error {
+	if err := fs.CanRename(oldPath); err != nil {
+		return err
+	}
+	if fs.failFrom[oldPath] {
+		return os.ErrPermission
+	}
+	if fs.isDir(oldPath) {
+		if fs.isDir(newPath) {
+			return os.ErrExist
+		}
+		moved := make(map[string]string)
+		for stored, content := range fs.files {
+			if strings.HasPrefix(stored, oldPath+"/") {
+				moved[newPath+strings.TrimPrefix(stored, oldPath)] = content
+				delete(fs.files, stored)
+			}
+		}
+		for path, content := range moved {
+			fs.files[path] = content
+		}
+		return nil
+	}
+
+	source, ok := fs.lookup(oldPath)
+	if !ok {
+		return os.ErrNotExist
+	}
+	if target, exists := fs.lookup(newPath); exists {
+		if target == source {
+			return nil
+		}
+		delete(fs.files, target)
+	}
+	content := fs.files[source]
+	delete(fs.files, source)
+	fs.files[newPath] = content
+	return nil
+}
+
+func (fs *fakeFileSystem) FileExists(path string) bool {
+	_, ok := fs.lookup(path)
+	return ok || fs.isDir(path)
+}
+
+func (fs *fakeFileSystem) SameFile(path1, path2 string) bool {
+	stored1, ok1 := fs.lookup(path1)
+	stored2, ok2 := fs.lookup(path2)
+	return ok1 && ok2 && stored1 == stored2
+}
+
+func (fs *fakeFileSystem) CanRename(path string) error {
+	if fs.readOnly[filepath.Dir(path)] {
+		return os.ErrPermission
+	}
+	return nil
+}
+
+func (fs *fakeFileSystem) Stat(path string) (domain.FileStat, error) {
+	if fs.isDir(path) {
+		return domain.FileStat{IsDir: true, ModTime: time.Now()}, nil
+	}
+	stored, ok := fs.lookup(path)
+	if !ok {
+		return domain.FileStat{}, os.ErrNotExist
+	}
+	content := fs.files[stored]
+	return domain.FileStat{Size: int64(len(content)), ModTime: time.Unix(0, 0)}, nil
+}
+
+// ReadDir lists the children of dir; folders are implied by the file paths
+func (fs *fakeFileSystem) ReadDir(dir string) ([]domain.DirEntry, error) {
+	if fs.unreadable[dir] {
+		return nil, os.ErrPermission
+	}
+	prefix := strings.TrimSuffix(dir, "/") + "/"
+	children := make(map[string]bool)
+	for path := range fs.files {
+		if !strings.HasPrefix(path, prefix) {
+			continue
+		}
+		name, rest, isDir := strings.Cut(strings.TrimPrefix(path, prefix), "/")
+		children[name] = children[name] || (isDir && rest != "")
+	}
+	if len(children) == 0 {
+		return nil, os.ErrNotExist
+	}
+
+	entries := make([]domain.DirEntry, 0, len(children))
+	for name, isDir := range children {
+		entries = append(entries, domain.DirEntry{Name: name, IsDir: isDir})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, nil
+}
+
+func (fs *fakeFileSystem) ReadFile(path string) ([]byte, error) {
+	if fs.unreadable[path] {
+		return nil, os.ErrPermission
+	}
+	stored, ok := fs.lookup(path)
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return []byte(fs.files[stored]), nil
+}
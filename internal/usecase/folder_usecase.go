@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"rename/internal/domain"
+)
+
+// DirectoryService defines the directory operations needed to expand folders
+// Following ISP (Interface Segregation Principle)
+type DirectoryService interface {
+	ReadDir(path string) ([]domain.DirEntry, error)
+	ReadFile(path string) ([]byte, error)
+	Stat(path string) (domain.FileStat, error)
+}
+
+// FolderUseCase expands a folder into the files to rename
+// Following SRP and DIP
+type FolderUseCase struct {
+	directories DirectoryService
+}
+
+// NewFolderUseCase creates a new FolderUseCase
+func NewFolderUseCase(directories DirectoryService) *FolderUseCase {
+	return &FolderUseCase{
+		directories: directories,
+	}
+}
+
+// folderWalk holds the state of a single Collect call
+type folderWalk struct {
+	options domain.FolderOptions
+	filter  *domain.FileFilter
+	files   []*domain.File
+	skipped []error
+}
+
+// NewFiles creates rename targets for paths, marking directories so they are renamed as a whole
+// Paths that cannot be read are treated as files (their rename reports the error)
+func (uc *FolderUseCase) NewFiles(paths []string) []*domain.File {
+	files := make([]*domain.File, len(paths))
+	for i, path := range paths {
+		if stat, err := uc.directories.Stat(path); err == nil && stat.IsDir {
+			files[i] = domain.NewDirectory(path)
+			continue
+		}
+		files[i] = domain.NewFile(path)
+	}
+	return files
+}
+
+// Collect walks root and returns the files and/or directories selected by options
+// Entries are visited depth-first in name order, a directory before its contents
+// Symbolic links to folders are not followed
+// Subfolders that cannot be read (or whose .gitignore cannot be read) are left out and
+// returned as skipped; only an unreadable root fails the walk
+func (uc *FolderUseCase) Collect(root string, options domain.FolderOptions) ([]*domain.File, []error, error) {
+	if err := options.Validate(); err != nil {
+		return nil, nil, err
+	}
+	filter, err := domain.NewFileFilter(options.Include, options.Exclude, options.Regex)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	walk := &folderWalk{
+		options: options,
+		filter:  filter,
+		files:   make([]*domain.File, 0),
+	}
+	if err := uc.walk(walk, root, "", 1, nil); err != nil {
+		return nil, nil, err
+	}
+	return walk.files, walk.skipped, nil
+}
+
+// walk visits dir (rel is its slash-separated path relative to the root, depth starts at 1)
+// It fails only when dir itself cannot be read; unreadable subfolders are recorded in walk.skipped
+func (uc *FolderUseCase) walk(walk *folderWalk, dir, rel string, depth int, gitignores []*domain.Gitignore) error {
+	entries, err := uc.directories.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("Failed to read %s: %v", dir, err)
+	}
+
+	if walk.options.RespectGitignore {
+		for _, entry := range entries {
+			if entry.Name != domain.GitignoreFileName || entry.IsDir {
+				continue
+			}
+			content, err := uc.directories.ReadFile(filepath.Join(dir, entry.Name))
+			if err != nil {
+				return fmt.Errorf("Failed to read %s: %v", filepath.Join(dir, entry.Name), err)
+			}
+			// Copy so sibling folders do not share appended rules
+			gitignores = append(gitignores[:len(gitignores):len(gitignores)], domain.ParseGitignore(rel, string(content)))
+		}
+	}
+
+	for _, entry := range entries {
+		if !walk.options.IncludeHidden && domain.IsHiddenName(entry.Name) {
+			continue
+		}
+
+		entryRel := path.Join(rel, entry.Name)
+		if walk.options.RespectGitignore {
+			if entry.IsDir && entry.Name == ".git" {
+				continue
+			}
+			if domain.IsIgnored(gitignores, entryRel, entry.IsDir) {
+				continue
+			}
+		}
+		if walk.filter.Excluded(entryRel) {
+			continue
+		}
+
+		entryPath := filepath.Join(dir, entry.Name)
+		if entry.IsDir {
+			if walk.options.Targets.IncludesDirectories() && walk.filter.Included(entryRel) {
+				walk.files = append(walk.files, domain.NewDirectory(entryPath))
+			}
+			if walk.options.MaxDepth > 0 && depth >= walk.options.MaxDepth {
+				continue
+			}
+			if err := uc.walk(walk, entryPath, entryRel, depth+1, gitignores); err != nil {
+				walk.skipped = append(walk.skipped, err)
+			}
+			continue
+		}
+
+		if walk.options.Targets.IncludesFiles() && walk.filter.Included(entryRel) {
+			walk.files = append(walk.files, domain.NewFile(entryPath))
+		}
+	}
+
+	return nil
+}
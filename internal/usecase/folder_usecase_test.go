@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"testing"
+
+	"rename/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newFolderFixture() *fakeFileSystem {
+	return newFakeFileSystem(map[string]string{
+		"/photos/a.jpg":                "",
+		"/photos/b.JPG":                "",
+		"/photos/notes.txt":            "",
+		"/photos/.hidden.jpg":          "",
+		"/photos/.gitignore":           "*.tmp\nexports/\n",
+		"/photos/2024/c.jpg":           "",
+		"/photos/2024/draft.tmp":       "",
+		"/photos/2024/trip/d.jpg":      "",
+		"/photos/2024/trip/.gitignore": "!keep.tmp\n",
+		"/photos/2024/trip/keep.tmp":   "",
+		"/photos/exports/e.jpg":        "",
+		"/photos/.git/config":          "",
+	})
+}
+
+func collectedPaths(files []*domain.File) []string {
+	paths := make([]string, len(files))
+	for i, file := range files {
+		paths[i] = file.OriginalPath()
+	}
+	return paths
+}
+
+func TestFolderUseCase_Collect(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  domain.FolderOptions
+		expected []string
+	}{
+		{
+			name:    "all visible files",
+			options: domain.FolderOptions{},
+			expected: []string{
+				"/photos/2024/c.jpg", "/photos/2024/draft.tmp", "/photos/2024/trip/d.jpg", "/photos/2024/trip/keep.tmp",
+				"/photos/a.jpg", "/photos/b.JPG", "/photos/exports/e.jpg", "/photos/notes.txt",
+			},
+		},
+		{
+			name:     "max depth",
+			options:  domain.FolderOptions{MaxDepth: 1},
+			expected: []string{"/photos/a.jpg", "/photos/b.JPG", "/photos/notes.txt"},
+		},
+		{
+			name:     "include and exclude",
+			options:  domain.FolderOptions{Include: []string{"*.jpg"}, Exclude: []string{"trip", "exports"}},
+			expected: []string{"/photos/2024/c.jpg", "/photos/a.jpg", "/photos/b.JPG"},
+		},
+		{
+			name:     "regex filters",
+			options:  domain.FolderOptions{Include: []string{`^2024/`}, Exclude: []string{`\.tmp$`}, Regex: true},
+			expected: []string{"/photos/2024/c.jpg", "/photos/2024/trip/d.jpg"},
+		},
+		{
+			name:     "hidden files",
+			options:  domain.FolderOptions{MaxDepth: 1, IncludeHidden: true, Include: []string{"*.jpg"}},
+			expected: []string{"/photos/.hidden.jpg", "/photos/a.jpg", "/photos/b.JPG"},
+		},
+		{
+			name:    "gitignore",
+			options: domain.FolderOptions{RespectGitignore: true, IncludeHidden: true},
+			expected: []string{
+				"/photos/.gitignore", "/photos/.hidden.jpg", "/photos/2024/c.jpg",
+				"/photos/2024/trip/.gitignore", "/photos/2024/trip/d.jpg", "/photos/2024/trip/keep.tmp",
+				"/photos/a.jpg", "/photos/b.JPG", "/photos/notes.txt",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useCase := NewFolderUseCase(newFolderFixture())
+
+			files, skipped, err := useCase.Collect("/photos", tt.options)
+
+			assert.NoError(t, err)
+			assert.Empty(t, skipped)
+			assert.Equal(t, tt.expected, collectedPaths(files))
+		})
+	}
+}
+
+func TestFolderUseCase_Collect_Targets(t *testing.T) {
+	useCase := NewFolderUseCase(newFolderFixture())
+
+	dirs, _, err := useCase.Collect("/photos", domain.FolderOptions{Targets: domain.TargetDirectories})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/photos/2024", "/photos/2024/trip", "/photos/exports"}, collectedPaths(dirs))
+	for _, dir := range dirs {
+		assert.True(t, dir.IsDir())
+	}
+
+	both, _, err := useCase.Collect("/photos", domain.FolderOptions{Targets: domain.TargetBoth, MaxDepth: 1, Include: []string{"2024", "a.jpg"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/photos/2024", "/photos/a.jpg"}, collectedPaths(both))
+	assert.True(t, both[0].IsDir())
+	assert.False(t, both[1].IsDir())
+}
+
+func TestFolderUseCase_NewFiles(t *testing.T) {
+	useCase := NewFolderUseCase(newFolderFixture())
+
+	files := useCase.NewFiles([]string{"/photos/2024", "/photos/a.jpg", "/photos/missing.jpg"})
+
+	assert.True(t, files[0].IsDir())
+	assert.False(t, files[1].IsDir())
+	assert.False(t, files[2].IsDir())
+}
+
+func TestFolderUseCase_Collect_Errors(t *testing.T) {
+	useCase := NewFolderUseCase(newFolderFixture())
+
+	_, _, err := useCase.Collect("/photos", domain.FolderOptions{Targets: "links"})
+	assert.Error(t, err)
+
+	_, _, err = useCase.Collect("/missing", domain.FolderOptions{})
+	assert.Error(t, err)
+
+	_, _, err = useCase.Collect("/photos", domain.FolderOptions{Include: []string{"[abc"}})
+	assert.Error(t, err)
+
+	t.Run("unreadable root", func(t *testing.T) {
+		fs := newFolderFixture()
+		fs.unreadable["/photos"] = true
+
+		_, _, err := NewFolderUseCase(fs).Collect("/photos", domain.FolderOptions{})
+		assert.ErrorContains(t, err, "permission denied")
+	})
+
+	t.Run("unreadable subfolder is skipped", func(t *testing.T) {
+		fs := newFolderFixture()
+		fs.unreadable["/photos/2024/trip"] = true
+		fs.unreadable["/photos/exports"] = true
+
+		files, skipped, err := NewFolderUseCase(fs).Collect("/photos", domain.FolderOptions{Targets: domain.TargetBoth})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"/photos/2024", "/photos/2024/c.jpg", "/photos/2024/draft.tmp", "/photos/2024/trip",
+			"/photos/a.jpg", "/photos/b.JPG", "/photos/exports", "/photos/notes.txt",
+		}, collectedPaths(files))
+		if assert.Len(t, skipped, 2) {
+			assert.ErrorContains(t, skipped[0], "/photos/2024/trip: permission denied")
+			assert.ErrorContains(t, skipped[1], "/photos/exports: permission denied")
+		}
+	})
+
+	t.Run("unreadable gitignore skips its folder", func(t *testing.T) {
+		fs := newFolderFixture()
+		fs.unreadable["/photos/2024/trip/.gitignore"] = true
+
+		files, skipped, err := NewFolderUseCase(fs).Collect("/photos", domain.FolderOptions{RespectGitignore: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"/photos/2024/c.jpg", "/photos/a.jpg", "/photos/b.JPG", "/photos/notes.txt"}, collectedPaths(files))
+		if assert.Len(t, skipped, 1) {
+			assert.ErrorContains(t, skipped[0], "/photos/2024/trip/.gitignore: permission denied")
+		}
+	})
+}
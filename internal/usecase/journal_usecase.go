@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"rename/internal/domain"
+)
+
+// JournalRepository defines undo journal persistence operations
+// Following ISP (Interface Segregation Principle)
+type JournalRepository interface {
+	Save(journal *domain.Journal) error
+	Load() (*domain.Journal, error)
+}
+
+// UndoResult represents the result of an undo operation
+type UndoResult struct {
+	RestoredCount int
+	FailureCount  int
+	Errors        []string
+	Restored      []domain.RenameOperation
+}
+
+// JournalUseCase records executed renames and reverts them
+// Following SRP and DIP
+type JournalUseCase struct {
+	repository JournalRepository
+	fileSystem FileSystemService
+}
+
+// NewJournalUseCase creates a new JournalUseCase
+func NewJournalUseCase(repository JournalRepository, fileSystem FileSystemService) *JournalUseCase {
+	return &JournalUseCase{
+		repository: repository,
+		fileSystem: fileSystem,
+	}
+}
+
+// Record stores the operations of an executed rename as one batch
+// Size and modification time are captured so later changes can be detected on undo
+// (not for directories, whose modification time changes with their contents)
+func (uc *JournalUseCase) Record(operations []domain.RenameOperation) error {
+	if len(operations) == 0 {
+		return nil
+	}
+
+	journal, err := uc.repository.Load()
+	if err != nil {
+		return err
+	}
+
+	batch := domain.RenameBatch{
+		ExecutedAt: time.Now(),
+		Operations: make([]domain.RenameOperation, len(operations)),
+	}
+	for i, op := range operations {
+		if stat, err := uc.fileSystem.Stat(op.NewPath); err == nil && !stat.IsDir {
+			op.Size = stat.Size
+			op.ModTime = stat.ModTime
+		}
+		batch.Operations[i] = op
+	}
+
+	journal.Add(batch)
+	return uc.repository.Save(journal)
+}
+
+// GetBatches returns all recorded batches (most recent first)
+func (uc *JournalUseCase) GetBatches() ([]domain.RenameBatch, error) {
+	journal, err := uc.repository.Load()
+	if err != nil {
+		return nil, err
+	}
+	return journal.GetAll(), nil
+}
+
+// Undo reverts the last count batches (most recent first)
+// Files that have since been moved or modified are skipped and reported
+// Operations that could not be undone stay in the journal, so the undo can be retried
+func (uc *JournalUseCase) Undo(count int) (UndoResult, error) {
+	result := UndoResult{
+		Errors:   make([]string, 0),
+		Restored: make([]domain.RenameOperation, 0),
+	}
+
+	journal, err := uc.repository.Load()
+	if err != nil {
+		return result, err
+	}
+
+	remaining := make([]domain.RenameBatch, 0)
+	for _, batch := range journal.TakeRecent(count) {
+		if failed := uc.undoBatch(batch, &result); len(failed.Operations) > 0 {
+			remaining = append(remaining, failed)
+		}
+	}
+	journal.PutBack(remaining)
+
+	return result, uc.repository.Save(journal)
+}
+
+// undoBatch moves every unchanged file of batch back to its original path
+// The inverse batch goes through batchRenamer so undoing swaps and chains works too
+// Returns the operations of batch that were not undone
+func (uc *JournalUseCase) undoBatch(batch domain.RenameBatch, result *UndoResult) domain.RenameBatch {
+	// Revert in reverse order so chained renames unwind correctly
+	count := len(batch.Operations)
+	errs := make([]error, count)
+	moves := make([]renameMove, 0, count)
+	moveOps := make([]int, 0, count)
+
+	for i := count - 1; i >= 0; i-- {
+		op := batch.Operations[i]
+		if err := uc.checkUnchanged(op); err != nil {
+			errs[i] = err
+			continue
+		}
+		moves = append(moves, renameMove{source: op.NewPath, target: op.OldPath})
+		moveOps = append(moveOps, i)
+	}
+
+	renamer := newBatchRenamer(uc.fileSystem, func(index int, target string) (string, error) {
+		return "", fmt.Errorf("Cannot undo %s: %s already exists", moves[index].source, target)
+	})
+	for index, outcome := range renamer.run(moves) {
+		errs[moveOps[index]] = outcome.err
+	}
+
+	failed := domain.RenameBatch{ExecutedAt: batch.ExecutedAt, Operations: make([]domain.RenameOperation, 0)}
+	for i := count - 1; i >= 0; i-- {
+		if errs[i] != nil {
+			result.FailureCount++
+			result.Errors = append(result.Errors, errs[i].Error())
+			continue
+		}
+		result.RestoredCount++
+		result.Restored = append(result.Restored, batch.Operations[i])
+	}
+	for i, op := range batch.Operations {
+		if errs[i] != nil {
+			failed.Operations = append(failed.Operations, op)
+		}
+	}
+	return failed
+}
+
+// checkUnchanged verifies the renamed file is still where and what it was after the rename
+func (uc *JournalUseCase) checkUnchanged(op domain.RenameOperation) error {
+	if !uc.fileSystem.FileExists(op.NewPath) {
+		return fmt.Errorf("Cannot undo %s: file has been moved or deleted", op.NewPath)
+	}
+
+	stat, err := uc.fileSystem.Stat(op.NewPath)
+	if err != nil {
+		return fmt.Errorf("Cannot undo %s: %v", op.NewPath, err)
+	}
+	if !op.ModTime.IsZero() && (stat.Size != op.Size || !stat.ModTime.Equal(op.ModTime)) {
+		return fmt.Errorf("Cannot undo %s: file has been modified since rename", op.NewPath)
+	}
+
+	return nil
+}
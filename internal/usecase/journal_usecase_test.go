@@ -0,0 +1,297 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"rename/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockJournalRepository is a mock implementation of JournalRepository
+type MockJournalRepository struct {
+	mock.Mock
+}
+
+func (m *MockJournalRepository) Save(journal *domain.Journal) error {
+	args := m.Called(journal)
+	return args.Error(0)
+}
+
+func (m *MockJournalRepository) Load() (*domain.Journal, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Journal), args.Error(1)
+}
+
+var testModTime = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func newJournalWith(batches ...domain.RenameBatch) *domain.Journal {
+	journal := domain.NewJournal()
+	for _, batch := range batches {
+		journal.Add(batch)
+	}
+	return journal
+}
+
+func TestJournalUseCase_Record(t *testing.T) {
+	mockRepo := new(MockJournalRepository)
+	mockFS := new(MockFileSystemService)
+	useCase := NewJournalUseCase(mockRepo, mockFS)
+
+	journal := domain.NewJournal()
+	mockRepo.On("Load").Return(journal, nil)
+	mockRepo.On("Save", journal).Return(nil)
+	mockFS.On("Stat", "/path/to/renamed.txt").Return(domain.FileStat{Size: 10, ModTime: testModTime}, nil)
+
+	err := useCase.Record([]domain.RenameOperation{
+		{OldPath: "/path/to/test.txt", NewPath: "/path/to/renamed.txt"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, journal.Count())
+	op := journal.GetAll()[0].Operations[0]
+	assert.Equal(t, int64(10), op.Size)
+	assert.Equal(t, testModTime, op.ModTime)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestJournalUseCase_Record_Empty(t *testing.T) {
+	mockRepo := new(MockJournalRepository)
+	useCase := NewJournalUseCase(mockRepo, new(MockFileSystemService))
+
+	err := useCase.Record(nil)
+
+	assert.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestJournalUseCase_Undo(t *testing.T) {
+	mockRepo := new(MockJournalRepository)
+	mockFS := new(MockFileSystemService)
+	useCase := NewJournalUseCase(mockRepo, mockFS)
+
+	journal := newJournalWith(
+		domain.RenameBatch{Operations: []domain.RenameOperation{
+			{OldPath: "/path/to/old.txt", NewPath: "/path/to/older.txt", Size: 1, ModTime: testModTime},
+		}},
+		domain.RenameBatch{Operations: []domain.RenameOperation{
+			{OldPath: "/path/to/a.txt", NewPath: "/path/to/b.txt", Size: 5, ModTime: testModTime},
+			{OldPath: "/path/to/c.txt", NewPath: "/path/to/d.txt", Size: 7, ModTime: testModTime},
+		}},
+	)
+	mockRepo.On("Load").Return(journal, nil)
+	mockRepo.On("Save", journal).Return(nil)
+
+	mockFS.On("FileExists", "/path/to/b.txt").Return(true)
+	mockFS.On("FileExists", "/path/to/d.txt").Return(true)
+	mockFS.On("FileExists", "/path/to/a.txt").Return(false)
+	mockFS.On("FileExists", "/path/to/c.txt").Return(false)
+	mockFS.On("Stat", "/path/to/b.txt").Return(domain.FileStat{Size: 5, ModTime: testModTime}, nil)
+	mockFS.On("Stat", "/path/to/d.txt").Return(domain.FileStat{Size: 7, ModTime: testModTime}, nil)
+	mockFS.On("RenameFile", "/path/to/d.txt", "/path/to/c.txt").Return(nil).Once()
+	mockFS.On("RenameFile", "/path/to/b.txt", "/path/to/a.txt").Return(nil).Once()
+
+	result, err := useCase.Undo(1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.RestoredCount)
+	assert.Equal(t, 0, result.FailureCount)
+	// Operations are reverted in reverse order
+	assert.Equal(t, "/path/to/d.txt", result.Restored[0].NewPath)
+	assert.Equal(t, "/path/to/b.txt", result.Restored[1].NewPath)
+	// Older batch stays in the journal
+	assert.Equal(t, 1, journal.Count())
+	mockFS.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestJournalUseCase_Undo_DetectsChanges(t *testing.T) {
+	mockRepo := new(MockJournalRepository)
+	mockFS := new(MockFileSystemService)
+	useCase := NewJournalUseCase(mockRepo, mockFS)
+
+	journal := newJournalWith(domain.RenameBatch{Operations: []domain.RenameOperation{
+		{OldPath: "/path/to/moved.txt", NewPath: "/path/to/moved-new.txt", Size: 1, ModTime: testModTime},
+		{OldPath: "/path/to/edited.txt", NewPath: "/path/to/edited-new.txt", Size: 1, ModTime: testModTime},
+		{OldPath: "/path/to/taken.txt", NewPath: "/path/to/taken-new.txt", Size: 1, ModTime: testModTime},
+		{OldPath: "/path/to/denied.txt", NewPath: "/path/to/denied-new.txt", Size: 1, ModTime: testModTime},
+	}})
+	mockRepo.On("Load").Return(journal, nil)
+	mockRepo.On("Save", journal).Return(nil)
+
+	unchanged := domain.FileStat{Size: 1, ModTime: testModTime}
+
+	// Moved away after rename
+	mockFS.On("FileExists", "/path/to/moved-new.txt").Return(false)
+
+	// Modified after rename
+	mockFS.On("FileExists", "/path/to/edited-new.txt").Return(true)
+	mockFS.On("Stat", "/path/to/edited-new.txt").Return(domain.FileStat{Size: 99, ModTime: testModTime.Add(time.Hour)}, nil)
+
+	// Original name reused by another file
+	mockFS.On("FileExists", "/path/to/taken-new.txt").Return(true)
+	mockFS.On("Stat", "/path/to/taken-new.txt").Return(unchanged, nil)
+	mockFS.On("FileExists", "/path/to/taken.txt").Return(true)
+	mockFS.On("SameFile", "/path/to/taken-new.txt", "/path/to/taken.txt").Return(false)
+
+	// Rename itself fails
+	mockFS.On("FileExists", "/path/to/denied-new.txt").Return(true)
+	mockFS.On("Stat", "/path/to/denied-new.txt").Return(unchanged, nil)
+	mockFS.On("FileExists", "/path/to/denied.txt").Return(false)
+	mockFS.On("RenameFile", "/path/to/denied-new.txt", "/path/to/denied.txt").Return(errors.New("permission denied"))
+
+	result, err := useCase.Undo(1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.RestoredCount)
+	assert.Equal(t, 4, result.FailureCount)
+	assert.Contains(t, result.Errors[0], "permission denied")
+	assert.Contains(t, result.Errors[1], "already exists")
+	assert.Contains(t, result.Errors[2], "modified")
+	assert.Contains(t, result.Errors[3], "moved or deleted")
+	// Nothing was undone, so the whole batch stays for another attempt
+	assert.Equal(t, 1, journal.Count())
+	assert.Len(t, journal.GetAll()[0].Operations, 4)
+	mockFS.AssertExpectations(t)
+}
+
+func TestJournalUseCase_Undo_Retry(t *testing.T) {
+	fs := newFakeFileSystem(map[string]string{"/dir/a.txt": "A", "/dir/b.txt": "B"})
+	renameUseCase := NewRenameUseCase(fs)
+	files := previewFiles(renameUseCase, map[string]string{"a.txt": "c.txt", "b.txt": "d.txt"}, "/dir/a.txt", "/dir/b.txt")
+	renameResult := renameUseCase.Execute(context.Background(), files)
+
+	mockRepo := new(MockJournalRepository)
+	journal := domain.NewJournal()
+	mockRepo.On("Load").Return(journal, nil)
+	mockRepo.On("Save", journal).Return(nil)
+	useCase := NewJournalUseCase(mockRepo, fs)
+	assert.NoError(t, useCase.Record(renameResult.Operations))
+
+	// Another file took the original name of b.txt meanwhile
+	fs.files["/dir/b.txt"] = "other"
+	result, err := useCase.Undo(1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.RestoredCount)
+	assert.Equal(t, 1, result.FailureCount)
+	assert.Equal(t, 1, journal.Count())
+	assert.Equal(t, []domain.RenameOperation{{OldPath: "/dir/b.txt", NewPath: "/dir/d.txt"}},
+		stripStats(journal.GetAll()[0].Operations))
+
+	// Once the name is free again, the failed part can be undone
+	delete(fs.files, "/dir/b.txt")
+	result, err = useCase.Undo(1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.RestoredCount)
+	assert.Equal(t, 0, result.FailureCount)
+	assert.Equal(t, 0, journal.Count())
+	assert.Equal(t, map[string]string{"/dir/a.txt": "A", "/dir/b.txt": "B"}, fs.files)
+}
+
+// stripStats drops the recorded size and modification time of operations
+func stripStats(operations []domain.RenameOperation) []domain.RenameOperation {
+	stripped := make([]domain.RenameOperation, len(operations))
+	for i, op := range operations {
+		stripped[i] = domain.RenameOperation{OldPath: op.OldPath, NewPath: op.NewPath}
+	}
+	return stripped
+}
+
+func TestJournalUseCase_Undo_LoadError(t *testing.T) {
+	mockRepo := new(MockJournalRepository)
+	useCase := NewJournalUseCase(mockRepo, new(MockFileSystemService))
+
+	mockRepo.On("Load").Return(nil, errors.New("load failed"))
+
+	_, err := useCase.Undo(1)
+
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestJournalUseCase_Undo_Swap(t *testing.T) {
+	fs := newFakeFileSystem(map[string]string{
+		"/dir/a.txt": "A",
+		"/dir/b.txt": "B",
+	})
+	renameUseCase := NewRenameUseCase(fs)
+	files := previewFiles(renameUseCase, map[string]string{"a.txt": "b.txt", "b.txt": "a.txt"}, "/dir/a.txt", "/dir/b.txt")
+	renameResult := renameUseCase.Execute(context.Background(), files)
+
+	mockRepo := new(MockJournalRepository)
+	journal := domain.NewJournal()
+	mockRepo.On("Load").Return(journal, nil)
+	mockRepo.On("Save", journal).Return(nil)
+	useCase := NewJournalUseCase(mockRepo, fs)
+
+	assert.NoError(t, useCase.Record(renameResult.Operations))
+	result, err := useCase.Undo(1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.RestoredCount)
+	assert.Equal(t, 0, result.FailureCount)
+	assert.Equal(t, map[string]string{"/dir/a.txt": "A", "/dir/b.txt": "B"}, fs.files)
+}
+
+func TestJournalUseCase_Undo_DirectoryWithContents(t *testing.T) {
+	original := map[string]string{
+		"/root/Album/IMG_1.jpg":     "one",
+		"/root/Album/Sub/IMG_2.jpg": "two",
+	}
+	fs := newFakeFileSystem(original)
+	renameUseCase := NewRenameUseCase(fs)
+	files := []*domain.File{
+		domain.NewDirectory("/root/Album"),
+		domain.NewDirectory("/root/Album/Sub"),
+		domain.NewFile("/root/Album/IMG_1.jpg"),
+		domain.NewFile("/root/Album/Sub/IMG_2.jpg"),
+	}
+	renameUseCase.GeneratePreview(files, renameMapStrategy{"Album": "album", "Sub": "sub", "IMG_1.jpg": "1.jpg", "IMG_2.jpg": "2.jpg"})
+	renameResult := renameUseCase.Execute(context.Background(), files)
+	assert.Equal(t, 4, renameResult.SuccessCount)
+
+	mockRepo := new(MockJournalRepository)
+	journal := domain.NewJournal()
+	mockRepo.On("Load").Return(journal, nil)
+	mockRepo.On("Save", journal).Return(nil)
+	useCase := NewJournalUseCase(mockRepo, fs)
+
+	assert.NoError(t, useCase.Record(renameResult.Operations))
+	result, err := useCase.Undo(1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, result.RestoredCount)
+	assert.Equal(t, 0, result.FailureCount)
+	assert.Equal(t, original, fs.files)
+}
+
+func TestJournalUseCase_Undo_CaseOnlyChange(t *testing.T) {
+	fs := newCaseInsensitiveFileSystem(map[string]string{
+		"/dir/photo.JPG": "photo",
+	})
+	renameUseCase := NewRenameUseCase(fs)
+	files := previewFiles(renameUseCase, map[string]string{"photo.JPG": "photo.jpg"}, "/dir/photo.JPG")
+	renameResult := renameUseCase.Execute(context.Background(), files)
+
+	mockRepo := new(MockJournalRepository)
+	journal := domain.NewJournal()
+	mockRepo.On("Load").Return(journal, nil)
+	mockRepo.On("Save", journal).Return(nil)
+	useCase := NewJournalUseCase(mockRepo, fs)
+
+	assert.NoError(t, useCase.Record(renameResult.Operations))
+	result, err := useCase.Undo(1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.RestoredCount)
+	assert.Equal(t, map[string]string{"/dir/photo.JPG": "photo"}, fs.files)
+}
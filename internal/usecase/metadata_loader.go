@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"maps"
+	"sync"
+
+	"rename/internal/domain"
+)
+
+// metadataPlan describes which metadata a strategy reads
+type metadataPlan struct {
+	stat    bool                   // File system fields (cached stats, cheap)
+	content bool                   // Fields from the metadata provider (EXIF, audio tags)
+	hashes  []domain.HashAlgorithm // Content digests
+}
+
+// planMetadata finds the metadata needed by strategy that can be provided
+func (uc *RenameUseCase) planMetadata(strategy domain.RenameStrategy) metadataPlan {
+	var plan metadataPlan
+	for _, field := range domain.MetadataFields(strategy) {
+		if algorithm, ok := domain.ParseHashField(field); ok {
+			if uc.hasher != nil {
+				plan.hashes = append(plan.hashes, algorithm)
+			}
+			continue
+		}
+		if domain.IsFileStatField(field) {
+			plan.stat = true
+		} else if uc.metadata != nil {
+			plan.content = true
+		}
+	}
+	return plan
+}
+
+// readsContents reports whether the plan opens files (slow for large folders)
+func (p metadataPlan) readsContents() bool {
+	return p.content || len(p.hashes) > 0
+}
+
+// loadMetadata returns the metadata of each file (nil entries when nothing is needed or readable)
+// Files are read by a pool of workers, reporting progress after each file
+func (uc *RenameUseCase) loadMetadata(files []*domain.File, plan metadataPlan) []domain.Metadata {
+	metadata := make([]domain.Metadata, len(files))
+	if !plan.stat && !plan.readsContents() {
+		return metadata
+	}
+	if !plan.readsContents() {
+		for i, file := range files {
+			metadata[i] = uc.fileMetadata(file, plan)
+		}
+		return metadata
+	}
+
+	progress := newProgressReporter(uc.progress, len(files))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < max(1, min(uc.workers, len(files))); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				metadata[i] = uc.fileMetadata(files[i], plan)
+				progress.advance()
+			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return metadata
+}
+
+// fileMetadata collects the metadata of file described by plan
+// Files without readable metadata are still renamed (with empty fields)
+func (uc *RenameUseCase) fileMetadata(file *domain.File, plan metadataPlan) domain.Metadata {
+	stat, err := uc.stat(file.OriginalPath())
+	if err != nil {
+		return nil
+	}
+
+	metadata := domain.Metadata{}
+	if plan.stat {
+		maps.Copy(metadata, domain.StatMetadata(stat))
+	}
+	if plan.content {
+		if content, err := uc.metadata.Metadata(file.OriginalPath(), stat); err == nil {
+			maps.Copy(metadata, content)
+		}
+	}
+	if !file.IsDir() {
+		for _, algorithm := range plan.hashes {
+			if digest, err := uc.hasher.Hash(file.OriginalPath(), algorithm, stat); err == nil {
+				metadata[domain.HashField(algorithm)] = digest
+			}
+		}
+	}
+
+	if len(metadata) == 0 {
+		return nil
+	}
+	return metadata
+}
+
+// progressReporter forwards progress in order, at most about 100 times per batch
+type progressReporter struct {
+	mu       sync.Mutex
+	report   ProgressFunc
+	done     int
+	total    int
+	interval int
+}
+
+func newProgressReporter(report ProgressFunc, total int) *progressReporter {
+	return &progressReporter{report: report, total: total, interval: max(1, total/100)}
+}
+
+// advance records one finished file
+func (p *progressReporter) advance() {
+	if p.report == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.done++
+	if p.done%p.interval == 0 || p.done == p.total {
+		p.report(p.done, p.total)
+	}
+}
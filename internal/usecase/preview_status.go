@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"rename/internal/domain"
+)
+
+// BlockingItems returns the preview items whose status prevents the batch from being executed
+// The GUI and CLI refuse to execute while it is not empty
+func BlockingItems(items []PreviewItem) []PreviewItem {
+	blocking := make([]PreviewItem, 0)
+	for _, item := range items {
+		if item.Status.IsError() {
+			blocking = append(blocking, item)
+		}
+	}
+	return blocking
+}
+
+// setStatuses fills in the status of each preview item
+// Checks run from the most to the least severe and the first match wins:
+// missing or inaccessible sources, invalid names, in-batch collisions, then conflicts with existing files
+func (uc *RenameUseCase) setStatuses(items []PreviewItem) {
+	uc.markResolvedCollisions(items)
+	dirErrors := make(map[string]error) // Access checks are shared by the files of a folder
+
+	for i := range items {
+		item := &items[i]
+		file := item.File
+		renamed := item.ResolvedName != file.OriginalName()
+		// Files skipped because of an in-batch collision still report it
+		if !renamed && !uc.trashes(file) && len(item.Collisions) == 0 {
+			item.Status = domain.StatusUnchanged
+			continue
+		}
+
+		// A cheap check, as previews are rebuilt on every keystroke (see MissingSources for Execute)
+		// A symbolic link is renamed itself, so a link whose target is gone is not missing
+		if !uc.fileSystem.FileExists(file.OriginalPath()) {
+			item.Status, item.Detail = sourceStatus(file, fs.ErrNotExist)
+			continue
+		}
+		dirErr, checked := dirErrors[file.Directory()]
+		if !checked {
+			dirErr = uc.fileSystem.CanRename(file.OriginalPath())
+			dirErrors[file.Directory()] = dirErr
+		}
+		if dirErr != nil {
+			item.Status, item.Detail = sourceStatus(file, dirErr)
+			continue
+		}
+
+		if !renamed && len(item.Collisions) == 0 {
+			// A duplicate moved to the trash keeps its name
+			item.Status = domain.StatusUnchanged
+			continue
+		}
+
+		if len(item.Issues) > 0 {
+			item.Status = domain.StatusInvalidName
+			item.Detail = fmt.Sprintf("invalid on %s: %s", item.Issues[0].Platform, item.Issues[0].Reason)
+			continue
+		}
+
+		if len(item.Collisions) > 0 {
+			names := make([]string, 0, len(item.Collisions)-1)
+			for _, path := range item.Collisions {
+				if path != file.OriginalPath() {
+					names = append(names, filepath.Base(path))
+				}
+			}
+			item.Status = domain.StatusCollidesWithinBatch
+			item.Detail = fmt.Sprintf("same new name as %s (%s)", strings.Join(names, ", "), uc.conflictPolicy)
+			continue
+		}
+
+		if item.Conflict {
+			item.Status = domain.StatusCollidesWithExisting
+			item.Detail = fmt.Sprintf("%s already exists (%s)", file.NewName(), item.Action)
+			continue
+		}
+
+		item.Status = domain.StatusWillRename
+	}
+}
+
+// MissingSources returns the files that would be renamed or trashed but no longer exist
+// The preview may be older than the batch, so callers check again just before Execute
+func (uc *RenameUseCase) MissingSources(files []*domain.File) []*domain.File {
+	missing := make([]*domain.File, 0)
+	for _, file := range files {
+		if (file.HasChanged() || uc.trashes(file)) && !uc.fileSystem.FileExists(file.OriginalPath()) {
+			missing = append(missing, file)
+		}
+	}
+	return missing
+}
+
+// sourceStatus maps an error about the source of file to a status
+func sourceStatus(file *domain.File, err error) (domain.FileStatus, string) {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return domain.StatusSourceMissing, fmt.Sprintf("%s no longer exists", file.OriginalName())
+	case errors.Is(err, fs.ErrPermission):
+		return domain.StatusPermissionDenied, fmt.Sprintf("%s cannot be renamed: permission denied", file.OriginalName())
+	}
+	return domain.StatusPermissionDenied, fmt.Sprintf("%s cannot be read: %v", file.OriginalName(), err)
+}
+
+// markResolvedCollisions reports items whose resolved names still collide
+// (a suffix picked for an existing file can be the new name of another file of the batch)
+func (uc *RenameUseCase) markResolvedCollisions(items []PreviewItem) {
+	targets := make(map[string][]int, len(items))
+	for i, item := range items {
+		if item.ResolvedName != item.File.OriginalName() {
+			key := uc.targetKey(item.File.Directory(), item.ResolvedName)
+			targets[key] = append(targets[key], i)
+		}
+	}
+
+	for _, indexes := range targets {
+		if len(indexes) < 2 {
+			continue
+		}
+		participants := make([]string, len(indexes))
+		for k, i := range indexes {
+			participants[k] = items[i].File.OriginalPath()
+		}
+		for _, i := range indexes {
+			if len(items[i].Collisions) == 0 {
+				items[i].Collisions = participants
+			}
+		}
+	}
+}
+
+// targetKey identifies the path dir/name, ignoring case when a target platform is case-insensitive
+func (uc *RenameUseCase) targetKey(dir, name string) string {
+	path := filepath.Join(dir, name)
+	for _, platform := range uc.platforms {
+		if platform.CaseInsensitive() {
+			return strings.ToLower(path)
+		}
+	}
+	return path
+}
@@ -1,10 +1,14 @@
 package usecase
 
 import (
+	"context"
+	"errors"
     "fmt"
     "path/filepath"
-    "strconv"
-    "strings"
+	"runtime"
+	"slices"
+	"sort"
+	"sync"
 
     "rename/internal/domain"
 )
@@ -14,101 +18,605 @@ import (
 type FileSystemService interface {
 	RenameFile(oldPath, newPath string) error
 	FileExists(path string) bool
+	SameFile(path1, path2 string) bool
+	Stat(path string) (domain.FileStat, error)
+	CanRename(path string) error
 }
 
+// MetadataProvider reads metadata (e.g. EXIF) from the contents of a file
+// stat lets the provider reuse earlier results for unchanged files
+type MetadataProvider interface {
+	Metadata(path string, stat domain.FileStat) (domain.Metadata, error)
+}
+
+// ContentHasher computes the hex digest of a file's contents
+// stat lets the hasher reuse earlier results for unchanged files
+type ContentHasher interface {
+	Hash(path string, algorithm domain.HashAlgorithm, stat domain.FileStat) (string, error)
+}
+
+// ProgressFunc receives the number of files processed so far out of total
+type ProgressFunc func(done, total int)
+
+// RenameProgressFunc receives the progress of Execute after each file (path is its original path)
+type RenameProgressFunc func(done, total int, path string)
+
+// ConflictPrompt asks the user how to handle an existing target
+// It should return ConflictSkip, ConflictOverwrite or ConflictSuffix
+type ConflictPrompt func(file *domain.File, existingPath string) domain.ConflictPolicy
+
+// maxRetries limits the search for an available name
+const maxRetries = 1000
+
+// errSkipped marks a move skipped by the conflict policy (not counted as a failure)
+var errSkipped = errors.New("skipped")
+
 // RenameResult represents the result of a rename operation
 type RenameResult struct {
-	SuccessCount  int
-	FailureCount  int
-	Errors        []string
-	NewFilePaths  []string
+	SuccessCount int
+	FailureCount int
+	SkippedCount int
+	TrashedCount int  // Duplicates moved to the trash
+	Aborted      bool // The fail policy or a failed trash (transactional) stopped the batch before anything was renamed
+	Cancelled    bool // The context was cancelled before every file was processed (the rest are skipped)
+	Errors       []string
+	NewFilePaths []string
+	Operations   []domain.RenameOperation
+	TrashedPaths []string // Original paths of the duplicates moved to the trash
+
+	// Transactional batches (see SetTransactional): a failed rename reverts the others
+	RolledBack      bool     // The batch failed and its renames were reverted
+	RolledBackCount int      // Renames reverted (not counted as successes)
+	RollbackErrors  []string // Renames that could not be reverted (counted as successes, recorded for undo)
+}
+
+// PreviewItem describes how a single file will be renamed
+type PreviewItem struct {
+	File         *domain.File
+	Conflict     bool                  // Target name is taken by a file outside the batch
+	Action       domain.ConflictPolicy // How the conflict will be handled
+	ResolvedName string                // Final name after conflict resolution
+	Metadata     domain.Metadata       // Metadata used by the strategy (nil when not needed)
+	DuplicateOf  string                // Earlier file of the batch with the same contents ("" if unique)
+	Duplicates   []string              // Later files of the batch with the same contents as this one
+	Issues       []domain.NameIssue    // Why the resolved name is invalid on the target platforms (nil if valid)
+	Collisions   []string              // Original paths of all files of the batch mapping to the same new name
+	// How the duplicate will be handled (empty unless DuplicateOf is set)
+	DuplicateAction domain.DuplicateAction
+	Status          domain.FileStatus // What will happen to the file (errors block execution, see BlockingItems)
+	Detail          string            // Explanation of the status for the user ("" for will-rename and unchanged)
 }
 
 // RenameUseCase handles file renaming operations
 // Following SRP (Single Responsibility Principle) and DIP (Dependency Inversion Principle)
 type RenameUseCase struct {
-	fileSystem FileSystemService
+	fileSystem     FileSystemService
+	conflictPolicy domain.ConflictPolicy
+	suffix         *domain.SuffixTemplate
+	prompt         ConflictPrompt
+	metadata       MetadataProvider
+	hasher         ContentHasher
+	progress       ProgressFunc
+	renameProgress RenameProgressFunc
+	duplicates     domain.DuplicateOptions
+	platforms      []domain.Platform // Platforms whose naming rules new names are checked against
+	trash          TrashService
+	transactional  bool // A failed rename reverts the whole batch
+	workers        int  // Files whose contents are read concurrently
+
+	// statCache keeps stats between previews, which are regenerated on every keystroke
+	statMu    sync.Mutex
+	statCache map[string]domain.FileStat
 }
 
 // NewRenameUseCase creates a new RenameUseCase
 func NewRenameUseCase(fileSystem FileSystemService) *RenameUseCase {
-	return &RenameUseCase{
+	uc := &RenameUseCase{
 		fileSystem: fileSystem,
+		statCache:  make(map[string]domain.FileStat),
+		workers:    runtime.NumCPU(),
+		duplicates: domain.DefaultDuplicateOptions(),
+		platforms:  []domain.Platform{domain.PlatformFor(runtime.GOOS)},
 	}
+	// Default options are always valid
+	_ = uc.SetConflictOptions(domain.DefaultConflictOptions())
+	return uc
+}
+
+// SetConflictOptions sets how existing target names are handled
+func (uc *RenameUseCase) SetConflictOptions(options domain.ConflictOptions) error {
+	if err := options.Validate(); err != nil {
+		return err
+	}
+
+	suffix, err := domain.NewSuffixTemplate(options.SuffixTemplate, options.SuffixStart)
+	if err != nil {
+		return err
+	}
+
+	uc.conflictPolicy = options.Policy
+	uc.suffix = suffix
+	return nil
+}
+
+// SetTargetPlatforms sets the platforms whose naming rules the preview checks new names against
+// (the current operating system by default)
+func (uc *RenameUseCase) SetTargetPlatforms(platforms []domain.Platform) error {
+	for _, platform := range platforms {
+		if err := platform.Validate(); err != nil {
+			return err
+		}
+	}
+	uc.platforms = platforms
+	return nil
+}
+
+// SetTransactional makes Execute all or nothing: when a rename fails, the renames already performed
+// in the batch are reverted in reverse order (by default failed files are skipped and the rest renamed)
+// Duplicates moved to the trash cannot be restored, so a failed trash stops the batch before renaming
+func (uc *RenameUseCase) SetTransactional(enabled bool) {
+	uc.transactional = enabled
+}
+
+// SetConflictPrompt sets the callback used by the prompt policy
+// Without a prompt, conflicts under the prompt policy are skipped
+func (uc *RenameUseCase) SetConflictPrompt(prompt ConflictPrompt) {
+	uc.prompt = prompt
+}
+
+// SetMetadataProvider sets the source of metadata for template strategies
+// Without a provider, fields read from file contents render empty (file system fields still work)
+func (uc *RenameUseCase) SetMetadataProvider(provider MetadataProvider) {
+	uc.metadata = provider
+}
+
+// SetContentHasher sets how content digests are computed for hash strategies
+// Without a hasher, hash strategies leave names unchanged
+func (uc *RenameUseCase) SetContentHasher(hasher ContentHasher) {
+	uc.hasher = hasher
+}
+
+// SetProgressHandler sets the callback notified while file contents are read for a preview
+// (metadata and digests), so a large folder can show progress; it may be called from several goroutines
+func (uc *RenameUseCase) SetProgressHandler(progress ProgressFunc) {
+	uc.progress = progress
+}
+
+// SetRenameProgressHandler sets the callback notified by Execute after each file is renamed (or fails)
+func (uc *RenameUseCase) SetRenameProgressHandler(progress RenameProgressFunc) {
+	uc.renameProgress = progress
 }
 
 // GeneratePreview applies the strategy to files and returns preview
+// Each file's position is passed to strategies that number files
+// Files mapping to the same new name are resolved by the conflict policy (see Preview)
 func (uc *RenameUseCase) GeneratePreview(files []*domain.File, strategy domain.RenameStrategy) []*domain.File {
-	for _, file := range files {
-		newName := strategy.Apply(file.OriginalName())
-		file.SetNewName(newName)
-	}
+	uc.applyStrategy(files, strategy)
+	uc.resolveBatchCollisions(files)
 	return files
 }
 
+// applyStrategy sets the new name of each file and returns the metadata passed to the strategy
+// Metadata is only read when the strategy uses it; files without metadata get nil
+func (uc *RenameUseCase) applyStrategy(files []*domain.File, strategy domain.RenameStrategy) []domain.Metadata {
+	metadata := uc.loadMetadata(files, uc.planMetadata(strategy))
+
+	for i, file := range files {
+		ctx := domain.RenameContext{Index: i, Path: file.OriginalPath(), IsDir: file.IsDir(), Metadata: metadata[i]}
+		file.SetNewName(domain.ApplyStrategy(strategy, file.OriginalName(), ctx))
+	}
+	return metadata
+}
+
+// stat returns the stat of path, cached until ClearStatCache or Execute
+func (uc *RenameUseCase) stat(path string) (domain.FileStat, error) {
+	uc.statMu.Lock()
+	stat, ok := uc.statCache[path]
+	uc.statMu.Unlock()
+	if ok {
+		return stat, nil
+	}
+
+	stat, err := uc.fileSystem.Stat(path)
+	if err != nil {
+		return domain.FileStat{}, err
+	}
+
+	uc.statMu.Lock()
+	uc.statCache[path] = stat
+	uc.statMu.Unlock()
+	return stat, nil
+}
+
+// ClearStatCache forgets cached stats
+// Call it when files may have changed outside the app (e.g. when a new selection is loaded)
+func (uc *RenameUseCase) ClearStatCache() {
+	uc.statMu.Lock()
+	uc.statCache = make(map[string]domain.FileStat)
+	uc.statMu.Unlock()
+}
+
+// SortFiles orders files in place (stable, so ties keep selection order)
+func (uc *RenameUseCase) SortFiles(files []*domain.File, order domain.FileOrder, descending bool) error {
+	stats := make(map[*domain.File]domain.FileStat, len(files))
+	if order.NeedsStat() {
+		for _, file := range files {
+			stat, err := uc.stat(file.OriginalPath())
+			if err != nil {
+				return fmt.Errorf("Failed to read %s: %v", file.OriginalName(), err)
+			}
+			stats[file] = stat
+		}
+	}
+
+	var less func(a, b *domain.File) bool
+	switch order {
+	case domain.OrderSelection:
+		if descending {
+			slices.Reverse(files)
+		}
+		return nil
+	case domain.OrderName:
+		less = func(a, b *domain.File) bool { return a.OriginalName() < b.OriginalName() }
+	case domain.OrderNatural:
+		less = func(a, b *domain.File) bool { return domain.NaturalLess(a.OriginalName(), b.OriginalName()) }
+	case domain.OrderModTime:
+		less = func(a, b *domain.File) bool { return stats[a].ModTime.Before(stats[b].ModTime) }
+	case domain.OrderSize:
+		less = func(a, b *domain.File) bool { return stats[a].Size < stats[b].Size }
+	default:
+		return fmt.Errorf("unknown file order: %q", order)
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		if descending {
+			return less(files[j], files[i])
+		}
+		return less(files[i], files[j])
+	})
+	return nil
+}
+
+// Preview applies the strategy, flags duplicate contents, resolves collisions within the batch and
+// conflicts with existing files, and checks the resulting names against the target platforms, so the user sees the final name before executing
+// Each item gets a status; items with an error status (see BlockingItems) must be fixed before executing
+func (uc *RenameUseCase) Preview(files []*domain.File, strategy domain.RenameStrategy) []PreviewItem {
+	metadata := uc.applyStrategy(files, strategy)
+	duplicates := uc.markDuplicates(files)
+	collisions := uc.resolveBatchCollisions(files)
+	uc.shareDuplicateNames(files)
+
+	sources := uc.batchSources(files)
+	items := make([]PreviewItem, len(files))
+	for i, file := range files {
+		items[i] = PreviewItem{
+			File:         file,
+			ResolvedName: file.NewName(),
+			Metadata:     metadata[i],
+			DuplicateOf:  file.DuplicateOf(),
+			Duplicates:   duplicates[file.OriginalPath()],
+			Collisions:   collisions[file.OriginalPath()],
+		}
+		if file.DuplicateOf() != "" {
+			items[i].DuplicateAction = uc.duplicates.Action
+		}
+
+		if !uc.targetTaken(file, sources) {
+			continue
+		}
+
+		items[i].Conflict = true
+		items[i].Action = uc.conflictPolicy
+		switch uc.conflictPolicy {
+		case domain.ConflictSuffix:
+			if name, err := uc.findAvailableName(file); err == nil {
+				items[i].ResolvedName = name
+			}
+		case domain.ConflictSkip, domain.ConflictFail:
+			items[i].ResolvedName = file.OriginalName()
+		}
+	}
+
+	// Only names created by the rename are checked (existing names are the user's business)
+	for i := range items {
+		if items[i].ResolvedName != items[i].File.OriginalName() {
+			items[i].Issues = domain.ValidateName(items[i].ResolvedName, uc.platforms)
+		}
+	}
+	uc.setStatuses(items)
+
+	return items
+}
+
 // Execute performs the actual file renaming
-// Skips files on error (as per requirements)
-func (uc *RenameUseCase) Execute(files []*domain.File) RenameResult {
-    result := RenameResult{
-        Errors:       make([]string, 0),
-        NewFilePaths: make([]string, 0),
-    }
-
-    const maxRetries = 1000
-
-    for _, file := range files {
-        // Skip files that haven't changed
-        if !file.HasChanged() {
-            // Keep original path for unchanged files
-            result.NewFilePaths = append(result.NewFilePaths, file.OriginalPath())
-            continue
-        }
-
-        // If target path already exists, find an available name by adding numeric suffix
-        targetPath := file.NewPath()
-        if targetPath != file.OriginalPath() && uc.fileSystem.FileExists(targetPath) {
-            // Split name and extension, then try base+1, base+2, ...
-            ext := filepath.Ext(file.NewName())
-            base := strings.TrimSuffix(file.NewName(), ext)
-
-            found := false
-            for i := 1; i < maxRetries; i++ {
-                candidateName := base + strconv.Itoa(i) + ext
-                candidatePath := filepath.Join(file.Directory(), candidateName)
-                if !uc.fileSystem.FileExists(candidatePath) {
-                    // Update file's new name to resolved unique name
-                    file.SetNewName(candidateName)
-                    targetPath = candidatePath
-                    found = true
-                    break
-                }
-            }
-
-            if !found {
-                result.FailureCount++
-                errorMsg := fmt.Sprintf("Failed to find available name for %s after %d retries", file.OriginalName(), maxRetries)
-                result.Errors = append(result.Errors, errorMsg)
-                // Keep original path for failed files
-                result.NewFilePaths = append(result.NewFilePaths, file.OriginalPath())
-                continue
-            }
-        }
-
-        err := uc.fileSystem.RenameFile(file.OriginalPath(), targetPath)
-        if err != nil {
-            result.FailureCount++
-            errorMsg := fmt.Sprintf("Failed to rename %s: %v", file.OriginalName(), err)
-            result.Errors = append(result.Errors, errorMsg)
-            // Keep original path for failed files
-            result.NewFilePaths = append(result.NewFilePaths, file.OriginalPath())
-            // Continue to next file (skip on error)
-            continue
-        }
-
-        result.SuccessCount++
-        // Add new path for successfully renamed files
-        result.NewFilePaths = append(result.NewFilePaths, file.NewPath())
-    }
-
-    return result
+// Skips files on error (as per requirements), unless transactional where a failure reverts the batch
+// Swaps, permutations and shifted sequences within the batch land exactly as previewed
+// Directories can be renamed together with their contents
+// Duplicates flagged by Preview under the trash action are moved to the trash first (not recorded for undo)
+// Cancelling ctx stops between files: the files not reached are counted as skipped and the result is marked cancelled
+func (uc *RenameUseCase) Execute(ctx context.Context, files []*domain.File) RenameResult {
+	result := RenameResult{
+		Errors:       make([]string, 0),
+		NewFilePaths: make([]string, 0),
+		Operations:   make([]domain.RenameOperation, 0),
+		TrashedPaths: make([]string, 0),
+	}
+
+	// The fail policy aborts the whole batch before touching anything
+	if uc.conflictPolicy == domain.ConflictFail {
+		conflicts := uc.findConflicts(files)
+		groups := uc.collisionGroups(files)
+		if len(conflicts) > 0 || len(groups) > 0 {
+			result.Aborted = true
+			result.FailureCount = len(conflicts)
+			for _, file := range conflicts {
+				result.Errors = append(result.Errors, fmt.Sprintf("Conflict: %s already exists (batch aborted)", file.NewName()))
+			}
+			for _, group := range groups {
+				result.FailureCount += len(group)
+				result.Errors = append(result.Errors, fmt.Sprintf("Conflict: %d files would be named %s (batch aborted)", len(group), group[0].NewName()))
+			}
+			for _, file := range files {
+				result.NewFilePaths = append(result.NewFilePaths, file.OriginalPath())
+			}
+			return result
+		}
+	}
+
+	// Build one move per changed file (unchanged and trashed files are skipped)
+	moves := make([]renameMove, 0, len(files))
+	moveFiles := make([]*domain.File, 0, len(files))
+	trashCount := 0
+	for _, file := range files {
+		if uc.trashes(file) {
+			trashCount++
+			continue
+		}
+		if !file.HasChanged() {
+			continue
+		}
+		moves = append(moves, renameMove{source: file.OriginalPath(), target: file.NewPath()})
+		moveFiles = append(moveFiles, file)
+	}
+
+	done, total := 0, trashCount+len(moves)
+	notify := func(path string) {
+		done++
+		if uc.renameProgress != nil {
+			uc.renameProgress(done, total, path)
+		}
+	}
+
+	// Trash duplicates so their names are free for the moves
+	trashErrors := make(map[*domain.File]error)
+	trashStopped := false // A trash failed (or the context was cancelled) in a transactional batch
+	trashSkipped := false // Some duplicates were not reached
+	for _, file := range files {
+		if !uc.trashes(file) {
+			continue
+		}
+		if ctx.Err() != nil || (uc.transactional && trashStopped) {
+			trashErrors[file] = errNotAttempted
+			trashStopped = uc.transactional
+			trashSkipped = true
+			continue
+		}
+		trashErrors[file] = uc.trashFile(file)
+		trashStopped = trashStopped || (uc.transactional && trashErrors[file] != nil)
+		notify(file.OriginalPath())
+	}
+	if trashStopped {
+		result.Cancelled = ctx.Err() != nil
+		return uc.abortAfterTrash(files, trashErrors, result)
+	}
+
+	renamer := newBatchRenamer(uc.fileSystem, func(index int, target string) (string, error) {
+		return uc.resolveConflict(moveFiles[index], uc.conflictPolicy)
+	})
+	// Cancellation stops between files; a transactional batch also stops at the first failure
+	renamer.stop = func() bool { return ctx.Err() != nil || (uc.transactional && renamer.failed) }
+	renamer.progress = func(index int) { notify(moves[index].source) }
+	outcomes := renamer.run(moves)
+
+	stoppedEarly := slices.ContainsFunc(outcomes, func(outcome moveOutcome) bool {
+		return errors.Is(outcome.err, errNotAttempted)
+	})
+	result.Cancelled = ctx.Err() != nil && (stoppedEarly || trashSkipped)
+
+	// A failed or cancelled transactional batch is reverted (the rollback itself is not cancellable)
+	var undone *rollback
+	if uc.transactional && (renamer.failed || stoppedEarly) {
+		undone = uc.rollbackMoves(renamer, outcomes)
+		result.RolledBack = true
+	}
+	// Renamed files have new paths and change times
+	uc.ClearStatCache()
+
+	// Report results in the original file order
+	next := 0
+	for _, file := range files {
+		if uc.trashes(file) {
+			result.NewFilePaths = append(result.NewFilePaths, file.OriginalPath())
+			if err := trashErrors[file]; errors.Is(err, errNotAttempted) {
+				result.SkippedCount++
+				continue
+			} else if err != nil {
+				result.FailureCount++
+				result.Errors = append(result.Errors, err.Error())
+				continue
+			}
+			result.TrashedCount++
+			result.TrashedPaths = append(result.TrashedPaths, file.OriginalPath())
+			continue
+		}
+
+		if !file.HasChanged() {
+			// Keep original path for unchanged files (following a renamed parent folder)
+			path := renamer.currentPath(file.OriginalPath(), outcomes)
+			if undone != nil {
+				path = undone.currentPath(path)
+			}
+			result.NewFilePaths = append(result.NewFilePaths, path)
+			continue
+		}
+
+		index := next
+		outcome := outcomes[index]
+		next++
+
+		if undone != nil {
+			revert, moved := undone.revert(index)
+			switch {
+			case !moved:
+				outcome.path = undone.currentPath(outcome.path)
+			case revert.err != nil:
+				result.RollbackErrors = append(result.RollbackErrors, revert.err.Error())
+				outcome.path = revert.path
+			case outcome.err == nil:
+				// Renamed, then moved back
+				result.RolledBackCount++
+				result.NewFilePaths = append(result.NewFilePaths, revert.path)
+				continue
+			default:
+				outcome.path = revert.path
+			}
+		}
+
+		if errors.Is(outcome.err, errSkipped) || errors.Is(outcome.err, errNotAttempted) {
+			result.SkippedCount++
+			result.NewFilePaths = append(result.NewFilePaths, outcome.path)
+			continue
+		}
+
+		if outcome.err != nil {
+			result.FailureCount++
+			result.Errors = append(result.Errors, outcome.err.Error())
+			// Keep original path for failed files
+			result.NewFilePaths = append(result.NewFilePaths, outcome.path)
+			continue
+		}
+
+		result.SuccessCount++
+		// Add new path for successfully renamed files
+		result.NewFilePaths = append(result.NewFilePaths, outcome.path)
+	}
+
+	// Record the performed renames (including suffix resolution) in execution order for undo
+	for _, index := range renamer.completed {
+		operation := domain.RenameOperation{OldPath: outcomes[index].source, NewPath: outcomes[index].path}
+		if undone != nil {
+			revert, _ := undone.revert(index)
+			if revert.err == nil {
+				continue
+			}
+			// Still renamed; its folder may have been moved back
+			operation = domain.RenameOperation{OldPath: undone.currentPath(operation.OldPath), NewPath: revert.path}
+		}
+		result.Operations = append(result.Operations, operation)
+	}
+
+	return result
+}
+
+// abortAfterTrash reports a transactional batch stopped by a failed (or cancelled) trash before any rename
+func (uc *RenameUseCase) abortAfterTrash(files []*domain.File, trashErrors map[*domain.File]error, result RenameResult) RenameResult {
+	result.Aborted = true
+	for _, file := range files {
+		err, trashed := trashErrors[file]
+		switch {
+		case !trashed:
+			result.NewFilePaths = append(result.NewFilePaths, file.OriginalPath())
+		case errors.Is(err, errNotAttempted):
+			result.SkippedCount++
+			result.NewFilePaths = append(result.NewFilePaths, file.OriginalPath())
+		case err != nil:
+			result.FailureCount++
+			result.Errors = append(result.Errors, err.Error())
+			result.NewFilePaths = append(result.NewFilePaths, file.OriginalPath())
+		default:
+			result.TrashedCount++
+			result.TrashedPaths = append(result.TrashedPaths, file.OriginalPath())
+			result.NewFilePaths = append(result.NewFilePaths, file.OriginalPath())
+		}
+	}
+	return result
+}
+
+// findConflicts returns changed files whose target exists outside the batch
+func (uc *RenameUseCase) findConflicts(files []*domain.File) []*domain.File {
+	sources := uc.batchSources(files)
+	conflicts := make([]*domain.File, 0)
+	for _, file := range files {
+		if uc.targetTaken(file, sources) {
+			conflicts = append(conflicts, file)
+		}
+	}
+	return conflicts
+}
+
+// targetTaken reports whether the new path of file is occupied by a file outside the batch
+// A target that is the file itself (case-only change on a case-insensitive file system) is not taken
+func (uc *RenameUseCase) targetTaken(file *domain.File, sources map[string]bool) bool {
+	if !file.HasChanged() || sources[file.NewPath()] || !uc.fileSystem.FileExists(file.NewPath()) {
+		return false
+	}
+	return !uc.fileSystem.SameFile(file.OriginalPath(), file.NewPath())
+}
+
+// resolveConflict applies policy when the target of file already exists
+// Returns the path to rename to, or errSkipped
+func (uc *RenameUseCase) resolveConflict(file *domain.File, policy domain.ConflictPolicy) (string, error) {
+	switch policy {
+	case domain.ConflictOverwrite:
+		return file.NewPath(), nil
+	case domain.ConflictSkip:
+		return "", errSkipped
+	case domain.ConflictFail:
+		// Only reached when the target appeared during execution
+		return "", fmt.Errorf("Conflict: %s already exists", file.NewName())
+	case domain.ConflictPrompt:
+		if uc.prompt == nil {
+			return "", errSkipped
+		}
+		choice := uc.prompt(file, file.NewPath())
+		if choice == domain.ConflictPrompt {
+			choice = domain.ConflictSkip
+		}
+		return uc.resolveConflict(file, choice)
+	}
+
+	name, err := uc.findAvailableName(file)
+	if err != nil {
+		return "", err
+	}
+	// Update file's new name to resolved unique name
+	file.SetNewName(name)
+	return file.NewPath(), nil
+}
+
+// findAvailableName finds a free name by adding the suffix template with increasing counters
+func (uc *RenameUseCase) findAvailableName(file *domain.File) (string, error) {
+	start := uc.suffix.Start()
+	for i := start; i < start+maxRetries; i++ {
+		candidateName := uc.suffix.Apply(file.NewName(), i)
+		if file.IsDir() {
+			candidateName = uc.suffix.ApplyToDirectory(file.NewName(), i)
+		}
+		if !uc.fileSystem.FileExists(filepath.Join(file.Directory(), candidateName)) {
+			return candidateName, nil
+		}
+	}
+
+	return "", fmt.Errorf("Failed to find available name for %s after %d retries", file.OriginalName(), maxRetries)
+}
+
+// batchSources returns the paths of files that will be vacated by the batch (renamed or trashed)
+func (uc *RenameUseCase) batchSources(files []*domain.File) map[string]bool {
+	sources := make(map[string]bool, len(files))
+	for _, file := range files {
+		if file.HasChanged() || uc.trashes(file) {
+			sources[file.OriginalPath()] = true
+		}
+	}
+	return sources
 }
@@ -1,8 +1,16 @@
 package usecase
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"rename/internal/domain"
 
@@ -25,6 +33,21 @@ func (m *MockFileSystemService) FileExists(path string) bool {
     return args.Bool(0)
 }
 
+func (m *MockFileSystemService) SameFile(path1, path2 string) bool {
+	args := m.Called(path1, path2)
+	return args.Bool(0)
+}
+
+func (m *MockFileSystemService) CanRename(path string) error {
+	args := m.Called(path)
+	return args.Error(0)
+}
+
+func (m *MockFileSystemService) Stat(path string) (domain.FileStat, error) {
+	args := m.Called(path)
+	return args.Get(0).(domain.FileStat), args.Error(1)
+}
+
 func TestRenameUseCase_GeneratePreview(t *testing.T) {
 	mockFS := new(MockFileSystemService)
 	useCase := NewRenameUseCase(mockFS)
@@ -65,7 +88,7 @@ func TestRenameUseCase_Execute_Success(t *testing.T) {
     mockFS.On("RenameFile", "/path/to/test1.txt", "/path/to/renamed1.txt").Return(nil)
     mockFS.On("RenameFile", "/path/to/test2.txt", "/path/to/renamed2.txt").Return(nil)
 
-	result := useCase.Execute(files)
+	result := useCase.Execute(context.Background(), files)
 
 	assert.Equal(t, 2, result.SuccessCount)
 	assert.Equal(t, 0, result.FailureCount)
@@ -73,6 +96,10 @@ func TestRenameUseCase_Execute_Success(t *testing.T) {
 	assert.Equal(t, 2, len(result.NewFilePaths))
 	assert.Equal(t, "/path/to/renamed1.txt", result.NewFilePaths[0])
 	assert.Equal(t, "/path/to/renamed2.txt", result.NewFilePaths[1])
+	assert.Equal(t, []domain.RenameOperation{
+		{OldPath: "/path/to/test1.txt", NewPath: "/path/to/renamed1.txt"},
+		{OldPath: "/path/to/test2.txt", NewPath: "/path/to/renamed2.txt"},
+	}, result.Operations)
 
 	mockFS.AssertExpectations(t)
 }
@@ -100,7 +127,7 @@ func TestRenameUseCase_Execute_SkipOnError(t *testing.T) {
     mockFS.On("RenameFile", "/path/to/test2.txt", "/path/to/renamed2.txt").Return(errors.New("permission denied"))
     mockFS.On("RenameFile", "/path/to/test3.txt", "/path/to/renamed3.txt").Return(nil)
 
-	result := useCase.Execute(files)
+	result := useCase.Execute(context.Background(), files)
 
 	assert.Equal(t, 2, result.SuccessCount)
 	assert.Equal(t, 1, result.FailureCount)
@@ -111,6 +138,7 @@ func TestRenameUseCase_Execute_SkipOnError(t *testing.T) {
 	assert.Equal(t, "/path/to/renamed1.txt", result.NewFilePaths[0])
 	assert.Equal(t, "/path/to/test2.txt", result.NewFilePaths[1]) // Failed, keeps original
 	assert.Equal(t, "/path/to/renamed3.txt", result.NewFilePaths[2])
+	assert.Equal(t, 2, len(result.Operations)) // Failed file is not recorded
 
 	mockFS.AssertExpectations(t)
 }
@@ -134,7 +162,7 @@ func TestRenameUseCase_Execute_SkipUnchangedFiles(t *testing.T) {
     // Only renamed file should be processed
     mockFS.On("RenameFile", "/path/to/test.txt", "/path/to/renamed.txt").Return(nil)
 
-	result := useCase.Execute(files)
+	result := useCase.Execute(context.Background(), files)
 
 	assert.Equal(t, 1, result.SuccessCount)
 	assert.Equal(t, 0, result.FailureCount)
@@ -161,16 +189,19 @@ func TestRenameUseCase_Execute_ConflictSuffix(t *testing.T) {
     mockFS.On("FileExists", "/path/to/renamed.txt").Return(true)
     mockFS.On("FileExists", "/path/to/renamed1.txt").Return(true)
     mockFS.On("FileExists", "/path/to/renamed2.txt").Return(false)
+	mockFS.On("SameFile", "/path/to/test.txt", "/path/to/renamed.txt").Return(false)
 
     // Expect rename to the resolved name with suffix 2
     mockFS.On("RenameFile", "/path/to/test.txt", "/path/to/renamed2.txt").Return(nil)
 
-    result := useCase.Execute(files)
+	result := useCase.Execute(context.Background(), files)
 
     assert.Equal(t, 1, result.SuccessCount)
     assert.Equal(t, 0, result.FailureCount)
     assert.Equal(t, 1, len(result.NewFilePaths))
     assert.Equal(t, "/path/to/renamed2.txt", result.NewFilePaths[0])
+	// Journal must record the resolved name, not the previewed one
+	assert.Equal(t, "/path/to/renamed2.txt", result.Operations[0].NewPath)
 
     mockFS.AssertExpectations(t)
 }
@@ -189,11 +220,12 @@ func TestRenameUseCase_Execute_ConflictMaxRetries(t *testing.T) {
     // Simulate all possible names being taken (up to maxRetries = 1000)
     // Initial check: renamed.txt exists
     mockFS.On("FileExists", "/path/to/renamed.txt").Return(true)
+	mockFS.On("SameFile", "/path/to/test.txt", "/path/to/renamed.txt").Return(false)
 
     // Mock FileExists to always return true for any path (all names taken)
     mockFS.On("FileExists", mock.AnythingOfType("string")).Return(true)
 
-    result := useCase.Execute(files)
+	result := useCase.Execute(context.Background(), files)
 
     // Should fail because all names are taken
     assert.Equal(t, 0, result.SuccessCount)
@@ -217,7 +249,7 @@ func TestRenameUseCase_Execute_SameNameRename(t *testing.T) {
     strategy := domain.NewExactMatchStrategy("test", "test")
     useCase.GeneratePreview(files, strategy)
 
-    result := useCase.Execute(files)
+	result := useCase.Execute(context.Background(), files)
 
     // Should skip because file hasn't changed
     assert.Equal(t, 0, result.SuccessCount)
@@ -240,7 +272,7 @@ func TestRenameUseCase_Execute_EmptyPattern(t *testing.T) {
     strategy := domain.NewExactMatchStrategy("", "")
     useCase.GeneratePreview(files, strategy)
 
-    result := useCase.Execute(files)
+	result := useCase.Execute(context.Background(), files)
 
     // File name becomes empty after replacement, but it's technically "changed"
     // However, the name is same ("" replaces to ""), so HasChanged should be false
@@ -262,7 +294,7 @@ func TestRenameUseCase_Execute_FileWithoutExtension(t *testing.T) {
     mockFS.On("FileExists", "/path/to/BUILDfile").Return(false)
     mockFS.On("RenameFile", "/path/to/Makefile", "/path/to/BUILDfile").Return(nil)
 
-    result := useCase.Execute(files)
+	result := useCase.Execute(context.Background(), files)
 
     assert.Equal(t, 1, result.SuccessCount)
     assert.Equal(t, 0, result.FailureCount)
@@ -270,3 +302,1146 @@ func TestRenameUseCase_Execute_FileWithoutExtension(t *testing.T) {
 
     mockFS.AssertExpectations(t)
 }
+
+func previewFiles(useCase *RenameUseCase, renames map[string]string, paths ...string) []*domain.File {
+	files := make([]*domain.File, len(paths))
+	for i, path := range paths {
+		files[i] = domain.NewFile(path)
+	}
+	useCase.GeneratePreview(files, renameMapStrategy(renames))
+	return files
+}
+
+// renameMapStrategy maps exact file names to new names
+type renameMapStrategy map[string]string
+
+func (s renameMapStrategy) Apply(filename string) string {
+	if newName, ok := s[filename]; ok {
+		return newName
+	}
+	return filename
+}
+
+func TestRenameUseCase_Execute_Swap(t *testing.T) {
+	fs := newFakeFileSystem(map[string]string{
+		"/dir/a.txt": "A",
+		"/dir/b.txt": "B",
+	})
+	useCase := NewRenameUseCase(fs)
+	files := previewFiles(useCase, map[string]string{"a.txt": "b.txt", "b.txt": "a.txt"}, "/dir/a.txt", "/dir/b.txt")
+
+	result := useCase.Execute(context.Background(), files)
+
+	assert.Equal(t, 2, result.SuccessCount)
+	assert.Equal(t, 0, result.FailureCount)
+	assert.Equal(t, map[string]string{"/dir/a.txt": "B", "/dir/b.txt": "A"}, fs.files)
+	assert.Equal(t, []string{"/dir/b.txt", "/dir/a.txt"}, result.NewFilePaths)
+}
+
+func TestRenameUseCase_Execute_Cycle(t *testing.T) {
+	fs := newFakeFileSystem(map[string]string{
+		"/dir/1.txt": "one",
+		"/dir/2.txt": "two",
+		"/dir/3.txt": "three",
+	})
+	useCase := NewRenameUseCase(fs)
+	files := previewFiles(useCase, map[string]string{"1.txt": "2.txt", "2.txt": "3.txt", "3.txt": "1.txt"},
+		"/dir/1.txt", "/dir/2.txt", "/dir/3.txt")
+
+	result := useCase.Execute(context.Background(), files)
+
+	assert.Equal(t, 3, result.SuccessCount)
+	assert.Equal(t, map[string]string{"/dir/2.txt": "one", "/dir/3.txt": "two", "/dir/1.txt": "three"}, fs.files)
+}
+
+func TestRenameUseCase_Execute_ShiftedSequence(t *testing.T) {
+	fs := newFakeFileSystem(map[string]string{
+		"/dir/file1.txt": "one",
+		"/dir/file2.txt": "two",
+	})
+	useCase := NewRenameUseCase(fs)
+	files := previewFiles(useCase, map[string]string{"file1.txt": "file2.txt", "file2.txt": "file3.txt"},
+		"/dir/file1.txt", "/dir/file2.txt")
+
+	result := useCase.Execute(context.Background(), files)
+
+	assert.Equal(t, 2, result.SuccessCount)
+	assert.Equal(t, map[string]string{"/dir/file2.txt": "one", "/dir/file3.txt": "two"}, fs.files)
+	assert.Equal(t, []string{"/dir/file2.txt", "/dir/file3.txt"}, result.NewFilePaths)
+	// Operations are recorded in execution order so undo can unwind them
+	assert.Equal(t, "/dir/file2.txt", result.Operations[0].OldPath)
+	assert.Equal(t, "/dir/file1.txt", result.Operations[1].OldPath)
+}
+
+func TestRenameUseCase_Execute_ChainWithFailedLink(t *testing.T) {
+	// file2.txt is missing: its own rename fails while file1.txt still lands on file2.txt
+	fs := newFakeFileSystem(map[string]string{
+		"/dir/file1.txt": "one",
+	})
+	useCase := NewRenameUseCase(fs)
+	files := previewFiles(useCase, map[string]string{"file1.txt": "file2.txt", "file2.txt": "file3.txt"},
+		"/dir/file1.txt", "/dir/file2.txt")
+
+	result := useCase.Execute(context.Background(), files)
+
+	assert.Equal(t, 1, result.SuccessCount)
+	assert.Equal(t, 1, result.FailureCount)
+	assert.Equal(t, map[string]string{"/dir/file2.txt": "one"}, fs.files)
+}
+
+func TestRenameUseCase_Execute_TransactionalRollback(t *testing.T) {
+	contents := map[string]string{
+		"/dir/a.txt":           "A",
+		"/dir/b.txt":           "B",
+		"/dir/Album/IMG_1.jpg": "photo",
+		"/dir/locked.txt":      "locked",
+		"/dir/z_last.txt":      "last",
+		"/other/unchanged.txt": "unchanged",
+	}
+	fs := newFakeFileSystem(contents)
+	fs.failFrom["/dir/locked.txt"] = true
+	useCase := NewRenameUseCase(fs)
+	useCase.SetTransactional(true)
+
+	// A swap, a folder renamed with its contents, a failing file and a file renamed after it
+	files := []*domain.File{
+		domain.NewFile("/dir/a.txt"),
+		domain.NewFile("/dir/b.txt"),
+		domain.NewDirectory("/dir/Album"),
+		domain.NewFile("/dir/Album/IMG_1.jpg"),
+		domain.NewFile("/dir/locked.txt"),
+		domain.NewFile("/dir/z_last.txt"),
+		domain.NewFile("/other/unchanged.txt"),
+	}
+	useCase.GeneratePreview(files, renameMapStrategy{
+		"a.txt": "b.txt", "b.txt": "a.txt", "Album": "album", "IMG_1.jpg": "1.jpg",
+		"locked.txt": "open.txt", "z_last.txt": "last.txt",
+	})
+
+	result := useCase.Execute(context.Background(), files)
+
+	// The batch stops at locked.txt: album is moved back, the swap (deferred), the photo
+	// inside the folder and z_last.txt are not attempted
+	assert.True(t, result.RolledBack)
+	assert.Equal(t, 0, result.SuccessCount)
+	assert.Equal(t, 1, result.FailureCount)
+	assert.Equal(t, 1, result.RolledBackCount)
+	assert.Equal(t, 4, result.SkippedCount)
+	assert.Empty(t, result.RollbackErrors)
+	assert.Empty(t, result.Operations)
+	assert.Equal(t, contents, fs.files)
+	for i, file := range files {
+		assert.Equal(t, file.OriginalPath(), result.NewFilePaths[i])
+	}
+}
+
+func TestRenameUseCase_Execute_TransactionalRollbackSwap(t *testing.T) {
+	contents := map[string]string{"/dir/a.txt": "A", "/dir/b.txt": "B", "/dir/sub/locked.txt": "locked"}
+	fs := newFakeFileSystem(contents)
+	fs.failFrom["/dir/sub/locked.txt"] = true
+	useCase := NewRenameUseCase(fs)
+	useCase.SetTransactional(true)
+
+	// The swap completes before the deeper file fails, and is undone through temporary names
+	files := previewFiles(useCase, map[string]string{"a.txt": "b.txt", "b.txt": "a.txt", "locked.txt": "open.txt"},
+		"/dir/a.txt", "/dir/b.txt", "/dir/sub/locked.txt")
+	result := useCase.Execute(context.Background(), files)
+
+	assert.True(t, result.RolledBack)
+	assert.Equal(t, 2, result.RolledBackCount)
+	assert.Equal(t, contents, fs.files)
+	assert.Empty(t, result.Operations)
+}
+
+func TestRenameUseCase_Execute_TransactionalRollbackError(t *testing.T) {
+	fs := newFakeFileSystem(map[string]string{"/dir/a.txt": "A", "/dir/locked.txt": "locked"})
+	fs.failFrom["/dir/locked.txt"] = true
+	// Renamed, but cannot be moved back
+	fs.failFrom["/dir/b.txt"] = true
+	useCase := NewRenameUseCase(fs)
+	useCase.SetTransactional(true)
+	files := previewFiles(useCase, map[string]string{"a.txt": "b.txt", "locked.txt": "open.txt"}, "/dir/a.txt", "/dir/locked.txt")
+
+	result := useCase.Execute(context.Background(), files)
+
+	assert.True(t, result.RolledBack)
+	assert.Equal(t, 1, result.SuccessCount)
+	assert.Equal(t, 1, result.FailureCount)
+	assert.Equal(t, 0, result.RolledBackCount)
+	assert.Len(t, result.RollbackErrors, 1)
+	assert.Contains(t, result.RollbackErrors[0], "b.txt")
+	assert.Equal(t, []string{"/dir/b.txt", "/dir/locked.txt"}, result.NewFilePaths)
+	// The rename that stayed can still be undone
+	assert.Equal(t, []domain.RenameOperation{{OldPath: "/dir/a.txt", NewPath: "/dir/b.txt"}}, result.Operations)
+}
+
+func TestRenameUseCase_Execute_TransactionalSuccess(t *testing.T) {
+	fs := newFakeFileSystem(map[string]string{"/dir/a.txt": "A", "/dir/b.txt": "B"})
+	useCase := NewRenameUseCase(fs)
+	useCase.SetTransactional(true)
+	files := previewFiles(useCase, map[string]string{"a.txt": "c.txt", "b.txt": "d.txt"}, "/dir/a.txt", "/dir/b.txt")
+
+	result := useCase.Execute(context.Background(), files)
+
+	assert.False(t, result.RolledBack)
+	assert.Equal(t, 2, result.SuccessCount)
+	assert.Len(t, result.Operations, 2)
+	assert.Equal(t, map[string]string{"/dir/c.txt": "A", "/dir/d.txt": "B"}, fs.files)
+}
+
+func TestRenameUseCase_Execute_Progress(t *testing.T) {
+	fs := newFakeFileSystem(map[string]string{"/dir/a.txt": "A", "/dir/b.txt": "B", "/dir/same.txt": "S"})
+	useCase := NewRenameUseCase(fs)
+	files := previewFiles(useCase, map[string]string{"a.txt": "c.txt", "b.txt": "d.txt"}, "/dir/a.txt", "/dir/b.txt", "/dir/same.txt")
+
+	type progress struct {
+		done, total int
+		path        string
+	}
+	var reported []progress
+	useCase.SetRenameProgressHandler(func(done, total int, path string) {
+		reported = append(reported, progress{done, total, path})
+	})
+	result := useCase.Execute(context.Background(), files)
+
+	// Unchanged files are not counted
+	assert.Equal(t, 2, result.SuccessCount)
+	assert.False(t, result.Cancelled)
+	assert.ElementsMatch(t, []progress{{1, 2, "/dir/a.txt"}, {2, 2, "/dir/b.txt"}}, reported)
+}
+
+func TestRenameUseCase_Execute_Cancelled(t *testing.T) {
+	fs := newFakeFileSystem(map[string]string{"/dir/a.txt": "A", "/dir/b.txt": "B", "/dir/c.txt": "C"})
+	useCase := NewRenameUseCase(fs)
+	files := previewFiles(useCase, map[string]string{"a.txt": "x.txt", "b.txt": "y.txt", "c.txt": "z.txt"},
+		"/dir/a.txt", "/dir/b.txt", "/dir/c.txt")
+
+	// Cancelled while the first file is reported: the batch stops before the next one
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	useCase.SetRenameProgressHandler(func(done, total int, path string) { cancel() })
+	result := useCase.Execute(ctx, files)
+
+	assert.True(t, result.Cancelled)
+	assert.Equal(t, 1, result.SuccessCount)
+	assert.Equal(t, 0, result.FailureCount)
+	assert.Equal(t, 2, result.SkippedCount)
+	assert.Len(t, result.Operations, 1)
+	assert.Len(t, fs.files, 3)
+}
+
+func TestRenameUseCase_Execute_CancelledTransactional(t *testing.T) {
+	contents := map[string]string{"/dir/a.txt": "A", "/dir/b.txt": "B", "/dir/c.txt": "C"}
+	fs := newFakeFileSystem(contents)
+	useCase := NewRenameUseCase(fs)
+	useCase.SetTransactional(true)
+	files := previewFiles(useCase, map[string]string{"a.txt": "x.txt", "b.txt": "y.txt", "c.txt": "z.txt"},
+		"/dir/a.txt", "/dir/b.txt", "/dir/c.txt")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	useCase.SetRenameProgressHandler(func(done, total int, path string) { cancel() })
+	result := useCase.Execute(ctx, files)
+
+	// What was renamed before the cancel is moved back
+	assert.True(t, result.Cancelled)
+	assert.True(t, result.RolledBack)
+	assert.Equal(t, 1, result.RolledBackCount)
+	assert.Empty(t, result.Operations)
+	assert.Equal(t, contents, fs.files)
+}
+
+func TestRenameUseCase_Execute_CancelledBeforeStart(t *testing.T) {
+	contents := map[string]string{"/dir/a.txt": "A", "/dir/b.txt": "B"}
+	fs := newFakeFileSystem(contents)
+	useCase := NewRenameUseCase(fs)
+	files := previewFiles(useCase, map[string]string{"a.txt": "x.txt", "b.txt": "y.txt"}, "/dir/a.txt", "/dir/b.txt")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result := useCase.Execute(ctx, files)
+
+	assert.True(t, result.Cancelled)
+	assert.Equal(t, 0, result.SuccessCount)
+	assert.Equal(t, 2, result.SkippedCount)
+	assert.Equal(t, contents, fs.files)
+}
+
+func TestRenameUseCase_Execute_CaseOnlyChange(t *testing.T) {
+	fs := newCaseInsensitiveFileSystem(map[string]string{
+		"/dir/photo.JPG":  "photo",
+		"/dir/README.MD":  "readme",
+		"/dir/other.jpg":  "other",
+		"/dir/Taken.jpeg": "existing",
+	})
+	useCase := NewRenameUseCase(fs)
+
+	files := []*domain.File{domain.NewFile("/dir/photo.JPG"), domain.NewFile("/dir/README.MD"), domain.NewFile("/dir/other.jpg")}
+	items := useCase.Preview(files, renameMapStrategy{"photo.JPG": "photo.jpg", "README.MD": "readme.md", "other.jpg": "taken.jpeg"})
+
+	// The file itself is not a conflict, but a different file with another spelling is
+	assert.False(t, items[0].Conflict)
+	assert.Equal(t, "photo.jpg", items[0].ResolvedName)
+	assert.False(t, items[1].Conflict)
+	assert.True(t, items[2].Conflict)
+	assert.Equal(t, "taken1.jpeg", items[2].ResolvedName)
+
+	result := useCase.Execute(context.Background(), files)
+
+	assert.Equal(t, 3, result.SuccessCount)
+	assert.Equal(t, map[string]string{
+		"/dir/photo.jpg":   "photo",
+		"/dir/readme.md":   "readme",
+		"/dir/taken1.jpeg": "other",
+		"/dir/Taken.jpeg":  "existing",
+	}, fs.files)
+	assert.Equal(t, []string{"/dir/photo.jpg", "/dir/readme.md", "/dir/taken1.jpeg"}, result.NewFilePaths)
+	assert.Equal(t, domain.RenameOperation{OldPath: "/dir/photo.JPG", NewPath: "/dir/photo.jpg"}, result.Operations[0])
+}
+
+func TestRenameUseCase_Execute_CaseOnlyChangeWithFailPolicy(t *testing.T) {
+	fs := newCaseInsensitiveFileSystem(map[string]string{
+		"/dir/IMG_0001.JPG": "photo",
+	})
+	useCase := NewRenameUseCase(fs)
+	options := domain.DefaultConflictOptions()
+	options.Policy = domain.ConflictFail
+	assert.NoError(t, useCase.SetConflictOptions(options))
+
+	files := previewFiles(useCase, map[string]string{"IMG_0001.JPG": "img_0001.jpg"}, "/dir/IMG_0001.JPG")
+	result := useCase.Execute(context.Background(), files)
+
+	assert.False(t, result.Aborted)
+	assert.Equal(t, 1, result.SuccessCount)
+	assert.Equal(t, map[string]string{"/dir/img_0001.jpg": "photo"}, fs.files)
+}
+
+func TestRenameUseCase_Execute_DirectoryWithContents(t *testing.T) {
+	fs := newFakeFileSystem(map[string]string{
+		"/root/Album 2024/IMG_1.jpg":           "one",
+		"/root/Album 2024/notes.txt":           "notes",
+		"/root/Album 2024/Raw Files/IMG_2.cr2": "raw",
+	})
+	useCase := NewRenameUseCase(fs)
+
+	// Children are listed before their parents: order must not matter
+	files := []*domain.File{
+		domain.NewFile("/root/Album 2024/Raw Files/IMG_2.cr2"),
+		domain.NewFile("/root/Album 2024/IMG_1.jpg"),
+		domain.NewFile("/root/Album 2024/notes.txt"),
+		domain.NewDirectory("/root/Album 2024/Raw Files"),
+		domain.NewDirectory("/root/Album 2024"),
+	}
+	items := useCase.Preview(files, renameMapStrategy{
+		"IMG_2.cr2":  "photo_2.cr2",
+		"IMG_1.jpg":  "photo_1.jpg",
+		"Raw Files":  "raw",
+		"Album 2024": "album-2024",
+	})
+	for _, item := range items {
+		assert.False(t, item.Conflict)
+	}
+
+	result := useCase.Execute(context.Background(), files)
+
+	assert.Equal(t, 4, result.SuccessCount)
+	assert.Equal(t, 0, result.FailureCount)
+	assert.Equal(t, map[string]string{
+		"/root/album-2024/photo_1.jpg":     "one",
+		"/root/album-2024/notes.txt":       "notes",
+		"/root/album-2024/raw/photo_2.cr2": "raw",
+	}, fs.files)
+	assert.Equal(t, []string{
+		"/root/album-2024/raw/photo_2.cr2",
+		"/root/album-2024/photo_1.jpg",
+		"/root/album-2024/notes.txt",
+		"/root/album-2024/raw",
+		"/root/album-2024",
+	}, result.NewFilePaths)
+	// Parents are renamed first; later operations use the paths valid at that point
+	assert.Equal(t, []domain.RenameOperation{
+		{OldPath: "/root/Album 2024", NewPath: "/root/album-2024"},
+		{OldPath: "/root/album-2024/IMG_1.jpg", NewPath: "/root/album-2024/photo_1.jpg"},
+		{OldPath: "/root/album-2024/Raw Files", NewPath: "/root/album-2024/raw"},
+		{OldPath: "/root/album-2024/raw/IMG_2.cr2", NewPath: "/root/album-2024/raw/photo_2.cr2"},
+	}, result.Operations)
+}
+
+func TestRenameUseCase_Preview_DirectoryNames(t *testing.T) {
+	fs := newFakeFileSystem(map[string]string{
+		"/root/v1.2/a.txt":    "",
+		"/root/v1.2_01/b.txt": "",
+	})
+	useCase := NewRenameUseCase(fs)
+	numbering, _ := domain.NewNumberingStrategy(nil, domain.NumberingOptions{Start: 1, Step: 1, Padding: 2, Position: domain.NumberSuffix, Separator: "_"})
+
+	items := useCase.Preview([]*domain.File{domain.NewDirectory("/root/v1.2")}, numbering)
+
+	// Directory names have no extension, and the conflict suffix goes at the end
+	assert.Equal(t, "v1.2_01", items[0].File.NewName())
+	assert.True(t, items[0].Conflict)
+	assert.Equal(t, "v1.2_011", items[0].ResolvedName)
+}
+
+func TestRenameUseCase_Preview_Issues(t *testing.T) {
+	fs := newFakeFileSystem(map[string]string{
+		"/dir/a.txt":   "",
+		"/dir/con.txt": "",
+		"/dir/b.txt":   "",
+	})
+	useCase := NewRenameUseCase(fs)
+	assert.Error(t, useCase.SetTargetPlatforms([]domain.Platform{"amiga"}))
+	assert.NoError(t, useCase.SetTargetPlatforms([]domain.Platform{domain.PlatformWindows}))
+
+	files := []*domain.File{domain.NewFile("/dir/a.txt"), domain.NewFile("/dir/con.txt"), domain.NewFile("/dir/b.txt")}
+	items := useCase.Preview(files, renameMapStrategy{"a.txt": "10:30.txt", "b.txt": "b2.txt"})
+
+	assert.Equal(t, []domain.NameIssue{{Platform: domain.PlatformWindows, Reason: `contains reserved characters ':'`}}, items[0].Issues)
+	// Unchanged names are not checked, even when invalid
+	assert.Nil(t, items[1].Issues)
+	assert.Nil(t, items[2].Issues)
+}
+
+func TestRenameUseCase_Preview_Statuses(t *testing.T) {
+	fs := newFakeFileSystem(map[string]string{
+		"/dir/rename.txt":   "",
+		"/dir/same.txt":     "",
+		"/dir/x_a.txt":      "",
+		"/dir/y_a.txt":      "",
+		"/dir/invalid.txt":  "",
+		"/dir/conflict.txt": "",
+		"/dir/taken.txt":    "",
+		"/locked/file.txt":  "",
+	})
+	fs.readOnly["/locked"] = true
+	useCase := NewRenameUseCase(fs)
+	assert.NoError(t, useCase.SetTargetPlatforms([]domain.Platform{domain.PlatformLinux}))
+
+	files := []*domain.File{
+		domain.NewFile("/dir/rename.txt"),
+		domain.NewFile("/dir/same.txt"),
+		domain.NewFile("/dir/x_a.txt"),
+		domain.NewFile("/dir/y_a.txt"),
+		domain.NewFile("/dir/invalid.txt"),
+		domain.NewFile("/dir/conflict.txt"),
+		domain.NewFile("/locked/file.txt"),
+		domain.NewFile("/dir/missing.txt"),
+	}
+	items := useCase.Preview(files, renameMapStrategy{
+		"rename.txt":   "renamed.txt",
+		"x_a.txt":      "a.txt",
+		"y_a.txt":      "a.txt",
+		"invalid.txt":  strings.Repeat("x", 256),
+		"conflict.txt": "taken.txt",
+		"file.txt":     "other.txt",
+		"missing.txt":  "found.txt",
+	})
+
+	expected := []struct {
+		status domain.FileStatus
+		detail string
+	}{
+		{domain.StatusWillRename, ""},
+		{domain.StatusUnchanged, ""},
+		{domain.StatusCollidesWithinBatch, "same new name as y_a.txt (suffix)"},
+		{domain.StatusCollidesWithinBatch, "same new name as x_a.txt (suffix)"},
+		{domain.StatusInvalidName, "invalid on linux: longer than 255 bytes (256)"},
+		{domain.StatusCollidesWithExisting, "taken.txt already exists (suffix)"},
+		{domain.StatusPermissionDenied, "file.txt cannot be renamed: permission denied"},
+		{domain.StatusSourceMissing, "missing.txt no longer exists"},
+	}
+	for i, want := range expected {
+		assert.Equal(t, want.status, items[i].Status, files[i].OriginalName())
+		assert.Equal(t, want.detail, items[i].Detail, files[i].OriginalName())
+	}
+
+	blocking := BlockingItems(items)
+	assert.Len(t, blocking, 3)
+	assert.Equal(t, "/dir/invalid.txt", blocking[0].File.OriginalPath())
+}
+
+func TestRenameUseCase_Preview_SourceDeletedAfterLoad(t *testing.T) {
+	fs := newFakeFileSystem(map[string]string{"/dir/a.txt": "A", "/dir/b.txt": "B"})
+	useCase := NewRenameUseCase(fs)
+	files := []*domain.File{domain.NewFile("/dir/a.txt"), domain.NewFile("/dir/b.txt")}
+	strategy := renameMapStrategy{"a.txt": "c.txt", "b.txt": "d.txt"}
+
+	// The first preview caches the stats of both files
+	items := useCase.Preview(files, strategy)
+	assert.Equal(t, domain.StatusWillRename, items[0].Status)
+
+	delete(fs.files, "/dir/a.txt")
+	items = useCase.Preview(files, strategy)
+
+	assert.Equal(t, domain.StatusSourceMissing, items[0].Status)
+	assert.Equal(t, domain.StatusWillRename, items[1].Status)
+	assert.Len(t, BlockingItems(items), 1)
+}
+
+func TestRenameUseCase_MissingSources(t *testing.T) {
+	fs := newFakeFileSystem(map[string]string{"/dir/a.txt": "A", "/dir/b.txt": "B", "/dir/same.txt": "S"})
+	useCase := NewRenameUseCase(fs)
+	files := previewFiles(useCase, map[string]string{"a.txt": "c.txt", "b.txt": "d.txt"}, "/dir/a.txt", "/dir/b.txt", "/dir/same.txt")
+	assert.Empty(t, useCase.MissingSources(files))
+
+	// Deleted after the preview; unchanged files do not matter
+	delete(fs.files, "/dir/b.txt")
+	delete(fs.files, "/dir/same.txt")
+
+	missing := useCase.MissingSources(files)
+	assert.Len(t, missing, 1)
+	assert.Equal(t, "/dir/b.txt", missing[0].OriginalPath())
+}
+
+func TestRenameUseCase_Preview_CaseInsensitiveBatchCollision(t *testing.T) {
+	fs := newFakeFileSystem(map[string]string{"/dir/a.txt": "", "/dir/b.txt": ""})
+	useCase := NewRenameUseCase(fs)
+	files := []*domain.File{domain.NewFile("/dir/a.txt"), domain.NewFile("/dir/b.txt")}
+	strategy := renameMapStrategy{"a.txt": "Photo.txt", "b.txt": "photo.txt"}
+
+	assert.NoError(t, useCase.SetTargetPlatforms([]domain.Platform{domain.PlatformLinux}))
+	items := useCase.Preview(files, strategy)
+	assert.Equal(t, domain.StatusWillRename, items[0].Status)
+	assert.Equal(t, domain.StatusWillRename, items[1].Status)
+
+	// Both names are the same file on macOS
+	assert.NoError(t, useCase.SetTargetPlatforms([]domain.Platform{domain.PlatformMacOS}))
+	items = useCase.Preview(files, strategy)
+	assert.Equal(t, domain.StatusCollidesWithinBatch, items[0].Status)
+	assert.Equal(t, domain.StatusCollidesWithinBatch, items[1].Status)
+}
+
+func TestRenameUseCase_Preview_BatchCollisions(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   domain.ConflictPolicy
+		existing bool // a.txt already exists outside the batch
+		resolved []string
+		files    map[string]string // Contents after Execute
+		aborted  bool
+	}{
+		{
+			name: "suffix", policy: domain.ConflictSuffix,
+			resolved: []string{"a.txt", "a1.txt", "a2.txt"},
+			files:    map[string]string{"/dir/a.txt": "X", "/dir/a1.txt": "Y", "/dir/a2.txt": "Z", "/dir/a3.txt": "other"},
+		},
+		{
+			name: "suffix with existing target", policy: domain.ConflictSuffix, existing: true,
+			resolved: []string{"a1.txt", "a2.txt", "a4.txt"},
+			files:    map[string]string{"/dir/a.txt": "existing", "/dir/a1.txt": "X", "/dir/a2.txt": "Y", "/dir/a4.txt": "Z", "/dir/a3.txt": "other"},
+		},
+		{
+			name: "skip", policy: domain.ConflictSkip,
+			resolved: []string{"a.txt", "y_a.txt", "z_a.txt"},
+			files:    map[string]string{"/dir/a.txt": "X", "/dir/y_a.txt": "Y", "/dir/z_a.txt": "Z", "/dir/a3.txt": "other"},
+		},
+		{
+			name: "fail", policy: domain.ConflictFail,
+			resolved: []string{"a.txt", "a.txt", "a.txt"},
+			files:    map[string]string{"/dir/x_a.txt": "X", "/dir/y_a.txt": "Y", "/dir/z_a.txt": "Z", "/dir/a3.txt": "other"},
+			aborted:  true,
+		},
+		{
+			name: "overwrite", policy: domain.ConflictOverwrite,
+			resolved: []string{"a.txt", "a.txt", "a.txt"},
+			files:    map[string]string{"/dir/a.txt": "Z", "/dir/a3.txt": "other"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contents := map[string]string{"/dir/x_a.txt": "X", "/dir/y_a.txt": "Y", "/dir/z_a.txt": "Z", "/dir/a3.txt": "other"}
+			if tt.existing {
+				contents["/dir/a.txt"] = "existing"
+			}
+			fs := newFakeFileSystem(contents)
+			useCase := NewRenameUseCase(fs)
+			assert.NoError(t, useCase.SetConflictOptions(domain.ConflictOptions{Policy: tt.policy, SuffixTemplate: "{n}", SuffixStart: 1}))
+
+			files := []*domain.File{domain.NewFile("/dir/x_a.txt"), domain.NewFile("/dir/y_a.txt"), domain.NewFile("/dir/z_a.txt")}
+			// a3.txt is taken, and is not part of the batch
+			items := useCase.Preview(files, renameMapStrategy{"x_a.txt": "a.txt", "y_a.txt": "a.txt", "z_a.txt": "a.txt"})
+
+			participants := []string{"/dir/x_a.txt", "/dir/y_a.txt", "/dir/z_a.txt"}
+			for i, item := range items {
+				assert.Equal(t, tt.resolved[i], item.ResolvedName, item.File.OriginalName())
+				assert.Equal(t, participants, item.Collisions)
+				assert.Equal(t, domain.StatusCollidesWithinBatch, item.Status)
+			}
+			assert.Empty(t, BlockingItems(items))
+
+			result := useCase.Execute(context.Background(), files)
+
+			assert.Equal(t, tt.aborted, result.Aborted)
+			assert.Equal(t, tt.files, fs.files)
+		})
+	}
+}
+
+func TestRenameUseCase_GeneratePreview_BatchCollisions(t *testing.T) {
+	fs := newFakeFileSystem(map[string]string{"/dir/x_a.txt": "X", "/dir/y_a.txt": "Y"})
+	useCase := NewRenameUseCase(fs)
+
+	files := []*domain.File{domain.NewFile("/dir/x_a.txt"), domain.NewFile("/dir/y_a.txt")}
+	useCase.GeneratePreview(files, renameMapStrategy{"x_a.txt": "a.txt", "y_a.txt": "a.txt"})
+
+	assert.Equal(t, "a.txt", files[0].NewName())
+	assert.Equal(t, "a1.txt", files[1].NewName())
+}
+
+func TestRenameUseCase_Preview_ResolvesConflicts(t *testing.T) {
+	fs := newFakeFileSystem(map[string]string{
+		"/dir/a.jpg":     "A",
+		"/dir/b.jpg":     "B",
+		"/dir/photo.jpg": "existing",
+	})
+	useCase := NewRenameUseCase(fs)
+	assert.NoError(t, useCase.SetConflictOptions(domain.ConflictOptions{
+		Policy:         domain.ConflictSuffix,
+		SuffixTemplate: " ({n})",
+		SuffixStart:    2,
+	}))
+
+	files := []*domain.File{domain.NewFile("/dir/a.jpg"), domain.NewFile("/dir/b.jpg")}
+	items := useCase.Preview(files, renameMapStrategy{"a.jpg": "photo.jpg", "b.jpg": "a.jpg"})
+
+	assert.True(t, items[0].Conflict)
+	assert.Equal(t, domain.ConflictSuffix, items[0].Action)
+	assert.Equal(t, "photo (2).jpg", items[0].ResolvedName)
+	// a.jpg is vacated by the batch, so it is not a conflict
+	assert.False(t, items[1].Conflict)
+	assert.Equal(t, "a.jpg", items[1].ResolvedName)
+
+	result := useCase.Execute(context.Background(), files)
+
+	assert.Equal(t, 2, result.SuccessCount)
+	assert.Equal(t, map[string]string{
+		"/dir/photo.jpg":     "existing",
+		"/dir/photo (2).jpg": "A",
+		"/dir/a.jpg":         "B",
+	}, fs.files)
+}
+
+func TestRenameUseCase_Execute_ConflictPolicies(t *testing.T) {
+	tests := []struct {
+		name            string
+		policy          domain.ConflictPolicy
+		expectedFiles   map[string]string
+		expectedSuccess int
+		expectedSkipped int
+		expectedFailure int
+		expectedAborted bool
+	}{
+		{
+			name:            "skip",
+			policy:          domain.ConflictSkip,
+			expectedFiles:   map[string]string{"/dir/taken.txt": "existing", "/dir/a.txt": "A", "/dir/free.txt": "B"},
+			expectedSuccess: 1,
+			expectedSkipped: 1,
+		},
+		{
+			name:            "fail",
+			policy:          domain.ConflictFail,
+			expectedFiles:   map[string]string{"/dir/taken.txt": "existing", "/dir/a.txt": "A", "/dir/b.txt": "B"},
+			expectedFailure: 1,
+			expectedAborted: true,
+		},
+		{
+			name:            "overwrite",
+			policy:          domain.ConflictOverwrite,
+			expectedFiles:   map[string]string{"/dir/taken.txt": "A", "/dir/free.txt": "B"},
+			expectedSuccess: 2,
+		},
+		{
+			name:            "prompt without callback skips",
+			policy:          domain.ConflictPrompt,
+			expectedFiles:   map[string]string{"/dir/taken.txt": "existing", "/dir/a.txt": "A", "/dir/free.txt": "B"},
+			expectedSuccess: 1,
+			expectedSkipped: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := newFakeFileSystem(map[string]string{
+				"/dir/a.txt":     "A",
+				"/dir/b.txt":     "B",
+				"/dir/taken.txt": "existing",
+			})
+			useCase := NewRenameUseCase(fs)
+			options := domain.DefaultConflictOptions()
+			options.Policy = tt.policy
+			assert.NoError(t, useCase.SetConflictOptions(options))
+
+			files := previewFiles(useCase, map[string]string{"a.txt": "taken.txt", "b.txt": "free.txt"}, "/dir/a.txt", "/dir/b.txt")
+			result := useCase.Execute(context.Background(), files)
+
+			assert.Equal(t, tt.expectedFiles, fs.files)
+			assert.Equal(t, tt.expectedSuccess, result.SuccessCount)
+			assert.Equal(t, tt.expectedSkipped, result.SkippedCount)
+			assert.Equal(t, tt.expectedFailure, result.FailureCount)
+			assert.Equal(t, tt.expectedAborted, result.Aborted)
+			assert.Equal(t, 2, len(result.NewFilePaths))
+		})
+	}
+}
+
+func TestRenameUseCase_Execute_ConflictPrompt(t *testing.T) {
+	fs := newFakeFileSystem(map[string]string{
+		"/dir/a.txt":     "A",
+		"/dir/taken.txt": "existing",
+	})
+	useCase := NewRenameUseCase(fs)
+	options := domain.DefaultConflictOptions()
+	options.Policy = domain.ConflictPrompt
+	assert.NoError(t, useCase.SetConflictOptions(options))
+
+	var prompted string
+	useCase.SetConflictPrompt(func(file *domain.File, existingPath string) domain.ConflictPolicy {
+		prompted = existingPath
+		return domain.ConflictSuffix
+	})
+
+	files := previewFiles(useCase, map[string]string{"a.txt": "taken.txt"}, "/dir/a.txt")
+	result := useCase.Execute(context.Background(), files)
+
+	assert.Equal(t, "/dir/taken.txt", prompted)
+	assert.Equal(t, 1, result.SuccessCount)
+	assert.Equal(t, "/dir/taken1.txt", result.NewFilePaths[0])
+}
+
+func TestRenameUseCase_SetConflictOptions_Invalid(t *testing.T) {
+	useCase := NewRenameUseCase(new(MockFileSystemService))
+
+	err := useCase.SetConflictOptions(domain.ConflictOptions{Policy: domain.ConflictSuffix, SuffixTemplate: "copy"})
+
+	assert.Error(t, err)
+}
+
+func TestRenameUseCase_GeneratePreview_Numbering(t *testing.T) {
+	useCase := NewRenameUseCase(new(MockFileSystemService))
+
+	files := []*domain.File{
+		domain.NewFile("/path/to/b.jpg"),
+		domain.NewFile("/path/to/a.jpg"),
+	}
+	strategy, _ := domain.NewNumberingStrategy(nil, domain.NumberingOptions{
+		Start: 1, Step: 1, Padding: 3, Position: domain.NumberPrefix, Separator: "_",
+	})
+
+	previews := useCase.GeneratePreview(files, strategy)
+
+	assert.Equal(t, "001_b.jpg", previews[0].NewName())
+	assert.Equal(t, "002_a.jpg", previews[1].NewName())
+}
+
+func TestRenameUseCase_SortFiles(t *testing.T) {
+	mockFS := new(MockFileSystemService)
+	useCase := NewRenameUseCase(mockFS)
+
+	newFiles := func() []*domain.File {
+		return []*domain.File{
+			domain.NewFile("/path/to/img10.jpg"),
+			domain.NewFile("/path/to/img2.jpg"),
+			domain.NewFile("/path/to/img1.jpg"),
+		}
+	}
+	names := func(files []*domain.File) []string {
+		result := make([]string, len(files))
+		for i, file := range files {
+			result[i] = file.OriginalName()
+		}
+		return result
+	}
+
+	mockFS.On("Stat", "/path/to/img10.jpg").Return(domain.FileStat{Size: 30, ModTime: testModTime.Add(2 * time.Hour)}, nil)
+	mockFS.On("Stat", "/path/to/img2.jpg").Return(domain.FileStat{Size: 10, ModTime: testModTime}, nil)
+	mockFS.On("Stat", "/path/to/img1.jpg").Return(domain.FileStat{Size: 20, ModTime: testModTime.Add(time.Hour)}, nil)
+
+	tests := []struct {
+		order      domain.FileOrder
+		descending bool
+		expected   []string
+	}{
+		{domain.OrderSelection, false, []string{"img10.jpg", "img2.jpg", "img1.jpg"}},
+		{domain.OrderSelection, true, []string{"img1.jpg", "img2.jpg", "img10.jpg"}},
+		{domain.OrderName, false, []string{"img1.jpg", "img10.jpg", "img2.jpg"}},
+		{domain.OrderNatural, false, []string{"img1.jpg", "img2.jpg", "img10.jpg"}},
+		{domain.OrderNatural, true, []string{"img10.jpg", "img2.jpg", "img1.jpg"}},
+		{domain.OrderModTime, false, []string{"img2.jpg", "img1.jpg", "img10.jpg"}},
+		{domain.OrderSize, true, []string{"img10.jpg", "img1.jpg", "img2.jpg"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.order), func(t *testing.T) {
+			files := newFiles()
+			err := useCase.SortFiles(files, tt.order, tt.descending)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, names(files))
+		})
+	}
+}
+
+func TestRenameUseCase_SortFiles_StatError(t *testing.T) {
+	mockFS := new(MockFileSystemService)
+	useCase := NewRenameUseCase(mockFS)
+
+	mockFS.On("Stat", "/path/to/missing.jpg").Return(domain.FileStat{}, errors.New("no such file"))
+
+	err := useCase.SortFiles([]*domain.File{domain.NewFile("/path/to/missing.jpg")}, domain.OrderSize, false)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "missing.jpg")
+}
+
+// fakeMetadataProvider returns fixed metadata per path
+type fakeMetadataProvider struct {
+	metadata map[string]domain.Metadata
+	calls    int
+}
+
+func (p *fakeMetadataProvider) Metadata(path string, stat domain.FileStat) (domain.Metadata, error) {
+	p.calls++
+	metadata, ok := p.metadata[path]
+	if !ok {
+		return nil, errors.New("no metadata")
+	}
+	return metadata, nil
+}
+
+func TestRenameUseCase_Preview_Metadata(t *testing.T) {
+	fs := newFakeFileSystem(map[string]string{
+		"/photos/IMG_0001.JPG": "",
+		"/photos/IMG_0002.JPG": "",
+	})
+	provider := &fakeMetadataProvider{metadata: map[string]domain.Metadata{
+		"/photos/IMG_0001.JPG": {
+			domain.FieldEXIFDate:  time.Date(2024, 5, 1, 14, 30, 15, 0, time.UTC),
+			domain.FieldEXIFModel: "X100V",
+		},
+	}}
+	useCase := NewRenameUseCase(fs)
+	useCase.SetMetadataProvider(provider)
+	strategy, err := domain.NewTemplateStrategy("{exif.date:20060102}_{exif.model}")
+	assert.NoError(t, err)
+
+	files := []*domain.File{domain.NewFile("/photos/IMG_0001.JPG"), domain.NewFile("/photos/IMG_0002.JPG")}
+	items := useCase.Preview(files, strategy)
+
+	assert.Equal(t, "20240501_X100V.JPG", items[0].ResolvedName)
+	assert.Equal(t, "X100V", items[0].Metadata[domain.FieldEXIFModel])
+	// Files without metadata keep their name
+	assert.Equal(t, "IMG_0002.JPG", items[1].ResolvedName)
+	assert.Nil(t, items[1].Metadata)
+
+	// Strategies without metadata fields do not read files
+	provider.calls = 0
+	items = useCase.Preview(files, domain.NewExactMatchStrategy("IMG", "photo"))
+	assert.Equal(t, 0, provider.calls)
+	assert.Nil(t, items[0].Metadata)
+}
+
+func TestRenameUseCase_GeneratePreview_FileTimes(t *testing.T) {
+	mockFS := new(MockFileSystemService)
+	useCase := NewRenameUseCase(mockFS)
+
+	birth := time.Date(2023, 12, 24, 18, 0, 0, 0, time.UTC)
+	mockFS.On("Stat", "/path/to/a.jpg").Return(domain.FileStat{ModTime: testModTime, ChangeTime: testModTime, BirthTime: birth}, nil).Once()
+	mockFS.On("Stat", "/path/to/b.jpg").Return(domain.FileStat{ModTime: testModTime}, nil).Once()
+
+	strategy, err := domain.NewTemplateStrategy("{btime:2006-01-02}{mtime:_20060102}_{stem}")
+	assert.NoError(t, err)
+	files := []*domain.File{domain.NewFile("/path/to/a.jpg"), domain.NewFile("/path/to/b.jpg")}
+
+	// Regenerating the preview reuses the cached stats (Once fails on a second Stat)
+	for i := 0; i < 3; i++ {
+		useCase.GeneratePreview(files, strategy)
+	}
+
+	assert.Equal(t, "2023-12-24_"+testModTime.Format("20060102")+"_a.jpg", files[0].NewName())
+	// Birth time is not available for b.jpg
+	assert.Equal(t, "_"+testModTime.Format("20060102")+"_b.jpg", files[1].NewName())
+	mockFS.AssertExpectations(t)
+
+	// Clearing the cache reads the stats again
+	useCase.ClearStatCache()
+	mockFS.On("Stat", "/path/to/a.jpg").Return(domain.FileStat{ModTime: testModTime.Add(24 * time.Hour)}, nil).Once()
+	mockFS.On("Stat", "/path/to/b.jpg").Return(domain.FileStat{ModTime: testModTime}, nil).Once()
+	useCase.GeneratePreview(files, strategy)
+	assert.Equal(t, testModTime.Add(24*time.Hour).Format("_20060102")+"_a.jpg", files[0].NewName())
+}
+
+// fakeHasher returns the file content as its digest and counts calls
+type fakeHasher struct {
+	fs    *fakeFileSystem
+	calls atomic.Int32
+}
+
+func (h *fakeHasher) Hash(path string, algorithm domain.HashAlgorithm, stat domain.FileStat) (string, error) {
+	h.calls.Add(1)
+	content, err := h.fs.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(algorithm) + "-" + string(content), nil
+}
+
+func TestRenameUseCase_Preview_Hash(t *testing.T) {
+	files := make(map[string]string)
+	paths := make([]*domain.File, 0)
+	for i := 0; i < 250; i++ {
+		path := fmt.Sprintf("/photos/IMG_%04d.jpg", i)
+		files[path] = fmt.Sprintf("%04d", i)
+		paths = append(paths, domain.NewFile(path))
+	}
+	fs := newFakeFileSystem(files)
+	hasher := &fakeHasher{fs: fs}
+
+	var mu sync.Mutex
+	reported := make([]int, 0)
+	useCase := NewRenameUseCase(fs)
+	useCase.SetContentHasher(hasher)
+	useCase.SetProgressHandler(func(done, total int) {
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, 250, total)
+		reported = append(reported, done)
+	})
+
+	strategy, err := domain.NewHashStrategy(domain.HashOptions{Algorithm: domain.HashXXH64, KeepStem: true, Separator: "_"})
+	assert.NoError(t, err)
+
+	items := useCase.Preview(paths, strategy)
+
+	assert.Equal(t, "IMG_0000_xxh64-0000.jpg", items[0].ResolvedName)
+	assert.Equal(t, "IMG_0249_xxh64-0249.jpg", items[249].ResolvedName)
+	assert.Equal(t, int32(250), hasher.calls.Load())
+	// Progress is reported in order, ending with the total
+	assert.True(t, slices.IsSorted(reported))
+	assert.Equal(t, 250, reported[len(reported)-1])
+	assert.LessOrEqual(t, len(reported), 130)
+
+	// Strategies that do not read contents report no progress
+	reported = reported[:0]
+	useCase.Preview(paths, domain.NewExactMatchStrategy("IMG", "photo"))
+	assert.Empty(t, reported)
+}
+
+// fakeTrash removes files from a fake file system and records them
+type fakeTrash struct {
+	fs      *fakeFileSystem
+	trashed []string
+}
+
+func (t *fakeTrash) Trash(path string) error {
+	if _, ok := t.fs.files[path]; !ok {
+		return os.ErrNotExist
+	}
+	delete(t.fs.files, path)
+	t.trashed = append(t.trashed, path)
+	return nil
+}
+
+func TestRenameUseCase_Preview_Duplicates(t *testing.T) {
+	contents := map[string]string{
+		"/photos/a.jpg": "same",
+		"/photos/b.jpg": "other",
+		"/photos/c.jpg": "same",
+		"/photos/d.jpg": "same",
+		"/photos/e.jpg": "four", // Same size as "same", different contents
+	}
+	newFiles := func() []*domain.File {
+		return []*domain.File{
+			domain.NewFile("/photos/a.jpg"),
+			domain.NewFile("/photos/b.jpg"),
+			domain.NewFile("/photos/c.jpg"),
+			domain.NewFile("/photos/d.jpg"),
+			domain.NewFile("/photos/e.jpg"),
+		}
+	}
+	strategy, err := domain.NewPatternStrategy(".jpg", "_trip.jpg", false, false, false)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		action   domain.DuplicateAction
+		expected []string
+	}{
+		{"keep", domain.DuplicateKeep, []string{"a_trip.jpg", "b_trip.jpg", "c_trip.jpg", "d_trip.jpg", "e_trip.jpg"}},
+		{"skip", domain.DuplicateSkip, []string{"a_trip.jpg", "b_trip.jpg", "c.jpg", "d.jpg", "e_trip.jpg"}},
+		{"trash", domain.DuplicateTrash, []string{"a_trip.jpg", "b_trip.jpg", "c.jpg", "d.jpg", "e_trip.jpg"}},
+		{"shared", domain.DuplicateShared, []string{"a_trip.jpg", "b_trip.jpg", "a_trip1.jpg", "a_trip2.jpg", "e_trip.jpg"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := newFakeFileSystem(contents)
+			hasher := &fakeHasher{fs: fs}
+			useCase := NewRenameUseCase(fs)
+			useCase.SetContentHasher(hasher)
+			assert.NoError(t, useCase.SetDuplicateOptions(domain.DuplicateOptions{
+				Detect: true, Algorithm: domain.HashSHA256, Action: tt.action,
+			}))
+
+			items := useCase.Preview(newFiles(), strategy)
+
+			names := make([]string, len(items))
+			for i, item := range items {
+				names[i] = item.ResolvedName
+			}
+			assert.Equal(t, tt.expected, names)
+			assert.Equal(t, []string{"/photos/c.jpg", "/photos/d.jpg"}, items[0].Duplicates)
+			assert.Equal(t, "/photos/a.jpg", items[2].DuplicateOf)
+			assert.Equal(t, tt.action, items[3].DuplicateAction)
+			assert.Empty(t, items[1].DuplicateOf)
+			assert.Empty(t, items[4].DuplicateOf)
+			assert.Empty(t, items[4].DuplicateAction)
+			// b.jpg has a unique size and is never hashed
+			assert.Equal(t, int32(4), hasher.calls.Load())
+		})
+	}
+}
+
+func TestRenameUseCase_Preview_SharedDuplicatesAfterCollisions(t *testing.T) {
+	contents := map[string]string{
+		"/photos/x.jpg": "x",
+		"/photos/a.jpg": "same",
+		"/photos/c.jpg": "same",
+		"/photos/y.jpg": "y",
+	}
+	tests := []struct {
+		name     string
+		strategy renameMapStrategy
+		expected []string
+	}{
+		// a.jpg loses photo.jpg to x.jpg, so its duplicate follows the suffixed name
+		{"original suffixed", renameMapStrategy{"x.jpg": "photo.jpg", "a.jpg": "photo.jpg", "c.jpg": "c2.jpg"},
+			[]string{"photo.jpg", "photo1.jpg", "photo11.jpg", "y.jpg"}},
+		// photo1.jpg is the new name of y.jpg, so the duplicate takes the next suffix
+		{"shared name taken by the batch", renameMapStrategy{"a.jpg": "photo.jpg", "c.jpg": "c2.jpg", "y.jpg": "photo1.jpg"},
+			[]string{"x.jpg", "photo.jpg", "photo2.jpg", "photo1.jpg"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := newFakeFileSystem(contents)
+			useCase := NewRenameUseCase(fs)
+			useCase.SetContentHasher(&fakeHasher{fs: fs})
+			assert.NoError(t, useCase.SetDuplicateOptions(domain.DuplicateOptions{
+				Detect: true, Algorithm: domain.HashSHA256, Action: domain.DuplicateShared,
+			}))
+			files := []*domain.File{
+				domain.NewFile("/photos/x.jpg"),
+				domain.NewFile("/photos/a.jpg"),
+				domain.NewFile("/photos/c.jpg"),
+				domain.NewFile("/photos/y.jpg"),
+			}
+
+			items := useCase.Preview(files, tt.strategy)
+
+			names := make([]string, len(items))
+			for i, item := range items {
+				names[i] = item.ResolvedName
+				assert.False(t, item.Status.IsError(), item.File.OriginalName())
+			}
+			assert.Equal(t, tt.expected, names)
+			assert.Equal(t, "/photos/a.jpg", items[2].DuplicateOf)
+			assert.Empty(t, items[2].Collisions)
+		})
+	}
+}
+
+func TestRenameUseCase_Preview_DuplicatesDisabled(t *testing.T) {
+	fs := newFakeFileSystem(map[string]string{"/a.txt": "same", "/b.txt": "same"})
+	hasher := &fakeHasher{fs: fs}
+	useCase := NewRenameUseCase(fs)
+	useCase.SetContentHasher(hasher)
+	strategy, err := domain.NewPatternStrategy("txt", "md", false, false, false)
+	assert.NoError(t, err)
+
+	items := useCase.Preview([]*domain.File{domain.NewFile("/a.txt"), domain.NewFile("/b.txt")}, strategy)
+
+	assert.Equal(t, "b.md", items[1].ResolvedName)
+	assert.Empty(t, items[1].DuplicateOf)
+	assert.Equal(t, int32(0), hasher.calls.Load())
+}
+
+func TestRenameUseCase_Execute_TrashDuplicates(t *testing.T) {
+	fs := newFakeFileSystem(map[string]string{
+		"/photos/a.jpg": "same",
+		"/photos/b.jpg": "same",
+		"/photos/c.jpg": "other",
+	})
+	trash := &fakeTrash{fs: fs}
+	useCase := NewRenameUseCase(fs)
+	useCase.SetContentHasher(&fakeHasher{fs: fs})
+	useCase.SetTrash(trash)
+	assert.NoError(t, useCase.SetDuplicateOptions(domain.DuplicateOptions{
+		Detect: true, Algorithm: domain.HashSHA256, Action: domain.DuplicateTrash,
+	}))
+
+	// c.jpg takes the name of the trashed duplicate
+	files := []*domain.File{
+		domain.NewFile("/photos/a.jpg"),
+		domain.NewFile("/photos/b.jpg"),
+		domain.NewFile("/photos/c.jpg"),
+	}
+	strategy, err := domain.NewPatternStrategy("c.jpg", "b.jpg", false, false, false)
+	assert.NoError(t, err)
+	items := useCase.Preview(files, strategy)
+	assert.False(t, items[2].Conflict)
+
+	result := useCase.Execute(context.Background(), files)
+
+	assert.Equal(t, 1, result.SuccessCount)
+	assert.Equal(t, 1, result.TrashedCount)
+	assert.Equal(t, 0, result.FailureCount)
+	assert.Equal(t, []string{"/photos/b.jpg"}, result.TrashedPaths)
+	assert.Equal(t, []string{"/photos/b.jpg"}, trash.trashed)
+	assert.Equal(t, map[string]string{"/photos/a.jpg": "same", "/photos/b.jpg": "other"}, fs.files)
+	// Trashing cannot be undone through the journal
+	assert.Equal(t, []domain.RenameOperation{{OldPath: "/photos/c.jpg", NewPath: "/photos/b.jpg"}}, result.Operations)
+}
+
+func TestRenameUseCase_Execute_TrashUnavailable(t *testing.T) {
+	fs := newFakeFileSystem(map[string]string{"/a.txt": "same", "/b.txt": "same"})
+	useCase := NewRenameUseCase(fs)
+	useCase.SetContentHasher(&fakeHasher{fs: fs})
+	assert.NoError(t, useCase.SetDuplicateOptions(domain.DuplicateOptions{
+		Detect: true, Algorithm: domain.HashMD5, Action: domain.DuplicateTrash,
+	}))
+	files := []*domain.File{domain.NewFile("/a.txt"), domain.NewFile("/b.txt")}
+	strategy, err := domain.NewCaseStrategy(domain.CaseUpper, domain.ScopeStem)
+	assert.NoError(t, err)
+	useCase.Preview(files, strategy)
+
+	result := useCase.Execute(context.Background(), files)
+
+	assert.Equal(t, 1, result.SuccessCount)
+	assert.Equal(t, 1, result.FailureCount)
+	assert.Contains(t, result.Errors[0], "Failed to move b.txt to the trash")
+	assert.Contains(t, fs.files, "/b.txt")
+}
+
+func TestRenameUseCase_Execute_TransactionalTrashUnavailable(t *testing.T) {
+	fs := newFakeFileSystem(map[string]string{"/a.txt": "same", "/b.txt": "same"})
+	useCase := NewRenameUseCase(fs)
+	useCase.SetContentHasher(&fakeHasher{fs: fs})
+	useCase.SetTransactional(true)
+	assert.NoError(t, useCase.SetDuplicateOptions(domain.DuplicateOptions{
+		Detect: true, Algorithm: domain.HashMD5, Action: domain.DuplicateTrash,
+	}))
+	files := []*domain.File{domain.NewFile("/a.txt"), domain.NewFile("/b.txt")}
+	strategy, err := domain.NewCaseStrategy(domain.CaseUpper, domain.ScopeStem)
+	assert.NoError(t, err)
+	useCase.Preview(files, strategy)
+
+	result := useCase.Execute(context.Background(), files)
+
+	// Nothing is renamed once the trash failed
+	assert.True(t, result.Aborted)
+	assert.Equal(t, 0, result.SuccessCount)
+	assert.Equal(t, 1, result.FailureCount)
+	assert.Equal(t, []string{"/a.txt", "/b.txt"}, result.NewFilePaths)
+	assert.Equal(t, map[string]string{"/a.txt": "same", "/b.txt": "same"}, fs.files)
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+)
+
+// rollback reverts a failed transactional batch
+// The completed moves (newest first) and the files left under temporary names are moved back
+// as a batch of their own, so swaps, cycles and renamed folders are undone the way they were done
+type rollback struct {
+	renamer  *batchRenamer
+	outcomes []moveOutcome
+	// reverts maps a move of the batch to its revert in outcomes
+	reverts map[int]int
+}
+
+// rollbackMoves moves the files of a failed batch back to where they were
+func (uc *RenameUseCase) rollbackMoves(renamer *batchRenamer, outcomes []moveOutcome) *rollback {
+	indexes := slices.Clone(renamer.completed)
+	slices.Reverse(indexes)
+	for i, outcome := range outcomes {
+		if outcome.err != nil && outcome.path != outcome.source {
+			indexes = append(indexes, i)
+		}
+	}
+
+	r := &rollback{reverts: make(map[int]int, len(indexes))}
+	moves := make([]renameMove, len(indexes))
+	for k, i := range indexes {
+		moves[k] = renameMove{source: outcomes[i].path, target: outcomes[i].source}
+		r.reverts[i] = k
+	}
+
+	// Whatever took an original name meanwhile is left alone
+	r.renamer = newBatchRenamer(uc.fileSystem, func(index int, target string) (string, error) {
+		return "", fmt.Errorf("Failed to restore %s: %s already exists", filepath.Base(moves[index].source), filepath.Base(target))
+	})
+	r.outcomes = r.renamer.run(moves)
+	return r
+}
+
+// revert returns the outcome of moving back the file of a batch move (false if it was not moved back)
+func (r *rollback) revert(index int) (moveOutcome, bool) {
+	k, ok := r.reverts[index]
+	if !ok {
+		return moveOutcome{}, false
+	}
+	return r.outcomes[k], true
+}
+
+// currentPath returns where path is after the rollback (files inside folders that were moved back)
+func (r *rollback) currentPath(path string) string {
+	return r.renamer.currentPath(path, r.outcomes)
+}
@@ -4,6 +4,11 @@ import (
 	"embed"
 	"os"
 
+	"rename/internal/cli"
+	"rename/internal/repository"
+	"rename/internal/service"
+	"rename/internal/usecase"
+
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/options/assetserver"
@@ -16,6 +21,11 @@ func main() {
 	// Get command-line arguments (excluding program name)
 	argsWithoutProg := os.Args[1:]
 
+	// Run headless when a subcommand is given (e.g. rename apply ...)
+	if cli.IsCommand(argsWithoutProg) {
+		os.Exit(runCLI(argsWithoutProg))
+	}
+
 	// Create an instance of the app structure
 	app := NewApp()
 
@@ -53,3 +63,16 @@ func main() {
 		println("Error:", err.Error())
 	}
 }
+
+// runCLI wires the use cases for headless mode and runs the subcommand
+func runCLI(args []string) int {
+	fileSystem := service.NewFileSystemService()
+	renameUseCase := usecase.NewRenameUseCase(fileSystem)
+	journalUseCase := usecase.NewJournalUseCase(repository.NewJSONJournalRepository(journalPath()), fileSystem)
+	folderUseCase := usecase.NewFolderUseCase(fileSystem)
+	renameUseCase.SetMetadataProvider(service.NewMetadataService())
+	renameUseCase.SetContentHasher(service.NewHashService())
+	renameUseCase.SetTrash(service.NewTrashService())
+
+	return cli.NewCLI(renameUseCase, journalUseCase, folderUseCase, os.Stdout, os.Stderr).Run(args)
+}